@@ -2,10 +2,13 @@ package client
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/li1213987842/spaceweave/proto"
 )
@@ -16,8 +19,37 @@ var (
 
 type DiskAllocatorClient interface {
 	Allocate(ctx context.Context, size uint64) (uint64, error)
+	AllocateWithPolicy(ctx context.Context, size uint64, policy pb.PlacementPolicy) (uint64, error)
+	AllocateAligned(ctx context.Context, size uint64, alignment uint64) (uint64, error)
+	AllocateWithTTL(ctx context.Context, size uint64, ttl time.Duration) (uint64, error)
+	// AllocateTiered 按服务端配置的分层分配，返回分配所在的存储池，释放时使用 Pool(tier)
+	AllocateTiered(ctx context.Context, size uint64) (tier string, address uint64, err error)
+	ExtendTTL(ctx context.Context, address uint64, ttl time.Duration) error
+	AllocateExtents(ctx context.Context, size uint64, maxExtents uint32, minExtentSize uint64) ([]*pb.Extent, error)
+	BatchAllocate(ctx context.Context, sizes []uint64) ([]uint64, []error, error)
+	BatchFree(ctx context.Context, extents []*pb.Extent) ([]error, error)
+	OpenAllocateStream(ctx context.Context) (*AllocateStream, error)
+	OpenSession(ctx context.Context, ttl time.Duration) (*Session, error)
+	ResumeSession(ctx context.Context, sessionID uint64) (*Session, error)
+	Reserve(ctx context.Context, address uint64, size uint64) error
+	Resize(ctx context.Context, address uint64, oldSize uint64, newSize uint64) (newAddress uint64, moved bool, err error)
 	Free(ctx context.Context, address uint64, size uint64) error
+	FreeExtents(ctx context.Context, extents []*pb.Extent) error
 	GetDiskUtilization(ctx context.Context) (float32, error)
+	Verify(ctx context.Context, repair bool) (*pb.VerifyResponse, error)
+	ExportMap(ctx context.Context) (*pb.AllocationMap, error)
+	ImportMap(ctx context.Context, m *pb.AllocationMap) (uint64, error)
+	Grow(ctx context.Context, totalSize uint64) (uint64, error)
+	Shrink(ctx context.Context, totalSize uint64) (*pb.ShrinkResponse, error)
+	CreatePool(ctx context.Context, req *pb.CreatePoolRequest) (*pb.PoolInfo, error)
+	DeletePool(ctx context.Context, poolID string, force bool) error
+	ListPools(ctx context.Context) ([]*pb.PoolInfo, error)
+	SetTenantQuota(ctx context.Context, tenantID string, softLimit, hardLimit, reserved uint64) (*pb.TenantUsage, error)
+	GetTenantUsage(ctx context.Context, tenantID string) ([]*pb.TenantUsage, error)
+	// Pool 返回操作另一个存储池的客户端，与当前客户端共用连接，关闭任意一个都会关闭连接
+	Pool(poolID string) DiskAllocatorClient
+	// Tenant 返回以 tenantID 身份分配的客户端，分配计入该租户的配额，与当前客户端共用连接
+	Tenant(tenantID string) DiskAllocatorClient
 	Close() error
 }
 
@@ -26,6 +58,8 @@ var _ DiskAllocatorClient = (*diskAllocatorClientImpl)(nil)
 type diskAllocatorClientImpl struct {
 	client pb.DiskAllocatorClient
 	conn   *grpc.ClientConn
+	pool   string // 为空时使用服务端的默认存储池
+	tenant string // 为空时不计租户配额
 }
 
 func NewDiskAllocatorClient(ctx context.Context, serverAddr string) (DiskAllocatorClient, error) {
@@ -52,25 +86,199 @@ func (c *diskAllocatorClientImpl) Close() error {
 	return c.conn.Close()
 }
 
+func (c *diskAllocatorClientImpl) Pool(poolID string) DiskAllocatorClient {
+	return &diskAllocatorClientImpl{client: c.client, conn: c.conn, pool: poolID, tenant: c.tenant}
+}
+
+func (c *diskAllocatorClientImpl) Tenant(tenantID string) DiskAllocatorClient {
+	return &diskAllocatorClientImpl{client: c.client, conn: c.conn, pool: c.pool, tenant: tenantID}
+}
+
 func (c *diskAllocatorClientImpl) Allocate(ctx context.Context, size uint64) (uint64, error) {
-	r, err := c.client.Allocate(ctx, &pb.AllocateRequest{Size: size})
+	return c.AllocateWithPolicy(ctx, size, pb.PlacementPolicy_POLICY_DEFAULT)
+}
+
+func (c *diskAllocatorClientImpl) AllocateWithPolicy(ctx context.Context, size uint64, policy pb.PlacementPolicy) (uint64, error) {
+	r, err := c.client.Allocate(ctx, &pb.AllocateRequest{Size: size, Policy: policy, PoolId: c.pool, TenantId: c.tenant})
+	if err != nil {
+		return 0, err
+	}
+	return r.Address, nil
+}
+
+func (c *diskAllocatorClientImpl) AllocateAligned(ctx context.Context, size uint64, alignment uint64) (uint64, error) {
+	r, err := c.client.Allocate(ctx, &pb.AllocateRequest{Size: size, Alignment: alignment, PoolId: c.pool, TenantId: c.tenant})
 	if err != nil {
 		return 0, err
 	}
 	return r.Address, nil
 }
 
+func (c *diskAllocatorClientImpl) AllocateWithTTL(ctx context.Context, size uint64, ttl time.Duration) (uint64, error) {
+	r, err := c.client.Allocate(ctx, &pb.AllocateRequest{Size: size, TtlSec: uint32(ttl / time.Second), PoolId: c.pool, TenantId: c.tenant})
+	if err != nil {
+		return 0, err
+	}
+	return r.Address, nil
+}
+
+func (c *diskAllocatorClientImpl) AllocateTiered(ctx context.Context, size uint64) (string, uint64, error) {
+	r, err := c.client.Allocate(ctx, &pb.AllocateRequest{Size: size, Tiered: true, TenantId: c.tenant})
+	if err != nil {
+		return "", 0, err
+	}
+	return r.Tier, r.Address, nil
+}
+
+func (c *diskAllocatorClientImpl) ExtendTTL(ctx context.Context, address uint64, ttl time.Duration) error {
+	_, err := c.client.ExtendTTL(ctx, &pb.ExtendTTLRequest{Address: address, TtlSec: uint32(ttl / time.Second), PoolId: c.pool})
+	return err
+}
+
+func (c *diskAllocatorClientImpl) AllocateExtents(ctx context.Context, size uint64, maxExtents uint32, minExtentSize uint64) ([]*pb.Extent, error) {
+	r, err := c.client.AllocateExtents(ctx, &pb.AllocateExtentsRequest{Size: size, MaxExtents: maxExtents, MinExtentSize: minExtentSize, PoolId: c.pool, TenantId: c.tenant})
+	if err != nil {
+		return nil, err
+	}
+	return r.Extents, nil
+}
+
+func (c *diskAllocatorClientImpl) FreeExtents(ctx context.Context, extents []*pb.Extent) error {
+	_, err := c.client.FreeExtents(ctx, &pb.FreeExtentsRequest{Extents: extents, PoolId: c.pool})
+	return err
+}
+
+// BatchAllocate 返回与 sizes 一一对应的地址和错误，最后一个返回值为整个 RPC 的错误
+func (c *diskAllocatorClientImpl) BatchAllocate(ctx context.Context, sizes []uint64) ([]uint64, []error, error) {
+	r, err := c.client.BatchAllocate(ctx, &pb.BatchAllocateRequest{Sizes: sizes, PoolId: c.pool, TenantId: c.tenant})
+	if err != nil {
+		return nil, nil, err
+	}
+	addresses := make([]uint64, len(r.Results))
+	errs := make([]error, len(r.Results))
+	for i, res := range r.Results {
+		addresses[i] = res.Address
+		errs[i] = fromItemStatus(res.Status)
+	}
+	return addresses, errs, nil
+}
+
+// BatchFree 返回与 extents 一一对应的错误，最后一个返回值为整个 RPC 的错误
+func (c *diskAllocatorClientImpl) BatchFree(ctx context.Context, extents []*pb.Extent) ([]error, error) {
+	r, err := c.client.BatchFree(ctx, &pb.BatchFreeRequest{Extents: extents, PoolId: c.pool})
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, len(r.Results))
+	for i, res := range r.Results {
+		errs[i] = fromItemStatus(res)
+	}
+	return errs, nil
+}
+
+func fromItemStatus(st *pb.ItemStatus) error {
+	if st == nil || codes.Code(st.Code) == codes.OK {
+		return nil
+	}
+	return status.Error(codes.Code(st.Code), st.Message)
+}
+
+func (c *diskAllocatorClientImpl) Reserve(ctx context.Context, address uint64, size uint64) error {
+	_, err := c.client.Reserve(ctx, &pb.ReserveRequest{Address: address, Size: size, PoolId: c.pool})
+	return err
+}
+
+func (c *diskAllocatorClientImpl) Resize(ctx context.Context, address uint64, oldSize uint64, newSize uint64) (uint64, bool, error) {
+	r, err := c.client.Resize(ctx, &pb.ResizeRequest{Address: address, OldSize: oldSize, NewSize: newSize, PoolId: c.pool, TenantId: c.tenant})
+	if err != nil {
+		return 0, false, err
+	}
+	return r.Address, r.Moved, nil
+}
+
 func (c *diskAllocatorClientImpl) Free(ctx context.Context, address uint64, size uint64) error {
-	_, err := c.client.Free(ctx, &pb.FreeRequest{Address: address, Size: size})
+	_, err := c.client.Free(ctx, &pb.FreeRequest{Address: address, Size: size, PoolId: c.pool})
 	return err
 }
 
 func (c *diskAllocatorClientImpl) GetDiskUtilization(ctx context.Context) (float32, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	res, err := c.client.GetDiskUtilization(ctx, &pb.GetDiskUtilizationRequest{})
+	res, err := c.client.GetDiskUtilization(ctx, &pb.GetDiskUtilizationRequest{PoolId: c.pool})
 	if err != nil {
 		return 0, err
 	}
 	return res.Utilization, nil
 }
+
+func (c *diskAllocatorClientImpl) Verify(ctx context.Context, repair bool) (*pb.VerifyResponse, error) {
+	return c.client.Verify(ctx, &pb.VerifyRequest{Repair: repair, PoolId: c.pool})
+}
+
+// ExportMap 不限制响应大小，分配图的大小随碎片程度增长
+func (c *diskAllocatorClientImpl) ExportMap(ctx context.Context) (*pb.AllocationMap, error) {
+	res, err := c.client.ExportMap(ctx, &pb.ExportMapRequest{PoolId: c.pool}, grpc.MaxCallRecvMsgSize(math.MaxInt32))
+	if err != nil {
+		return nil, err
+	}
+	return res.Map, nil
+}
+
+func (c *diskAllocatorClientImpl) ImportMap(ctx context.Context, m *pb.AllocationMap) (uint64, error) {
+	res, err := c.client.ImportMap(ctx, &pb.ImportMapRequest{Map: m, PoolId: c.pool})
+	if err != nil {
+		return 0, err
+	}
+	return res.Allocations, nil
+}
+
+func (c *diskAllocatorClientImpl) Grow(ctx context.Context, totalSize uint64) (uint64, error) {
+	res, err := c.client.Grow(ctx, &pb.GrowRequest{TotalSize: totalSize, PoolId: c.pool})
+	if err != nil {
+		return 0, err
+	}
+	return res.TotalSize, nil
+}
+
+func (c *diskAllocatorClientImpl) Shrink(ctx context.Context, totalSize uint64) (*pb.ShrinkResponse, error) {
+	return c.client.Shrink(ctx, &pb.ShrinkRequest{TotalSize: totalSize, PoolId: c.pool})
+}
+
+func (c *diskAllocatorClientImpl) CreatePool(ctx context.Context, req *pb.CreatePoolRequest) (*pb.PoolInfo, error) {
+	res, err := c.client.CreatePool(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Pool, nil
+}
+
+func (c *diskAllocatorClientImpl) DeletePool(ctx context.Context, poolID string, force bool) error {
+	_, err := c.client.DeletePool(ctx, &pb.DeletePoolRequest{PoolId: poolID, Force: force})
+	return err
+}
+
+func (c *diskAllocatorClientImpl) ListPools(ctx context.Context) ([]*pb.PoolInfo, error) {
+	res, err := c.client.ListPools(ctx, &pb.ListPoolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Pools, nil
+}
+
+// SetTenantQuota 设置租户的软硬配额和预留空间，全部为 0 时删除
+func (c *diskAllocatorClientImpl) SetTenantQuota(ctx context.Context, tenantID string, softLimit, hardLimit, reserved uint64) (*pb.TenantUsage, error) {
+	res, err := c.client.SetTenantQuota(ctx, &pb.SetTenantQuotaRequest{PoolId: c.pool, TenantId: tenantID, SoftLimit: softLimit, HardLimit: hardLimit, Reserved: reserved})
+	if err != nil {
+		return nil, err
+	}
+	return res.Tenant, nil
+}
+
+// GetTenantUsage 返回租户的配额和用量，tenantID 为空时返回全部租户
+func (c *diskAllocatorClientImpl) GetTenantUsage(ctx context.Context, tenantID string) ([]*pb.TenantUsage, error) {
+	res, err := c.client.GetTenantUsage(ctx, &pb.GetTenantUsageRequest{PoolId: c.pool, TenantId: tenantID})
+	if err != nil {
+		return nil, err
+	}
+	return res.Tenants, nil
+}
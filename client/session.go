@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+// Session 是服务端的一个租约会话。会话期间的分配需要 Commit 才会成为永久分配，
+// Close 或连接断开后未提交的分配会被服务端释放
+type Session struct {
+	id     uint64
+	pool   string
+	tenant string
+	client pb.DiskAllocatorClient
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// OpenSession 打开一个新会话。会话随会话流的结束而结束：Close 或连接断开后服务端立即关闭会话并释放未提交的分配。
+// ttl 为服务端收不到续期时会话保留的时间，例如服务端重启后等待 ResumeSession
+func (c *diskAllocatorClientImpl) OpenSession(ctx context.Context, ttl time.Duration) (*Session, error) {
+	return c.openSession(ctx, &pb.OpenSessionRequest{TtlSec: uint32(ttl / time.Second), PoolId: c.pool})
+}
+
+// ResumeSession 在服务端重启后重新绑定一个已有会话，重启前的会话由快照和 WAL 恢复，ttl 内没有重新绑定的会话会被关闭。
+// 客户端连接断开时会话已被关闭，不能再恢复
+func (c *diskAllocatorClientImpl) ResumeSession(ctx context.Context, sessionID uint64) (*Session, error) {
+	return c.openSession(ctx, &pb.OpenSessionRequest{SessionId: sessionID, PoolId: c.pool})
+}
+
+func (c *diskAllocatorClientImpl) openSession(ctx context.Context, req *pb.OpenSessionRequest) (*Session, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.OpenSession(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	event, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, errors.WithMessage(err, "open session")
+	}
+
+	s := &Session{
+		id:     event.SessionId,
+		pool:   c.pool,
+		tenant: c.tenant,
+		client: c.client,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+		}
+	}()
+	return s, nil
+}
+
+func (s *Session) ID() uint64 {
+	return s.id
+}
+
+// Done 在会话流结束时关闭，此后未提交的分配已不再受保护
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Session) Allocate(ctx context.Context, size uint64) (uint64, error) {
+	r, err := s.client.Allocate(ctx, &pb.AllocateRequest{Size: size, SessionId: s.id, PoolId: s.pool, TenantId: s.tenant})
+	if err != nil {
+		return 0, err
+	}
+	return r.Address, nil
+}
+
+func (s *Session) Commit(ctx context.Context, address uint64, size uint64) error {
+	_, err := s.client.Commit(ctx, &pb.CommitRequest{SessionId: s.id, Address: address, Size: size, PoolId: s.pool})
+	return err
+}
+
+// Close 结束会话，服务端会释放所有未提交的分配
+func (s *Session) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+var (
+	ErrStreamClosed = errors.New("allocate stream closed")
+)
+
+// Future 是流上一条命令的结果，命令的响应到达或流关闭后完成
+type Future struct {
+	done    chan struct{}
+	address uint64
+	err     error
+}
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+func (f *Future) complete(address uint64, err error) {
+	f.address = address
+	f.err = err
+	close(f.done)
+}
+
+// Done 返回在结果可用时关闭的 channel
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 等待结果，返回分配到的地址（free 命令为 0）和错误
+func (f *Future) Wait(ctx context.Context) (uint64, error) {
+	select {
+	case <-f.done:
+		return f.address, f.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// AllocateStream 封装 AllocateStream 双向流，允许在收到响应前连续发送多条命令
+type AllocateStream struct {
+	stream pb.DiskAllocator_AllocateStreamClient
+	cancel context.CancelFunc
+	pool   string
+	tenant string
+
+	sendMu sync.Mutex // gRPC 流不允许并发 Send
+
+	mu      sync.Mutex // 保护 nextID、pending 和 err，不在 Send 期间持有，避免阻塞接收
+	nextID  uint64
+	pending map[uint64]*Future
+	err     error // 流结束的原因，非 nil 后不再接受新命令
+
+	recvDone chan struct{}
+}
+
+func (c *diskAllocatorClientImpl) OpenAllocateStream(ctx context.Context) (*AllocateStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.AllocateStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s := &AllocateStream{
+		stream:   stream,
+		cancel:   cancel,
+		pool:     c.pool,
+		tenant:   c.tenant,
+		pending:  make(map[uint64]*Future),
+		recvDone: make(chan struct{}),
+	}
+	go s.recvLoop()
+	return s, nil
+}
+
+// Allocate 发送一条分配命令，不等待结果
+func (s *AllocateStream) Allocate(size uint64) (*Future, error) {
+	return s.send(&pb.StreamRequest{Command: &pb.StreamRequest_Allocate{Allocate: &pb.AllocateRequest{Size: size, PoolId: s.pool, TenantId: s.tenant}}})
+}
+
+// Free 发送一条释放命令，不等待结果
+func (s *AllocateStream) Free(address uint64, size uint64) (*Future, error) {
+	return s.send(&pb.StreamRequest{Command: &pb.StreamRequest_Free{Free: &pb.FreeRequest{Address: address, Size: size, PoolId: s.pool}}})
+}
+
+func (s *AllocateStream) send(req *pb.StreamRequest) (*Future, error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, s.err
+	}
+	s.nextID++
+	req.Id = s.nextID
+	f := newFuture()
+	s.pending[req.Id] = f
+	s.mu.Unlock()
+
+	s.sendMu.Lock()
+	err := s.stream.Send(req)
+	s.sendMu.Unlock()
+	if err != nil {
+		s.mu.Lock()
+		delete(s.pending, req.Id)
+		s.mu.Unlock()
+		return nil, errors.WithMessage(err, "send stream request")
+	}
+	return f, nil
+}
+
+func (s *AllocateStream) recvLoop() {
+	defer close(s.recvDone)
+	for {
+		resp, err := s.stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = ErrStreamClosed
+			}
+			s.failPending(err)
+			return
+		}
+
+		s.mu.Lock()
+		f, ok := s.pending[resp.Id]
+		delete(s.pending, resp.Id)
+		s.mu.Unlock()
+		if ok {
+			f.complete(resp.Address, fromItemStatus(resp.Status))
+		}
+	}
+}
+
+func (s *AllocateStream) failPending(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+	for id, f := range s.pending {
+		f.complete(0, err)
+		delete(s.pending, id)
+	}
+}
+
+// Close 结束发送并等待所有已发送命令的结果返回
+func (s *AllocateStream) Close() error {
+	s.sendMu.Lock()
+	err := s.stream.CloseSend()
+	s.sendMu.Unlock()
+
+	<-s.recvDone
+	s.cancel()
+	return err
+}
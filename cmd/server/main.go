@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net"
 	"os"
 	"os/signal"
@@ -16,7 +17,6 @@ import (
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/li1213987842/spaceweave/config"
-	"github.com/li1213987842/spaceweave/internal/allocator"
 	"github.com/li1213987842/spaceweave/service"
 )
 
@@ -29,7 +29,10 @@ func main() {
 
 	log.Println("load config info:", string(cinfo), err)
 	service.ServConfig = cfg
-	service.AllocatorStore = allocator.NewDiskAllocator(service.ServConfig)
+	service.Pools, err = service.NewPoolRegistry(service.ServConfig)
+	if err != nil {
+		panic(fmt.Sprintf("load pools fail: %v", err))
+	}
 
 	runService(service.ServConfig)
 }
@@ -44,6 +47,8 @@ func runService(cfg *config.Config) {
 			Time:                  7200 * time.Second,
 			Timeout:               20 * time.Second,
 		}),
+		// ImportMap 的请求包含完整的分配图，大小随碎片程度增长
+		grpc.MaxRecvMsgSize(math.MaxInt32),
 	)
 	//TODO tls...
 
@@ -75,9 +80,9 @@ func runService(cfg *config.Config) {
 			grpcServer.GracefulStop()
 			log.Println("gRPC service stopped")
 
-			log.Println("Closing allocator store")
-			service.AllocatorStore.Close()
-			log.Println("Allocator store closed")
+			log.Println("Closing pools")
+			service.Pools.Close()
+			log.Println("Pools closed")
 			wg.Done()
 		}()
 	}()
@@ -0,0 +1,317 @@
+// spaceweave-admin 通过管理接口操作运行中的服务，服务地址默认取 SPACE_WEAVE_ADDR。
+//
+//	spaceweave-admin [-addr host:port] [-pool id] verify [-repair]
+//	spaceweave-admin [-addr host:port] [-pool id] export [-format json|csv] [-o file]
+//	spaceweave-admin [-addr host:port] [-pool id] import [-format json|csv] <file>
+//	spaceweave-admin [-addr host:port] [-pool id] grow <total-bytes>
+//	spaceweave-admin [-addr host:port] [-pool id] shrink <total-bytes>
+//	spaceweave-admin [-addr host:port] [-pool id] quota [-soft bytes] [-hard bytes] [-reserve bytes] <tenant>
+//	spaceweave-admin [-addr host:port] [-pool id] tenants [tenant]
+//	spaceweave-admin [-addr host:port] pools
+//	spaceweave-admin [-addr host:port] create-pool [-unit-size n] [-path file] <id> <total-bytes>
+//	spaceweave-admin [-addr host:port] delete-pool [-force] <id>
+//
+// -pool 指定操作的存储池，默认为服务端的默认存储池。import 只能在没有存活分配的存储池上执行。
+// quota 设置租户在存储池中的配额和预留空间，全部为 0 时删除
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/li1213987842/spaceweave/client"
+	"github.com/li1213987842/spaceweave/config"
+	"github.com/li1213987842/spaceweave/internal/allocator"
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: spaceweave-admin [-addr host:port] [-pool id] verify [-repair]")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] [-pool id] export [-format json|csv] [-o file]")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] [-pool id] import [-format json|csv] <file>")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] [-pool id] grow <total-bytes>")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] [-pool id] shrink <total-bytes>")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] [-pool id] quota [-soft bytes] [-hard bytes] [-reserve bytes] <tenant>")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] [-pool id] tenants [tenant]")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] pools")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] create-pool [-unit-size n] [-path file] <id> <total-bytes>")
+	fmt.Fprintln(os.Stderr, "       spaceweave-admin [-addr host:port] delete-pool [-force] <id>")
+	os.Exit(2)
+}
+
+func main() {
+	addr := flag.String("addr", "", "server address (default: SPACE_WEAVE_ADDR)")
+	pool := flag.String("pool", "", "pool id (default: the server's default pool)")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+	}
+	if *addr == "" {
+		cfg, err := config.LoadConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load config from env fail: %v\n", err)
+			os.Exit(1)
+		}
+		*addr = cfg.SpaceWeaveAddr
+	}
+
+	ctx := context.Background()
+	c, err := client.NewDiskAllocatorClient(ctx, *addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer c.Close()
+	if *pool != "" {
+		c = c.Pool(*pool)
+	}
+
+	args := flag.Args()[1:]
+	switch flag.Arg(0) {
+	case "verify":
+		err = verify(ctx, c, args)
+	case "export":
+		err = exportMap(ctx, c, args)
+	case "import":
+		err = importMap(ctx, c, args)
+	case "grow":
+		err = grow(ctx, c, args)
+	case "shrink":
+		err = shrink(ctx, c, args)
+	case "quota":
+		err = setQuota(ctx, c, args)
+	case "tenants":
+		err = listTenants(ctx, c, args)
+	case "pools":
+		err = listPools(ctx, c)
+	case "create-pool":
+		err = createPool(ctx, c, args)
+	case "delete-pool":
+		err = deletePool(ctx, c, args)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func verify(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	repair := fs.Bool("repair", false, "rebuild treeBySize from treeByStart if the indexes disagree")
+	fs.Parse(args)
+
+	res, err := c.Verify(ctx, *repair)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d free blocks, %d free units in tree, %d free units in bitmap, utilization %.4f\n",
+		res.FreeBlocks, res.TreeFreeUnits, res.BitmapFreeUnits, res.Utilization)
+	for _, p := range res.Problems {
+		fmt.Printf("  %s: %s\n", p.Kind, p.Detail)
+	}
+	if res.Repaired {
+		fmt.Println("  rebuilt treeBySize")
+	}
+	if len(res.Problems) > 0 {
+		return fmt.Errorf("%d problems found", len(res.Problems))
+	}
+	return nil
+}
+
+func exportMap(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := fs.String("format", "json", "json or csv")
+	output := fs.String("o", "", "output file (default: stdout)")
+	fs.Parse(args)
+
+	format, err := allocator.ParseMapFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	m, err := c.ExportMap(ctx)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return allocator.WriteMap(os.Stdout, m, format)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := allocator.WriteMap(file, m, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func importMap(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := fs.String("format", "json", "json or csv")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	format, err := allocator.ParseMapFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	m, err := allocator.ReadMap(file, format)
+	if err != nil {
+		return err
+	}
+	n, err := c.ImportMap(ctx, m)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported %d allocations from %s\n", n, fs.Arg(0))
+	return nil
+}
+
+func parseTotalSize(args []string) (uint64, error) {
+	if len(args) != 1 {
+		usage()
+	}
+	totalSize, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid total size %q: %w", args[0], err)
+	}
+	return totalSize, nil
+}
+
+func grow(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	totalSize, err := parseTotalSize(args)
+	if err != nil {
+		return err
+	}
+	total, err := c.Grow(ctx, totalSize)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("managed space is now %d bytes\n", total)
+	return nil
+}
+
+func shrink(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	totalSize, err := parseTotalSize(args)
+	if err != nil {
+		return err
+	}
+	res, err := c.Shrink(ctx, totalSize)
+	if err != nil {
+		return err
+	}
+	if res.Done {
+		fmt.Printf("managed space is now %d bytes\n", res.TotalSize)
+		return nil
+	}
+	fmt.Printf("allocations above %d bytes are blocked, relocate these extents and run shrink again:\n", totalSize)
+	for _, extent := range res.Relocate {
+		fmt.Printf("  address %d size %d\n", extent.Address, extent.Size)
+	}
+	return fmt.Errorf("shrink pending: %d extents to relocate", len(res.Relocate))
+}
+
+func setQuota(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	fs := flag.NewFlagSet("quota", flag.ExitOnError)
+	soft := fs.Uint64("soft", 0, "soft limit in bytes, 0 for none")
+	hard := fs.Uint64("hard", 0, "hard limit in bytes, 0 for none")
+	reserve := fs.Uint64("reserve", 0, "bytes guaranteed to the tenant, 0 for none")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+	u, err := c.SetTenantQuota(ctx, fs.Arg(0), *soft, *hard, *reserve)
+	if err != nil {
+		return err
+	}
+	printTenant(u)
+	return nil
+}
+
+func listTenants(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	if len(args) > 1 {
+		usage()
+	}
+	var tenant string
+	if len(args) == 1 {
+		tenant = args[0]
+	}
+	tenants, err := c.GetTenantUsage(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	for _, u := range tenants {
+		printTenant(u)
+	}
+	return nil
+}
+
+func printTenant(u *pb.TenantUsage) {
+	fmt.Printf("%s\tused %d\tsoft limit %d\thard limit %d\treserved %d\n", u.TenantId, u.Used, u.SoftLimit, u.HardLimit, u.Reserved)
+}
+
+func listPools(ctx context.Context, c client.DiskAllocatorClient) error {
+	pools, err := c.ListPools(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range pools {
+		fmt.Printf("%s\tunit size %d\ttotal size %d\tutilization %.4f\tstate %q\n",
+			p.PoolId, p.UnitSize, p.TotalSize, p.Utilization, p.StatePersistencePath)
+	}
+	return nil
+}
+
+func createPool(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	fs := flag.NewFlagSet("create-pool", flag.ExitOnError)
+	unitSize := fs.Uint64("unit-size", 0, "unit size in bytes (default: the server's UNIT_SIZE)")
+	path := fs.String("path", "", "state persistence path (default: STATE_PERSISTENCE_PATH + \".\" + id)")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		usage()
+	}
+	totalSize, err := parseTotalSize(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	p, err := c.CreatePool(ctx, &pb.CreatePoolRequest{
+		PoolId:               fs.Arg(0),
+		UnitSize:             *unitSize,
+		TotalSize:            totalSize,
+		StatePersistencePath: *path,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created pool %s: unit size %d, total size %d, state %q\n", p.PoolId, p.UnitSize, p.TotalSize, p.StatePersistencePath)
+	return nil
+}
+
+func deletePool(ctx context.Context, c client.DiskAllocatorClient, args []string) error {
+	fs := flag.NewFlagSet("delete-pool", flag.ExitOnError)
+	force := fs.Bool("force", false, "delete the pool even if it still has allocations")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+	if err := c.DeletePool(ctx, fs.Arg(0), *force); err != nil {
+		return err
+	}
+	fmt.Printf("deleted pool %s, its state files are kept on disk\n", fs.Arg(0))
+	return nil
+}
@@ -0,0 +1,65 @@
+// spaceweave-fsck 离线检查状态文件中位图、空闲树和分配表的一致性，配置与服务端相同，从环境变量读取。
+// 指定 -repair 时重建空闲树并写回，原文件保留为 <state>.fsck-backup。修复需要在服务停止时执行
+//
+//	spaceweave-fsck [-state path] [-repair]
+//
+// 没有发现问题时退出码为 0，发现问题（修复后仍有问题）时为 1，无法读取文件时为 2
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/li1213987842/spaceweave/config"
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+func main() {
+	statePath := flag.String("state", "", "state file to check (default: STATE_PERSISTENCE_PATH)")
+	repair := flag.Bool("repair", false, "rebuild the free tree and write the state file back")
+	flag.Parse()
+
+	cfg, err := config.LoadConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config from env fail: %v\n", err)
+		os.Exit(2)
+	}
+	path := *statePath
+	if path == "" {
+		path = cfg.StatePersistencePath
+	}
+
+	report, err := allocator.CheckState(cfg, path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	printReport(path, report)
+
+	if *repair && !report.OK() {
+		report, err = allocator.RepairState(cfg, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "repair failed: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Printf("\nrepaired %s, original saved as %s.fsck-backup\n", path, path)
+		printReport(path, report)
+	}
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func printReport(path string, report *allocator.CheckReport) {
+	fmt.Printf("%s: %d free blocks, %d free units in tree, %d free units in bitmap, %d allocations covering %d units\n",
+		path, report.FreeBlocks, report.TreeFreeUnits, report.BitmapFreeUnits, report.Allocations, report.AllocatedUnits)
+	for _, p := range report.Problems {
+		fmt.Println("  " + p.String())
+	}
+	if report.OK() {
+		fmt.Println("  no problems found")
+	} else {
+		fmt.Printf("  %d problems found\n", len(report.Problems))
+	}
+}
@@ -0,0 +1,69 @@
+// spaceweave-snapshot 列出和恢复分配器的快照，配置与服务端相同，从环境变量读取。
+// restore 需要在服务停止时执行
+//
+//	spaceweave-snapshot list
+//	spaceweave-snapshot restore <name>
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: spaceweave-snapshot list")
+	fmt.Fprintln(os.Stderr, "       spaceweave-snapshot restore <name>")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cfg, err := config.LoadConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config from env fail: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		err = list(cfg)
+	case "restore":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		err = allocator.RestoreSnapshot(cfg, os.Args[2])
+		if err == nil {
+			fmt.Printf("restored snapshot %s to %s\n", os.Args[2], cfg.StatePersistencePath)
+		}
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func list(cfg *config.Config) error {
+	snapshots, err := allocator.ListSnapshots(cfg)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTIME\tWAL SEQ\tSIZE")
+	for _, s := range snapshots {
+		seq := fmt.Sprint(s.WALSeq)
+		if s.Current {
+			seq = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", s.Name, s.Time.Local().Format(time.RFC3339), seq, s.Size)
+	}
+	return w.Flush()
+}
@@ -18,6 +18,17 @@ type Config struct {
 	StatePersistencePath     string  `env:"STATE_PERSISTENCE_PATH" default:".spaceweave"`
 	BackupIntervalSec        int     `env:"BACKUP_INTERVAL_SEC" default:"5"`
 	BackupOperationThreshold uint64  `env:"BACKUP_OPERATION_THRESHOLD" default:"1000000"`
+	PlacementPolicy          string  `env:"PLACEMENT_POLICY" default:"best-fit"` // best-fit / first-fit / next-fit / worst-fit
+	WALPath                  string  `env:"WAL_PATH" default:""`                 // 为空时使用 STATE_PERSISTENCE_PATH + ".wal"
+	WALSyncPolicy            string  `env:"WAL_SYNC_POLICY" default:"group"`     // off / per-op / group / interval
+	WALSyncIntervalMs        int     `env:"WAL_SYNC_INTERVAL_MS" default:"10"`   // interval 策略下的刷盘间隔
+	SnapshotRetention        int     `env:"SNAPSHOT_RETENTION" default:"3"`      // 保留的快照个数（含当前快照），不大于 1 时不保留历史
+	SnapshotMaxAgeSec        int     `env:"SNAPSHOT_MAX_AGE_SEC" default:"0"`    // 历史快照的最长保留时间，0 表示不限
+	DeltaCheckpointLimit     int     `env:"DELTA_CHECKPOINT_LIMIT" default:"16"` // 两次完整快照之间最多写入的增量检查点个数，0 表示每次都写完整快照
+	VerifyIntervalSec        int     `env:"VERIFY_INTERVAL_SEC" default:"0"`     // 在线一致性检查的间隔，0 表示只通过管理接口触发
+	VerifySelfHeal           bool    `env:"VERIFY_SELF_HEAL" default:"false"`    // 定期检查发现索引不一致时是否自动重建 treeBySize
+	PoolRegistryPath         string  `env:"POOL_REGISTRY_PATH" default:""`       // 存储池登记文件，为空时使用 STATE_PERSISTENCE_PATH + ".pools"
+	Tiers                    string  `env:"TIERS" default:""`                    // 分层分配使用的存储池，按优先级从高到低以逗号分隔，每项为 <pool>[:<水位线>]
 }
 
 func LoadConfigFromEnv() (*Config, error) {
@@ -58,6 +69,12 @@ func LoadConfigFromEnv() (*Config, error) {
 				return nil, fmt.Errorf("%s must be a float: %v", envName, err)
 			}
 			v.Field(i).SetFloat(floatVal)
+		case reflect.Bool:
+			boolVal, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a bool: %v", envName, err)
+			}
+			v.Field(i).SetBool(boolVal)
 		case reflect.String:
 			v.Field(i).SetString(val)
 		}
@@ -73,6 +90,9 @@ func LoadConfigFromEnv() (*Config, error) {
 }
 
 func (c *Config) validate() error {
+	if c.UnitSize == 0 {
+		return fmt.Errorf("UNIT_SIZE must be greater than 0")
+	}
 	if c.TotalSize < c.UnitSize {
 		return fmt.Errorf("TOTAL_SIZE must be greater than or equal to UNIT_SIZE")
 	}
@@ -80,6 +100,26 @@ func (c *Config) validate() error {
 	return nil
 }
 
+// ForPool 以 c 为模板生成一个存储池的配置，UnitSize、TotalSize 和持久化路径取自参数，
+// WAL 路径随持久化路径派生，派生值重新计算
+func (c *Config) ForPool(unitSize, totalSize uint64, statePath string) (*Config, error) {
+	pool := *c
+	pool.UnitSize = unitSize
+	pool.TotalSize = totalSize
+	pool.StatePersistencePath = statePath
+	pool.WALPath = ""
+	pool.PoolRegistryPath = ""
+	if err := pool.validate(); err != nil {
+		return nil, err
+	}
+	pool.calculateDerivedValues()
+	// 位图按 64 个单元一个字均分到各分片，每个分片至少需要一个字
+	if pool.NumShards == 0 || pool.SmallBlockLimit/(64*pool.NumShards) == 0 {
+		return nil, fmt.Errorf("TOTAL_SIZE %d gives %d small block units, too few for %d shards of at least 64 units", totalSize, pool.SmallBlockLimit, pool.NumShards)
+	}
+	return &pool, nil
+}
+
 func (c *Config) calculateDerivedValues() {
 	c.SmallBlockLimit = uint64(float64(c.TotalSize) * c.SmallBlockRatio / float64(c.UnitSize))
 }
@@ -3,6 +3,7 @@ module github.com/li1213987842/spaceweave
 go 1.21.1
 
 require (
+	github.com/google/btree v1.1.3
 	github.com/pkg/errors v0.9.1
 	google.golang.org/grpc v1.61.1
 	google.golang.org/protobuf v1.34.2
@@ -10,7 +11,6 @@ require (
 
 require (
 	github.com/golang/protobuf v1.5.3 // indirect
-	github.com/google/btree v1.1.3 // indirect
 	golang.org/x/net v0.28.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
@@ -0,0 +1,295 @@
+package allocator
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+var (
+	ErrInvalidMap = errors.New("invalid allocation map")
+	ErrNotEmpty   = errors.New("allocator has live allocations")
+)
+
+// MapFormat 是分配图的导出格式
+type MapFormat string
+
+const (
+	MapFormatJSON MapFormat = "json" // 使用 proto 包中的 protojson 编码
+	MapFormatCSV  MapFormat = "csv"  // 每行一个区间：address,size,region,state
+)
+
+var mapCSVHeader = []string{"address", "size", "region", "state"}
+
+// ParseMapFormat 将格式名转换为 MapFormat，空字符串表示 JSON
+func ParseMapFormat(name string) (MapFormat, error) {
+	switch format := MapFormat(strings.ToLower(name)); format {
+	case "":
+		return MapFormatJSON, nil
+	case MapFormatJSON, MapFormatCSV:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown map format %q", name)
+}
+
+// ExportMap 返回按地址排序的全部已分配和空闲区间。已分配区间来自分配表，跨越两个区域的区间按起点所在区域标记；
+// 空闲区间来自位图中连续的空闲单元和空闲树。三者在 walMu 写锁下以写时复制的方式取快照：
+// 释放和分配的登记都在读锁内完成，三者互相一致，已从空闲结构取出、尚未登记的单元不出现在导出中。
+// 取完快照即释放锁，导出期间分配和释放照常进行
+func (da *diskAllocatorImpl) ExportMap() *pb.AllocationMap {
+	unit := da.cfg.UnitSize
+	limit := da.cfg.SmallBlockLimit
+	da.walMu.Lock()
+	total := da.totalSize()
+	allocations := da.allocations.snapshot()
+	shards := da.bitmaps.snapshot()
+	free := da.tree.snapshot()
+	da.walMu.Unlock()
+
+	extents := make([]*pb.MapExtent, 0, len(allocations)+len(free))
+	add := func(start, size uint64, allocated bool) {
+		region := pb.Region_TREE
+		if start < limit {
+			region = pb.Region_BITMAP
+		}
+		extents = append(extents, &pb.MapExtent{Address: start * unit, Size: size * unit, Region: region, Allocated: allocated})
+	}
+	for _, entry := range allocations {
+		add(entry.Start, entry.Size, true)
+	}
+	clearRuns(shards, func(start, size uint64) {
+		add(start, size, false)
+	})
+	for _, block := range free {
+		add(limit+block.Start, block.Size, false)
+	}
+	slices.SortFunc(extents, func(a, b *pb.MapExtent) int {
+		return cmp.Compare(a.Address, b.Address)
+	})
+	return &pb.AllocationMap{UnitSize: unit, TotalSize: total, Extents: extents}
+}
+
+// ImportMap 在没有存活分配的分配器上按分配图占用全部已分配区间，返回导入的区间个数，空闲区间只做校验。
+// 检查分配器为空和占用全部区间在 walMu 写锁下完成，其间不会插入其他分配或释放；
+// 全部区间一次写入 WAL，任一区间失败时归还已占用的区间
+func (da *diskAllocatorImpl) ImportMap(m *pb.AllocationMap) (int, error) {
+	allocated, err := da.checkMap(m)
+	if err != nil {
+		return 0, err
+	}
+	unit := da.cfg.UnitSize
+	blocks := make([]BTreeBlock, len(allocated))
+	var units uint64
+	for i, extent := range allocated {
+		blocks[i] = BTreeBlock{Start: extent.Address / unit, Size: extent.Size / unit}
+		units += blocks[i].Size
+	}
+	adm, err := da.admit("", units)
+	if err != nil {
+		return 0, err
+	}
+	defer adm.release()
+
+	da.walMu.Lock()
+	defer da.walMu.Unlock()
+	if da.allocations.len() > 0 {
+		return 0, ErrNotEmpty
+	}
+	for i, b := range blocks {
+		if err := da.reserveUnits(b.Start, b.Size); err != nil {
+			for _, done := range blocks[:i] {
+				da.freeUnits(done.Start, done.Size)
+			}
+			return 0, fmt.Errorf("failed to import extent [%d, %d): %w", b.Start*unit, (b.Start+b.Size)*unit, err)
+		}
+	}
+	if err := da.commitAllocsLocked(blocks...); err != nil {
+		return 0, err
+	}
+	da.incrementOperationCount()
+	return len(blocks), nil
+}
+
+// checkMap 检查分配图与配置一致、区间按单元对齐且互不重叠、区域标记与地址相符，返回已分配的区间
+func (da *diskAllocatorImpl) checkMap(m *pb.AllocationMap) ([]*pb.MapExtent, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: empty map", ErrInvalidMap)
+	}
+	unit, total := da.cfg.UnitSize, da.totalSize()
+	if (m.UnitSize != 0 && m.UnitSize != unit) || (m.TotalSize != 0 && m.TotalSize != total) {
+		return nil, fmt.Errorf("%w: map has unit size %d and total size %d, allocator has %d and %d",
+			ErrInvalidMap, m.UnitSize, m.TotalSize, unit, total)
+	}
+
+	boundary := da.cfg.SmallBlockLimit * unit
+	extents := slices.Clone(m.Extents)
+	slices.SortFunc(extents, func(a, b *pb.MapExtent) int {
+		return cmp.Compare(a.GetAddress(), b.GetAddress())
+	})
+	var allocated []*pb.MapExtent
+	var prevEnd uint64
+	for i, extent := range extents {
+		start, end := extent.GetAddress(), extent.GetAddress()+extent.GetSize()
+		switch {
+		case extent.GetSize() == 0 || start%unit != 0 || extent.GetSize()%unit != 0:
+			return nil, fmt.Errorf("%w: extent [%d, %d) is not a positive multiple of unit size %d", ErrInvalidMap, start, end, unit)
+		case end < start || end > total:
+			return nil, fmt.Errorf("%w: extent [%d, %d) exceeds total size %d", ErrInvalidMap, start, end, total)
+		case i > 0 && start < prevEnd:
+			return nil, fmt.Errorf("%w: extent [%d, %d) overlaps previous extent ending at %d", ErrInvalidMap, start, end, prevEnd)
+		case (start < boundary) != (extent.GetRegion() == pb.Region_BITMAP):
+			return nil, fmt.Errorf("%w: extent [%d, %d) is not in region %s", ErrInvalidMap, start, end, extent.GetRegion())
+		}
+		prevEnd = end
+		if extent.GetAllocated() {
+			allocated = append(allocated, extent)
+		}
+	}
+	return allocated, nil
+}
+
+// clearRuns 按地址顺序对位图中每段连续的空闲单元调用 fn，分片首尾相接
+func clearRuns(shards [][]uint64, fn func(start, size uint64)) {
+	var unit, runStart, runSize uint64
+	flush := func() {
+		if runSize > 0 {
+			fn(runStart, runSize)
+			runSize = 0
+		}
+	}
+	for _, words := range shards {
+		for _, word := range words {
+			switch word {
+			case 0:
+				if runSize == 0 {
+					runStart = unit
+				}
+				runSize += 64
+			case ^uint64(0):
+				flush()
+			default:
+				for bit := uint64(0); bit < 64; bit++ {
+					if word&(1<<bit) != 0 {
+						flush()
+					} else {
+						if runSize == 0 {
+							runStart = unit + bit
+						}
+						runSize++
+					}
+				}
+			}
+			unit += 64
+		}
+	}
+	flush()
+}
+
+// WriteMap 按 format 写出分配图。JSON 经 protojson 编码后缩进；CSV 只包含区间，不包含单元大小和总大小
+func WriteMap(w io.Writer, m *pb.AllocationMap, format MapFormat) error {
+	switch format {
+	case MapFormatJSON:
+		data, err := m.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return err
+		}
+		out.WriteByte('\n')
+		_, err = out.WriteTo(w)
+		return err
+	case MapFormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(mapCSVHeader)
+		for _, extent := range m.Extents {
+			state := "free"
+			if extent.Allocated {
+				state = "allocated"
+			}
+			cw.Write([]string{
+				strconv.FormatUint(extent.Address, 10),
+				strconv.FormatUint(extent.Size, 10),
+				strings.ToLower(extent.Region.String()),
+				state,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("unknown map format %q", format)
+}
+
+// ReadMap 读取 WriteMap 写出的分配图
+func ReadMap(r io.Reader, format MapFormat) (*pb.AllocationMap, error) {
+	switch format {
+	case MapFormatJSON:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		m := &pb.AllocationMap{}
+		if err := m.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMap, err)
+		}
+		return m, nil
+	case MapFormatCSV:
+		return readMapCSV(r)
+	}
+	return nil, fmt.Errorf("unknown map format %q", format)
+}
+
+func readMapCSV(r io.Reader) (*pb.AllocationMap, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(mapCSVHeader)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMap, err)
+	}
+	if !slices.Equal(header, mapCSVHeader) {
+		return nil, fmt.Errorf("%w: header is %q, want %q", ErrInvalidMap, header, mapCSVHeader)
+	}
+
+	m := &pb.AllocationMap{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMap, err)
+		}
+		line, _ := cr.FieldPos(0)
+		address, err := strconv.ParseUint(record[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: address: %v", ErrInvalidMap, line, err)
+		}
+		size, err := strconv.ParseUint(record[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: size: %v", ErrInvalidMap, line, err)
+		}
+		region, ok := pb.Region_value[strings.ToUpper(record[2])]
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: unknown region %q", ErrInvalidMap, line, record[2])
+		}
+		if record[3] != "allocated" && record[3] != "free" {
+			return nil, fmt.Errorf("%w: line %d: unknown state %q", ErrInvalidMap, line, record[3])
+		}
+		m.Extents = append(m.Extents, &pb.MapExtent{
+			Address:   address,
+			Size:      size,
+			Region:    pb.Region(region),
+			Allocated: record[3] == "allocated",
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package allocator
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/li1213987842/spaceweave/config"
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+func TestExportMapCoversSpace(t *testing.T) {
+	da := newVerifyTestAllocator(t)
+	defer da.Close()
+
+	m := da.ExportMap()
+	var next, allocated uint64
+	for _, extent := range m.Extents {
+		if extent.Address != next {
+			t.Fatalf("extent %v starts at %d, want %d", extent, extent.Address, next)
+		}
+		if want := extent.Address < da.cfg.SmallBlockLimit*da.cfg.UnitSize; (extent.Region == pb.Region_BITMAP) != want {
+			t.Errorf("extent %v has region %s", extent, extent.Region)
+		}
+		if extent.Allocated {
+			allocated += extent.Size
+		}
+		next += extent.Size
+	}
+	if next != da.cfg.TotalSize {
+		t.Errorf("extents end at %d, want %d", next, da.cfg.TotalSize)
+	}
+	if want := uint64(da.GetDiskUtilization() * float64(da.cfg.TotalSize)); allocated != want {
+		t.Errorf("allocated extents cover %d bytes, want %d", allocated, want)
+	}
+}
+
+func TestImportMapRoundTrip(t *testing.T) {
+	da := newVerifyTestAllocator(t)
+	defer da.Close()
+	exported := da.ExportMap()
+
+	for _, format := range []MapFormat{MapFormatJSON, MapFormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteMap(&buf, exported, format); err != nil {
+				t.Fatalf("WriteMap() error = %v", err)
+			}
+			m, err := ReadMap(&buf, format)
+			if err != nil {
+				t.Fatalf("ReadMap() error = %v", err)
+			}
+
+			loaded, err := LoadState(da.cfg)
+			if err != nil {
+				t.Fatalf("LoadState() error = %v", err)
+			}
+			imported := loaded.(*diskAllocatorImpl)
+			defer imported.Close()
+			n, err := imported.ImportMap(m)
+			if err != nil {
+				t.Fatalf("ImportMap() error = %v", err)
+			}
+			if want := da.allocations.len(); n != want {
+				t.Errorf("ImportMap() = %d, want %d", n, want)
+			}
+			if got := imported.ExportMap(); !proto.Equal(got, exported) {
+				t.Errorf("ExportMap() after import = %v, want %v", got, exported)
+			}
+			checkTreeConsistent(t, imported.tree)
+
+			if _, err := imported.ImportMap(m); !errors.Is(err, ErrNotEmpty) {
+				t.Errorf("ImportMap() into non-empty allocator error = %v, want %v", err, ErrNotEmpty)
+			}
+		})
+	}
+}
+
+func TestImportMapConcurrent(t *testing.T) {
+	da := newLeaseTestAllocator(t, "")
+	defer da.Close()
+
+	// 两张互不重叠的分配图同时导入，检查为空和占用是原子的，只有一张能导入
+	maps := []*pb.AllocationMap{
+		{Extents: []*pb.MapExtent{{Address: 0, Size: 8192, Region: pb.Region_BITMAP, Allocated: true}}},
+		{Extents: []*pb.MapExtent{{Address: 1024 * 4096, Size: 1024 * 1024, Region: pb.Region_TREE, Allocated: true}}},
+	}
+	errs := make([]error, len(maps))
+	var wg sync.WaitGroup
+	for i, m := range maps {
+		wg.Add(1)
+		go func(i int, m *pb.AllocationMap) {
+			defer wg.Done()
+			_, errs[i] = da.ImportMap(m)
+		}(i, m)
+	}
+	wg.Wait()
+	if (errs[0] == nil) == (errs[1] == nil) || !errors.Is(errors.Join(errs...), ErrNotEmpty) {
+		t.Errorf("concurrent ImportMap() errors = %v, want exactly one %v", errs, ErrNotEmpty)
+	}
+	if got := da.allocations.len(); got != 1 {
+		t.Errorf("allocations after concurrent import = %d, want 1", got)
+	}
+}
+
+func TestImportMapRejectsInvalid(t *testing.T) {
+	cfg := &config.Config{
+		UnitSize:        4096,
+		TotalSize:       64 * 1024 * 1024,
+		SmallBlockLimit: 1024,
+		NumShards:       4,
+	}
+	loaded, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	da := loaded.(*diskAllocatorImpl)
+	defer da.Close()
+
+	boundary := cfg.SmallBlockLimit * cfg.UnitSize
+	tests := []struct {
+		name string
+		m    *pb.AllocationMap
+	}{
+		{"unit size", &pb.AllocationMap{UnitSize: 512}},
+		{"unaligned", &pb.AllocationMap{Extents: []*pb.MapExtent{{Address: 100, Size: 4096, Allocated: true}}}},
+		{"out of range", &pb.AllocationMap{Extents: []*pb.MapExtent{{Address: cfg.TotalSize, Size: 4096, Region: pb.Region_TREE}}}},
+		{"overlap", &pb.AllocationMap{Extents: []*pb.MapExtent{
+			{Address: 0, Size: 8192, Allocated: true},
+			{Address: 4096, Size: 4096},
+		}}},
+		{"region", &pb.AllocationMap{Extents: []*pb.MapExtent{{Address: boundary, Size: 4096, Region: pb.Region_BITMAP, Allocated: true}}}},
+	}
+	for _, tt := range tests {
+		if _, err := da.ImportMap(tt.m); !errors.Is(err, ErrInvalidMap) {
+			t.Errorf("%s: ImportMap() error = %v, want %v", tt.name, err, ErrInvalidMap)
+		}
+	}
+	if n := da.allocations.len(); n != 0 {
+		t.Errorf("%d allocations after rejected imports, want 0", n)
+	}
+}
@@ -0,0 +1,259 @@
+package allocator
+
+import (
+	"sync"
+
+	"github.com/google/btree"
+)
+
+// AllocationEntry 记录一段存活的分配，Start 和 Size 以单元为单位
+type AllocationEntry struct {
+	Start  uint64
+	Size   uint64
+	Legacy bool // 由旧版本状态文件重建，允许按任意子范围释放
+}
+
+func (e AllocationEntry) Less(than btree.Item) bool {
+	return e.Start < than.(AllocationEntry).Start
+}
+
+// allocationTable 记录所有存活的分配，用于拒绝重复释放、未知地址和大小不符的释放
+type allocationTable struct {
+	mu      sync.RWMutex
+	entries *btree.BTree
+	// dirty 记录上次检查点之后被插入、删除或修改过的起点，未启用脏跟踪时为 nil
+	dirty map[uint64]struct{}
+}
+
+func newAllocationTable() *allocationTable {
+	return &allocationTable{entries: btree.New(32)}
+}
+
+func (t *allocationTable) insert(start, size uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries.ReplaceOrInsert(AllocationEntry{Start: start, Size: size})
+	t.touch(start)
+}
+
+// remove 删除 [start, start+size) 对应的记录，范围必须与某次分配完全一致。
+// claim 不为 nil 时在校验通过后、持有锁期间调用，返回 false 时不删除并返回 ErrNotAllocated，
+// 用于与删除原子地检查和清理这段分配的附属状态
+func (t *allocationTable) remove(start, size uint64, claim func() bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.containing(start)
+	if !ok {
+		return ErrNotAllocated
+	}
+	if !entry.Legacy && entry.Start != start {
+		return ErrNotAllocated
+	}
+	if end := entry.Start + entry.Size; entry.Legacy && start+size > end || !entry.Legacy && entry.Size != size {
+		return ErrSizeMismatch
+	}
+	if claim != nil && !claim() {
+		return ErrNotAllocated
+	}
+	if entry.Legacy {
+		t.splitLegacy(entry, start, size)
+		return nil
+	}
+	t.entries.Delete(entry)
+	t.touch(start)
+	return nil
+}
+
+// resize 将起点为 start 的记录从 oldSize 调整为 newSize
+func (t *allocationTable) resize(start, oldSize, newSize uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item := t.entries.Get(AllocationEntry{Start: start})
+	if item == nil {
+		return ErrNotAllocated
+	}
+	entry := item.(AllocationEntry)
+	if entry.Size != oldSize {
+		return ErrSizeMismatch
+	}
+	entry.Size = newSize
+	t.entries.ReplaceOrInsert(entry)
+	t.touch(start)
+	return nil
+}
+
+// lookup 返回起点恰好为 start 的记录
+func (t *allocationTable) lookup(start uint64) (AllocationEntry, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	item := t.entries.Get(AllocationEntry{Start: start})
+	if item == nil {
+		return AllocationEntry{}, false
+	}
+	return item.(AllocationEntry), true
+}
+
+// len 返回存活分配的个数
+func (t *allocationTable) len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.entries.Len()
+}
+
+// beyond 返回结束位置超过单元 start 的全部记录，按起点排序
+func (t *allocationTable) beyond(start uint64) []AllocationEntry {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var entries []AllocationEntry
+	if entry, ok := t.containing(start); ok && entry.Start < start {
+		entries = append(entries, entry)
+	}
+	t.entries.AscendGreaterOrEqual(AllocationEntry{Start: start}, func(item btree.Item) bool {
+		entries = append(entries, item.(AllocationEntry))
+		return true
+	})
+	return entries
+}
+
+// containing 返回包含单元 start 的记录，调用方需持有锁
+func (t *allocationTable) containing(start uint64) (AllocationEntry, bool) {
+	var found AllocationEntry
+	var ok bool
+	t.entries.DescendLessOrEqual(AllocationEntry{Start: start}, func(item btree.Item) bool {
+		entry := item.(AllocationEntry)
+		ok = start < entry.Start+entry.Size
+		found = entry
+		return false
+	})
+	return found, ok
+}
+
+// splitLegacy 从旧记录中移除子范围 [start, start+size)，保留首尾剩余部分，调用方需已检查范围
+func (t *allocationTable) splitLegacy(entry AllocationEntry, start, size uint64) {
+	end := entry.Start + entry.Size
+	t.entries.Delete(entry)
+	t.touch(entry.Start)
+	if start > entry.Start {
+		t.entries.ReplaceOrInsert(AllocationEntry{Start: entry.Start, Size: start - entry.Start, Legacy: true})
+	}
+	if start+size < end {
+		t.entries.ReplaceOrInsert(AllocationEntry{Start: start + size, Size: end - start - size, Legacy: true})
+		t.touch(start + size)
+	}
+}
+
+// snapshot 返回全部记录的副本，持锁期间只做 O(1) 的 Clone，遍历在锁外进行
+func (t *allocationTable) snapshot() []AllocationEntry {
+	t.mu.Lock()
+	clone := t.entries.Clone()
+	t.mu.Unlock()
+
+	entries := make([]AllocationEntry, 0, clone.Len())
+	clone.Ascend(func(item btree.Item) bool {
+		entries = append(entries, item.(AllocationEntry))
+		return true
+	})
+	return entries
+}
+
+func (t *allocationTable) load(entries []AllocationEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries.Clear(false)
+	for _, e := range entries {
+		t.entries.ReplaceOrInsert(e)
+	}
+}
+
+// trackDirty 启用脏跟踪，需在并发访问开始之前调用
+func (t *allocationTable) trackDirty() {
+	t.dirty = make(map[uint64]struct{})
+}
+
+// touch 记录起点为 start 的记录已改变，调用方需持有写锁
+func (t *allocationTable) touch(start uint64) {
+	if t.dirty != nil {
+		t.dirty[start] = struct{}{}
+	}
+}
+
+// takeDirty 返回上次调用之后改变过的记录：仍存在的作为 upserts，已删除的起点作为 deletes
+func (t *allocationTable) takeDirty() (upserts []AllocationEntry, deletes []uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for start := range t.dirty {
+		if item := t.entries.Get(AllocationEntry{Start: start}); item != nil {
+			upserts = append(upserts, item.(AllocationEntry))
+		} else {
+			deletes = append(deletes, start)
+		}
+	}
+	if t.dirty != nil {
+		t.dirty = make(map[uint64]struct{})
+	}
+	return upserts, deletes
+}
+
+// clearDirty 丢弃全部脏记录，写完整快照前调用
+func (t *allocationTable) clearDirty() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.dirty != nil {
+		t.dirty = make(map[uint64]struct{})
+	}
+}
+
+// applyDelta 应用增量检查点中的记录，先删除后插入
+func (t *allocationTable) applyDelta(upserts []AllocationEntry, deletes []uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, start := range deletes {
+		t.entries.Delete(AllocationEntry{Start: start})
+	}
+	for _, entry := range upserts {
+		t.entries.ReplaceOrInsert(entry)
+	}
+}
+
+// rebuildLegacyAllocations 根据位图和空闲树推算已分配的区间，用于加载没有分配表的旧状态文件。
+// 推算出的区间无法区分相邻的多次分配，因此标记为 Legacy
+func rebuildLegacyAllocations(bitmaps *ConcurrentBitMap, tree *BTreeManager, smallBlockLimit uint64) []AllocationEntry {
+	entries := make([]AllocationEntry, 0)
+	appendRun := func(start, size uint64) {
+		if size == 0 {
+			return
+		}
+		if n := len(entries); n > 0 && entries[n-1].Start+entries[n-1].Size == start {
+			entries[n-1].Size += size
+			return
+		}
+		entries = append(entries, AllocationEntry{Start: start, Size: size, Legacy: true})
+	}
+
+	for i := range bitmaps.shards {
+		shard := &bitmaps.shards[i]
+		base := uint64(i) * uint64(len(shard.bits)) * 64
+		shard.mu.RLock()
+		for j := uint64(0); j < uint64(len(shard.bits))*64; j++ {
+			if shard.bits[j/64]&(1<<(j%64)) != 0 {
+				appendRun(base+j, 1)
+			}
+		}
+		shard.mu.RUnlock()
+	}
+
+	tree.mu.RLock()
+	next := uint64(0)
+	tree.treeByStart.Ascend(func(item btree.Item) bool {
+		block := item.(BlockByStart).BTreeBlock
+		appendRun(smallBlockLimit+next, block.Start-next)
+		next = block.Start + block.Size
+		return true
+	})
+	appendRun(smallBlockLimit+next, tree.totalSpace-next)
+	tree.mu.RUnlock()
+
+	return entries
+}
@@ -0,0 +1,78 @@
+package allocator
+
+import (
+	"testing"
+)
+
+func TestAllocationTableRemove(t *testing.T) {
+	table := newAllocationTable()
+	table.insert(100, 10)
+	table.insert(200, 20)
+
+	tests := []struct {
+		name        string
+		start, size uint64
+		expectedErr error
+	}{
+		{"Unknown address", 50, 10, ErrNotAllocated},
+		{"Inside allocation", 105, 5, ErrNotAllocated},
+		{"Wrong size", 100, 5, ErrSizeMismatch},
+		{"Too large", 200, 30, ErrSizeMismatch},
+		{"Exact match", 100, 10, nil},
+		{"Double free", 100, 10, ErrNotAllocated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := table.remove(tt.start, tt.size, nil); err != tt.expectedErr {
+				t.Errorf("remove(%d, %d) error = %v, want %v", tt.start, tt.size, err, tt.expectedErr)
+			}
+		})
+	}
+
+	if entries := table.snapshot(); len(entries) != 1 || entries[0].Start != 200 {
+		t.Errorf("snapshot() = %v, want only [200, 220)", entries)
+	}
+}
+
+func TestAllocationTableLegacySplit(t *testing.T) {
+	table := newAllocationTable()
+	table.load([]AllocationEntry{{Start: 0, Size: 100, Legacy: true}})
+
+	if err := table.remove(40, 20, nil); err != nil {
+		t.Fatalf("remove() error = %v", err)
+	}
+	if err := table.remove(45, 5, nil); err != ErrNotAllocated {
+		t.Errorf("remove() on freed legacy range error = %v, want ErrNotAllocated", err)
+	}
+	if err := table.remove(90, 20, nil); err != ErrSizeMismatch {
+		t.Errorf("remove() past legacy range error = %v, want ErrSizeMismatch", err)
+	}
+
+	entries := table.snapshot()
+	if len(entries) != 2 || entries[0] != (AllocationEntry{0, 40, true}) || entries[1] != (AllocationEntry{60, 40, true}) {
+		t.Errorf("snapshot() = %v", entries)
+	}
+}
+
+func TestRebuildLegacyAllocations(t *testing.T) {
+	bm := NewBitMap(128, 1)
+	bm.Reserve(10, 5)
+	bm.Reserve(126, 2)
+	tree := NewBTreeManagerWithBlocks(100, []BTreeBlock{{Start: 3, Size: 50}})
+
+	entries := rebuildLegacyAllocations(bm, tree, 128)
+	expected := []AllocationEntry{
+		{Start: 10, Size: 5, Legacy: true},
+		{Start: 126, Size: 5, Legacy: true}, // 位图末尾与 B 树开头相连
+		{Start: 128 + 53, Size: 47, Legacy: true},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("rebuildLegacyAllocations() = %v, want %v", entries, expected)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %d = %v, want %v", i, entries[i], expected[i])
+		}
+	}
+}
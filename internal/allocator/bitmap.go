@@ -3,20 +3,56 @@ package allocator
 import (
 	"math/bits"
 	"math/rand"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
-var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+// random 会被多个 goroutine 同时使用，rand.Rand 本身不是并发安全的，需要加锁的 Source
+var random = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano())})
+
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
 
 type ConcurrentBitMap struct {
 	shards []Shard
+
+	// free 是位图中的空闲单元数，随分配和释放原子更新，读取时不必扫描位图
+	free int64
 }
 
 type Shard struct {
 	bits []uint64
 	mu   sync.RWMutex
+
+	// shared 为 true 时 bits 被快照引用，修改前先复制（写时复制）
+	shared bool
+
+	// dirty 每一位对应 bits 中的一个字，记录上次检查点之后被修改过的字，未启用脏跟踪时为 nil
+	dirty    []uint64
+	hasDirty bool
+}
+
+// bitmapWord 是位图中的一个字，增量检查点只保存被修改过的字
+type bitmapWord struct {
+	Shard uint64
+	Index uint64
+	Bits  uint64
 }
 
 func NewBitMap(size, shards uint64) *ConcurrentBitMap {
@@ -30,6 +66,7 @@ func NewBitMap(size, shards uint64) *ConcurrentBitMap {
 	for i := range bm.shards {
 		bm.shards[i].bits = make([]uint64, shardSize)
 	}
+	bm.free = int64(bm.capacity())
 	return bm
 }
 
@@ -42,7 +79,11 @@ func (b *ConcurrentBitMap) Allocate(size uint64) (uint64, error) {
 		shard := &b.shards[shardIndex]
 
 		shard.mu.Lock()
-		start, ok := allocateInShard(shard.bits, size)
+		start, ok := allocateInShard(shard.writable(), size)
+		if ok {
+			shard.touch(start, size)
+			b.addFree(-int64(size))
+		}
 		shard.mu.Unlock()
 		if ok {
 			return shardIndex*uint64(len(shard.bits))*64 + start, nil
@@ -51,6 +92,39 @@ func (b *ConcurrentBitMap) Allocate(size uint64) (uint64, error) {
 	return 0, ErrNoSpaceLeft
 }
 
+// AllocateBatch 为 sizes 中的每一项分配空间，每个分片至多加锁一次。
+// ok[i] 为 false 表示所有分片都无法容纳第 i 项
+func (b *ConcurrentBitMap) AllocateBatch(sizes []uint64) ([]uint64, []bool) {
+	starts := make([]uint64, len(sizes))
+	ok := make([]bool, len(sizes))
+	pending := len(sizes)
+
+	shardCount := uint64(len(b.shards))
+	startShard := uint64(uint32(random.Int63())) % shardCount
+
+	for i := uint64(0); i < shardCount && pending > 0; i++ {
+		shardIndex := (startShard + i) % shardCount
+		shard := &b.shards[shardIndex]
+		base := shardIndex * uint64(len(shard.bits)) * 64
+
+		shard.mu.Lock()
+		for j, size := range sizes {
+			if ok[j] {
+				continue
+			}
+			if start, found := allocateInShard(shard.writable(), size); found {
+				shard.touch(start, size)
+				b.addFree(-int64(size))
+				starts[j] = base + start
+				ok[j] = true
+				pending--
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return starts, ok
+}
+
 func allocateInShard(bits []uint64, size uint64) (uint64, bool) {
 	if size == 0 {
 		return 0, false
@@ -80,22 +154,190 @@ func allocateInShard(bits []uint64, size uint64) (uint64, bool) {
 	return 0, false
 }
 
-func markAllocated(bits []uint64, start, size uint64) {
+// AllocateAligned 分配 size 个单元，起始单元号为 alignment 的整数倍
+func (b *ConcurrentBitMap) AllocateAligned(size, alignment uint64) (uint64, error) {
+	if alignment <= 1 {
+		return b.Allocate(size)
+	}
+
+	shardCount := uint64(len(b.shards))
+	startShard := uint64(uint32(random.Int63())) % shardCount
+
+	for i := uint64(0); i < shardCount; i++ {
+		shardIndex := (startShard + i) % shardCount
+		shard := &b.shards[shardIndex]
+		base := shardIndex * uint64(len(shard.bits)) * 64
+
+		shard.mu.Lock()
+		start, ok := allocateAlignedInShard(shard.writable(), size, alignment, base)
+		if ok {
+			shard.touch(start, size)
+			b.addFree(-int64(size))
+		}
+		shard.mu.Unlock()
+		if ok {
+			return base + start, nil
+		}
+	}
+	return 0, ErrNoSpaceLeft
+}
+
+// allocateAlignedInShard 与 allocateInShard 相同，但连续空闲区间只能从 (base+i)%alignment==0 处开始
+func allocateAlignedInShard(bits []uint64, size, alignment, base uint64) (uint64, bool) {
+	if size == 0 {
+		return 0, false
+	}
+
+	consecutiveFree := uint64(0)
+	start := uint64(0)
+	total := uint64(len(bits)) * 64
+
+	for i := uint64(0); i < total; i++ {
+		if bits[i/64]&(1<<(i%64)) != 0 {
+			consecutiveFree = 0
+			continue
+		}
+		if consecutiveFree == 0 {
+			if (base+i)%alignment != 0 {
+				continue
+			}
+			start = i
+		}
+		consecutiveFree++
+		if consecutiveFree == size {
+			markAllocated(bits, start, size)
+			return start, true
+		}
+	}
+
+	return 0, false
+}
+
+// AllocateUpTo 分配一段长度在 [minSize, maxSize] 之间的连续空间，返回起点和实际长度
+func (b *ConcurrentBitMap) AllocateUpTo(maxSize, minSize uint64) (uint64, uint64, error) {
+	shardCount := uint64(len(b.shards))
+	startShard := uint64(uint32(random.Int63())) % shardCount
+
+	for i := uint64(0); i < shardCount; i++ {
+		shardIndex := (startShard + i) % shardCount
+		shard := &b.shards[shardIndex]
+
+		shard.mu.Lock()
+		start, size, ok := allocateRunInShard(shard.writable(), maxSize, minSize)
+		if ok {
+			shard.touch(start, size)
+			b.addFree(-int64(size))
+		}
+		shard.mu.Unlock()
+		if ok {
+			return shardIndex*uint64(len(shard.bits))*64 + start, size, nil
+		}
+	}
+	return 0, 0, ErrNoSpaceLeft
+}
+
+// allocateRunInShard 查找第一段长度不小于 minSize 的空闲区间，并占用其中至多 maxSize 个单元
+func allocateRunInShard(bits []uint64, maxSize, minSize uint64) (uint64, uint64, bool) {
+	if maxSize == 0 || minSize > maxSize {
+		return 0, 0, false
+	}
+
+	consecutiveFree := uint64(0)
+	start := uint64(0)
+	total := uint64(len(bits)) * 64
+
+	for i := uint64(0); i <= total; i++ {
+		if i < total && bits[i/64]&(1<<(i%64)) == 0 {
+			if consecutiveFree == 0 {
+				start = i
+			}
+			consecutiveFree++
+			if consecutiveFree < maxSize {
+				continue
+			}
+		}
+		if consecutiveFree >= minSize && consecutiveFree > 0 {
+			markAllocated(bits, start, consecutiveFree)
+			return start, consecutiveFree, true
+		}
+		consecutiveFree = 0
+	}
+
+	return 0, 0, false
+}
+
+// markAllocated 将 [start, start+size) 置位，返回此前为空闲的单元数
+func markAllocated(bits []uint64, start, size uint64) uint64 {
+	var marked uint64
 	for i := start; i < start+size; i++ {
 		blockIndex := i / 64
 		bitIndex := i % 64
-		bits[blockIndex] |= 1 << bitIndex
+		if bits[blockIndex]&(1<<bitIndex) == 0 {
+			bits[blockIndex] |= 1 << bitIndex
+			marked++
+		}
 	}
+	return marked
 }
 
 func (b *ConcurrentBitMap) Free(start, size uint64) error {
-	shardIndex := start / uint64(len(b.shards[0].bits)*64)
-	bitStart := start % uint64(len(b.shards[0].bits)*64)
+	if size == 0 {
+		return nil
+	}
+	shardBits := b.shardBits()
+	if start+size > shardBits*uint64(len(b.shards)) {
+		return ErrOutOfRange
+	}
+
+	first, last := start/shardBits, (start+size-1)/shardBits
+	for i := first; i <= last; i++ {
+		from, n := b.localRange(i, start, size)
+		b.freeInShard(i, from, n)
+	}
+	return nil
+}
+
+// FreeBatch 释放多段空间，按分片分组后每个分片只加锁一次
+func (b *ConcurrentBitMap) FreeBatch(blocks []BTreeBlock) error {
+	shardBits := b.shardBits()
+	byShard := make(map[uint64][]BTreeBlock)
+	for _, block := range blocks {
+		if block.Size == 0 {
+			continue
+		}
+		if block.Start+block.Size > shardBits*uint64(len(b.shards)) {
+			return ErrOutOfRange
+		}
+		for i := block.Start / shardBits; i <= (block.Start+block.Size-1)/shardBits; i++ {
+			from, n := b.localRange(i, block.Start, block.Size)
+			byShard[i] = append(byShard[i], BTreeBlock{Start: from, Size: n})
+		}
+	}
+
+	for shardIndex, local := range byShard {
+		shard := &b.shards[shardIndex]
+		shard.mu.Lock()
+		for _, block := range local {
+			b.addFree(int64(clearBits(shard.writable(), block.Start, block.Size)))
+			shard.touch(block.Start, block.Size)
+		}
+		shard.mu.Unlock()
+	}
+	return nil
+}
 
+func (b *ConcurrentBitMap) freeInShard(shardIndex, bitStart, size uint64) {
 	shard := &b.shards[shardIndex]
 	shard.mu.Lock()
 	defer shard.mu.Unlock()
+	b.addFree(int64(clearBits(shard.writable(), bitStart, size)))
+	shard.touch(bitStart, size)
+}
 
+// clearBits 将 [bitStart, bitStart+size) 清零，返回此前为已占用的单元数。
+// WAL 重放可能重复释放同一段空间，按实际清除的位计数
+func clearBits(words []uint64, bitStart, size uint64) uint64 {
+	var cleared uint64
 	for size > 0 {
 		bitIndex := bitStart / 64
 		bitOffset := bitStart % 64
@@ -105,20 +347,181 @@ func (b *ConcurrentBitMap) Free(start, size uint64) error {
 		}
 
 		mask := ((uint64(1) << bitsToFree) - 1) << bitOffset
-		shard.bits[bitIndex] &= ^mask
+		cleared += uint64(bits.OnesCount64(words[bitIndex] & mask))
+		words[bitIndex] &= ^mask
 
 		size -= bitsToFree
 		bitStart += bitsToFree
 	}
+	return cleared
+}
 
+// Reserve 将 [start, start+size) 标记为已分配，范围可以跨越多个分片。
+// 任一单元已被占用时返回 ErrRangeInUse 且不做任何修改
+func (b *ConcurrentBitMap) Reserve(start, size uint64) error {
+	if size == 0 {
+		return nil
+	}
+	shardBits := b.shardBits()
+	if start+size > shardBits*uint64(len(b.shards)) {
+		return ErrOutOfRange
+	}
+
+	first, last := start/shardBits, (start+size-1)/shardBits
+	for i := first; i <= last; i++ {
+		b.shards[i].mu.Lock()
+		defer b.shards[i].mu.Unlock()
+	}
+
+	for i := first; i <= last; i++ {
+		from, n := b.localRange(i, start, size)
+		if !isRangeFree(b.shards[i].bits, from, n) {
+			return ErrRangeInUse
+		}
+	}
+	for i := first; i <= last; i++ {
+		from, n := b.localRange(i, start, size)
+		markAllocated(b.shards[i].writable(), from, n)
+		b.shards[i].touch(from, n)
+		b.addFree(-int64(n))
+	}
 	return nil
 }
 
+// markUsed 将 [start, start+size) 标记为已占用，不检查是否已被占用，用于 WAL 重放和布局迁移。
+// 范围超出位图覆盖的单元时返回 ErrOutOfRange，不做任何修改
+func (b *ConcurrentBitMap) markUsed(start, size uint64) error {
+	if size == 0 {
+		return nil
+	}
+	if start+size > b.capacity() {
+		return ErrOutOfRange
+	}
+	shardBits := b.shardBits()
+	first, last := start/shardBits, (start+size-1)/shardBits
+	for i := first; i <= last; i++ {
+		from, n := b.localRange(i, start, size)
+		shard := &b.shards[i]
+		shard.mu.Lock()
+		b.addFree(-int64(markAllocated(shard.writable(), from, n)))
+		shard.touch(from, n)
+		shard.mu.Unlock()
+	}
+	return nil
+}
+
+// writable 返回可以原地修改的 bits。bits 被快照引用时先复制一份，快照持有的数组保持不变，调用方需持有写锁
+func (s *Shard) writable() []uint64 {
+	if s.shared {
+		s.bits = slices.Clone(s.bits)
+		s.shared = false
+	}
+	return s.bits
+}
+
+// snapshot 返回各分片位图的只读视图，每个分片只短暂加锁，不复制数据。
+// 分片在快照之后第一次被修改时才复制，返回的切片之后不会再被修改，可以在锁外读取
+func (b *ConcurrentBitMap) snapshot() [][]uint64 {
+	frozen := make([][]uint64, len(b.shards))
+	for i := range b.shards {
+		shard := &b.shards[i]
+		shard.mu.Lock()
+		shard.shared = true
+		frozen[i] = shard.bits
+		shard.mu.Unlock()
+	}
+	return frozen
+}
+
+// trackDirty 启用脏跟踪，需在并发访问开始之前调用
+func (b *ConcurrentBitMap) trackDirty() {
+	for i := range b.shards {
+		b.shards[i].dirty = make([]uint64, (len(b.shards[i].bits)+63)/64)
+	}
+}
+
+// touch 记录 [start, start+size) 所在的字已被修改，调用方需持有分片的写锁
+func (s *Shard) touch(start, size uint64) {
+	if s.dirty == nil || size == 0 {
+		return
+	}
+	for word := start / 64; word <= (start+size-1)/64; word++ {
+		s.dirty[word/64] |= 1 << (word % 64)
+	}
+	s.hasDirty = true
+}
+
+// takeDirty 返回上次调用之后被修改过的字的当前值，并清空脏记录。没有修改的分片不做扫描
+func (b *ConcurrentBitMap) takeDirty() []bitmapWord {
+	var words []bitmapWord
+	for i := range b.shards {
+		shard := &b.shards[i]
+		shard.mu.Lock()
+		if shard.hasDirty {
+			for j, mask := range shard.dirty {
+				for mask != 0 {
+					index := uint64(j)*64 + uint64(bits.TrailingZeros64(mask))
+					words = append(words, bitmapWord{Shard: uint64(i), Index: index, Bits: shard.bits[index]})
+					mask &= mask - 1
+				}
+				shard.dirty[j] = 0
+			}
+			shard.hasDirty = false
+		}
+		shard.mu.Unlock()
+	}
+	return words
+}
+
+// clearDirty 丢弃全部脏记录，写完整快照前调用
+func (b *ConcurrentBitMap) clearDirty() {
+	for i := range b.shards {
+		shard := &b.shards[i]
+		shard.mu.Lock()
+		clear(shard.dirty)
+		shard.hasDirty = false
+		shard.mu.Unlock()
+	}
+}
+
+// capacity 返回位图覆盖的单元数。NewBitMap 将每个分片向下取整到 64 的倍数，覆盖的单元可能少于位图区的大小
+func (b *ConcurrentBitMap) capacity() uint64 {
+	return b.shardBits() * uint64(len(b.shards))
+}
+
+func (b *ConcurrentBitMap) shardBits() uint64 {
+	return uint64(len(b.shards[0].bits)) * 64
+}
+
+// localRange 返回全局范围 [start, start+size) 落在第 shardIndex 个分片内的局部起点和长度
+func (b *ConcurrentBitMap) localRange(shardIndex, start, size uint64) (uint64, uint64) {
+	shardBits := b.shardBits()
+	base := shardIndex * shardBits
+	from, to := start, start+size
+	if from < base {
+		from = base
+	}
+	if to > base+shardBits {
+		to = base + shardBits
+	}
+	return from - base, to - from
+}
+
+func isRangeFree(bits []uint64, start, size uint64) bool {
+	for i := start; i < start+size; i++ {
+		if bits[i/64]&(1<<(i%64)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (b *ConcurrentBitMap) freeSmallInShard(shardIndex, fromBit, toBit uint64) {
 	shard := &b.shards[shardIndex]
 	shard.mu.Lock()
 	defer shard.mu.Unlock()
 
+	words := shard.writable()
 	startIndex := fromBit / 64
 	endIndex := toBit / 64
 
@@ -136,11 +539,38 @@ func (b *ConcurrentBitMap) freeSmallInShard(shardIndex, fromBit, toBit uint64) {
 			mask = ^uint64(0)
 		}
 
-		shard.bits[i] &= ^mask
+		b.addFree(int64(bits.OnesCount64(words[i] & mask)))
+		words[i] &= ^mask
 	}
+	shard.touch(fromBit, toBit-fromBit+1)
 }
 
+// GetAvailableSpace 返回位图中的空闲单元数，读取计数器，不扫描位图
 func (b *ConcurrentBitMap) GetAvailableSpace() uint64 {
+	return uint64(atomic.LoadInt64(&b.free))
+}
+
+func (b *ConcurrentBitMap) addFree(delta int64) {
+	atomic.AddInt64(&b.free, delta)
+}
+
+// setWord 用 value 覆盖第 shardIndex 个分片的第 index 个字并更新空闲计数，用于应用增量检查点
+func (b *ConcurrentBitMap) setWord(shardIndex, index, value uint64) {
+	shard := &b.shards[shardIndex]
+	shard.mu.Lock()
+	words := shard.writable()
+	b.addFree(int64(bits.OnesCount64(words[index])) - int64(bits.OnesCount64(value)))
+	words[index] = value
+	shard.mu.Unlock()
+}
+
+// recount 扫描位图重新计算空闲计数，在整体载入位图之后调用
+func (b *ConcurrentBitMap) recount() {
+	atomic.StoreInt64(&b.free, int64(b.countFree()))
+}
+
+// countFree 扫描全部分片统计空闲单元数，用于一致性检查和重新计数
+func (b *ConcurrentBitMap) countFree() uint64 {
 	var totalUnused uint64
 	var wg sync.WaitGroup
 	for i := range b.shards {
@@ -26,7 +26,8 @@ func TestNewBitMap(t *testing.T) {
 			if len(bm.shards) != tt.expectedLen {
 				t.Errorf("NewBitMap() shard count = %v, want %v", len(bm.shards), tt.expectedLen)
 			}
-			for _, shard := range bm.shards {
+			for i := range bm.shards {
+				shard := &bm.shards[i]
 				if len(shard.bits) != int(tt.size/64/tt.shards) {
 					t.Errorf("NewBitMap() shard size = %v, want %v", len(shard.bits), tt.size/64/tt.shards)
 				}
@@ -142,3 +143,167 @@ func TestAllocationPerformance(t *testing.T) {
 		bm = NewBitMap(1<<20, 100)
 	}
 }
+
+func TestBitMapAllocateAligned(t *testing.T) {
+	bm := NewBitMap(1024, 4) // 4 shards × 256 bits
+
+	for i := 0; i < 8; i++ {
+		start, err := bm.AllocateAligned(3, 16)
+		if err != nil {
+			t.Fatalf("AllocateAligned() error = %v", err)
+		}
+		if start%16 != 0 {
+			t.Errorf("AllocateAligned() start = %d, not aligned to 16", start)
+		}
+	}
+	if bm.GetAvailableSpace() != 1024-8*3 {
+		t.Errorf("available space = %v, want %v", bm.GetAvailableSpace(), 1024-8*3)
+	}
+
+	// 超过单个分片大小的对齐无法满足时应返回错误
+	if _, err := bm.AllocateAligned(300, 256); err == nil {
+		t.Error("AllocateAligned() should fail when size exceeds shard")
+	}
+}
+
+func TestBitMapReserve(t *testing.T) {
+	bm := NewBitMap(1024, 4) // 4 shards × 256 bits
+
+	// 跨越分片 0 和 1 的边界
+	if err := bm.Reserve(250, 20); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if bm.GetAvailableSpace() != 1024-20 {
+		t.Errorf("available space = %v, want %v", bm.GetAvailableSpace(), 1024-20)
+	}
+	if err := bm.Reserve(260, 4); err != ErrRangeInUse {
+		t.Errorf("Reserve() overlapping error = %v, want ErrRangeInUse", err)
+	}
+	if err := bm.Reserve(1020, 8); err != ErrOutOfRange {
+		t.Errorf("Reserve() out of range error = %v, want ErrOutOfRange", err)
+	}
+
+	if err := bm.Free(250, 20); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if bm.GetAvailableSpace() != 1024 {
+		t.Errorf("available space = %v, want 1024", bm.GetAvailableSpace())
+	}
+}
+
+func TestBitMapAllocateUpTo(t *testing.T) {
+	bm := NewBitMap(128, 1)
+	bm.Reserve(10, 1)
+	bm.Reserve(50, 1)
+
+	start, size, err := bm.AllocateUpTo(100, 20)
+	if err != nil {
+		t.Fatalf("AllocateUpTo() error = %v", err)
+	}
+	if start != 11 || size != 39 {
+		t.Errorf("AllocateUpTo() = (%d, %d), want (11, 39)", start, size)
+	}
+	start, size, err = bm.AllocateUpTo(16, 1)
+	if err != nil || start != 0 || size != 10 {
+		t.Errorf("AllocateUpTo() = (%d, %d, %v), want (0, 10, nil)", start, size, err)
+	}
+	if _, _, err := bm.AllocateUpTo(100, 100); err != ErrNoSpaceLeft {
+		t.Errorf("AllocateUpTo() error = %v, want ErrNoSpaceLeft", err)
+	}
+}
+
+func TestBitMapBatch(t *testing.T) {
+	bm := NewBitMap(256, 2) // 2 shards × 128 bits
+
+	starts, ok := bm.AllocateBatch([]uint64{64, 64, 64, 64, 1})
+	for i := 0; i < 4; i++ {
+		if !ok[i] {
+			t.Fatalf("AllocateBatch() item %d failed", i)
+		}
+	}
+	if ok[4] {
+		t.Errorf("AllocateBatch() item 4 should fail when full")
+	}
+
+	blocks := make([]BTreeBlock, 0, 4)
+	for i := 0; i < 4; i++ {
+		blocks = append(blocks, BTreeBlock{Start: starts[i], Size: 64})
+	}
+	if err := bm.FreeBatch(blocks); err != nil {
+		t.Fatalf("FreeBatch() error = %v", err)
+	}
+	if bm.GetAvailableSpace() != 256 {
+		t.Errorf("available space = %v, want 256", bm.GetAvailableSpace())
+	}
+}
+
+func TestBitMapSnapshotCopyOnWrite(t *testing.T) {
+	bm := NewBitMap(1280, 2)
+	start, err := bm.Allocate(10)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+
+	frozen := bm.snapshot()
+	before := make([][]uint64, len(frozen))
+	for i := range frozen {
+		before[i] = append([]uint64(nil), frozen[i]...)
+	}
+
+	// 快照之后的修改不能影响快照持有的数组
+	if err := bm.Free(start, 10); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := bm.Allocate(5); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+	}
+	for i := range frozen {
+		for j := range frozen[i] {
+			if frozen[i][j] != before[i][j] {
+				t.Fatalf("snapshot shard %d word %d changed from %x to %x", i, j, before[i][j], frozen[i][j])
+			}
+		}
+	}
+	if got := bm.GetAvailableSpace(); got != 1280-100 {
+		t.Errorf("GetAvailableSpace() = %d, want %d", got, 1280-100)
+	}
+}
+
+func TestBitMapFreeCounter(t *testing.T) {
+	bm := NewBitMap(1024, 4) // 4 shards × 256 bits
+
+	check := func(step string) {
+		t.Helper()
+		if got, want := bm.GetAvailableSpace(), bm.countFree(); got != want {
+			t.Fatalf("%s: free counter = %d, bitmap has %d free units", step, got, want)
+		}
+	}
+
+	start, err := bm.Allocate(10)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	bm.AllocateBatch([]uint64{5, 7})
+	bm.AllocateUpTo(40, 1)
+	bm.AllocateAligned(3, 16)
+	check("allocate")
+
+	// 重放时可能重复标记或重复释放同一段空间，只按实际变化的位计数
+	bm.markUsed(start, 20)
+	check("markUsed over used units")
+	bm.Free(start, 20)
+	bm.Free(start, 20)
+	check("double free")
+	bm.Reserve(250, 20)
+	bm.FreeBatch([]BTreeBlock{{Start: 250, Size: 10}, {Start: 250, Size: 20}})
+	check("FreeBatch")
+
+	bm.setWord(1, 0, ^uint64(0))
+	bm.setWord(1, 0, 0xff)
+	check("setWord")
+	if got := bm.GetAvailableSpace(); got == 1024 {
+		t.Errorf("GetAvailableSpace() = %d, want less than 1024", got)
+	}
+}
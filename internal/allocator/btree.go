@@ -1,6 +1,8 @@
 package allocator
 
 import (
+	"cmp"
+	"slices"
 	"sync"
 
 	"github.com/google/btree"
@@ -16,7 +18,12 @@ type BlockBySize struct {
 }
 
 func (b BlockBySize) Less(than btree.Item) bool {
-	return b.Size < than.(BlockBySize).Size
+	other := than.(BlockBySize)
+	if b.Size != other.Size {
+		return b.Size < other.Size
+	}
+	// 相同大小的块按起始地址区分，避免互相覆盖
+	return b.Start < other.Start
 }
 
 type BlockByStart struct {
@@ -33,6 +40,24 @@ type BTreeManager struct {
 	mu          sync.RWMutex
 	totalSpace  uint64
 	freeSpace   uint64
+	policy      PlacementPolicy
+	cursor      uint64 // next-fit 的游标
+
+	// fence 不为 0 时 [fence, totalSpace) 正在收缩，其中的空闲块移出两棵索引保存在 fenced 中，不参与分配，
+	// fenced 按起点排序且已合并，不计入 freeSpace
+	fence  uint64
+	fenced []BTreeBlock
+
+	// dirty 记录上次检查点之后空闲状态发生变化的范围，tracking 为 false 时不记录
+	tracking   bool
+	dirty      []BTreeBlock
+	dirtyLimit int
+}
+
+// treeRange 是空闲树中发生过变化的范围及其中的空闲块，空闲块已裁剪到范围之内
+type treeRange struct {
+	Range BTreeBlock
+	Free  []BTreeBlock
 }
 
 func NewBTreeManager(totalSpace uint64) *BTreeManager {
@@ -41,6 +66,7 @@ func NewBTreeManager(totalSpace uint64) *BTreeManager {
 		treeByStart: btree.New(32),
 		totalSpace:  totalSpace,
 		freeSpace:   totalSpace,
+		policy:      PolicyBestFit,
 	}
 	block := &BTreeBlock{Start: 0, Size: totalSpace}
 	dm.treeBySize.ReplaceOrInsert(BlockBySize{block})
@@ -53,6 +79,7 @@ func NewBTreeManagerWithBlocks(totalSpace uint64, blocks []BTreeBlock) *BTreeMan
 		treeBySize:  btree.New(32),
 		treeByStart: btree.New(32),
 		totalSpace:  totalSpace,
+		policy:      PolicyBestFit,
 	}
 
 	for _, block := range blocks {
@@ -63,7 +90,66 @@ func NewBTreeManagerWithBlocks(totalSpace uint64, blocks []BTreeBlock) *BTreeMan
 	return dm
 }
 
+// SetPolicy 设置默认的放置策略，PolicyDefault 等同于 PolicyBestFit
+func (dm *BTreeManager) SetPolicy(policy PlacementPolicy) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if policy == PolicyDefault {
+		policy = PolicyBestFit
+	}
+	dm.policy = policy
+}
+
 func (dm *BTreeManager) Allocate(size uint64) (uint64, error) {
+	return dm.AllocateWithPolicy(size, PolicyDefault)
+}
+
+// AllocateWithPolicy 使用指定的放置策略分配空间，PolicyDefault 表示使用管理器的默认策略
+func (dm *BTreeManager) AllocateWithPolicy(size uint64, policy PlacementPolicy) (uint64, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	return dm.allocateLocked(size, policy)
+}
+
+// AllocateBatch 在一次加锁内依次为 sizes 中的每一项分配空间，返回每一项的起点和错误
+func (dm *BTreeManager) AllocateBatch(sizes []uint64) ([]uint64, []error) {
+	starts := make([]uint64, len(sizes))
+	errs := make([]error, len(sizes))
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	for i, size := range sizes {
+		starts[i], errs[i] = dm.allocateLocked(size, PolicyDefault)
+	}
+	return starts, errs
+}
+
+func (dm *BTreeManager) allocateLocked(size uint64, policy PlacementPolicy) (uint64, error) {
+	if dm.freeSpace < size {
+		return 0, ErrNoSpaceLeft
+	}
+
+	if policy == PolicyDefault {
+		policy = dm.policy
+	}
+	allocatedBlock := dm.findBlock(size, policy)
+	if allocatedBlock == nil {
+		return 0, ErrNoSpaceLeft
+	}
+
+	start := allocatedBlock.Start
+	dm.carve(allocatedBlock, start, size)
+	dm.cursor = start + size
+	return start, nil
+}
+
+// AllocateAligned 分配起始地址满足 (start+offset)%alignment==0 的空间，
+// offset 为本管理器第 0 个单元在全局地址空间中的位置。前导的空隙会放回空闲树
+func (dm *BTreeManager) AllocateAligned(size, alignment, offset uint64) (uint64, error) {
+	if alignment <= 1 {
+		return dm.Allocate(size)
+	}
+
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
 
@@ -72,34 +158,257 @@ func (dm *BTreeManager) Allocate(size uint64) (uint64, error) {
 	}
 
 	var allocatedBlock *BTreeBlock
+	var start uint64
 	dm.treeBySize.AscendGreaterOrEqual(BlockBySize{&BTreeBlock{Size: size}}, func(item btree.Item) bool {
 		block := item.(BlockBySize).BTreeBlock
-		allocatedBlock = block
-		return false
+		aligned := alignUp(block.Start+offset, alignment) - offset
+		if aligned+size <= block.Start+block.Size {
+			allocatedBlock = block
+			start = aligned
+			return false
+		}
+		return true
 	})
 
 	if allocatedBlock == nil {
 		return 0, ErrNoSpaceLeft
 	}
 
-	start := allocatedBlock.Start
-	dm.treeBySize.Delete(BlockBySize{allocatedBlock})
-	dm.treeByStart.Delete(BlockByStart{allocatedBlock})
+	dm.carve(allocatedBlock, start, size)
+	return start, nil
+}
+
+// AllocateUpTo 从最大的空闲块中切出至多 maxSize 个单元，最大块小于 minSize 时返回 ErrNoSpaceLeft
+func (dm *BTreeManager) AllocateUpTo(maxSize, minSize uint64) (uint64, uint64, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	item := dm.treeBySize.Max()
+	if item == nil || item.(BlockBySize).Size < minSize {
+		return 0, 0, ErrNoSpaceLeft
+	}
+	block := item.(BlockBySize).BTreeBlock
+	start, size := block.Start, min(maxSize, block.Size)
+	dm.carve(block, start, size)
+	return start, size, nil
+}
+
+// Reserve 将 [start, start+size) 从空闲树中移除，该范围必须完整地落在某个空闲块内
+func (dm *BTreeManager) Reserve(start, size uint64) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if start+size > dm.totalSpace {
+		return ErrOutOfRange
+	}
 
-	if allocatedBlock.Size > size {
-		remainingBlock := &BTreeBlock{Start: start + size, Size: allocatedBlock.Size - size}
-		dm.treeBySize.ReplaceOrInsert(BlockBySize{remainingBlock})
-		dm.treeByStart.ReplaceOrInsert(BlockByStart{remainingBlock})
+	var block *BTreeBlock
+	dm.treeByStart.DescendLessOrEqual(BlockByStart{&BTreeBlock{Start: start}}, func(item btree.Item) bool {
+		block = item.(BlockByStart).BTreeBlock
+		return false
+	})
+	if block == nil || block.Start+block.Size < start+size {
+		return ErrRangeInUse
+	}
+
+	dm.carve(block, start, size)
+	return nil
+}
+
+// overlapping 返回与 [start, end) 相交的空闲块，adjacent 为 true 时也包含首尾相接的块，调用方需持有锁
+func (dm *BTreeManager) overlapping(start, end uint64, adjacent bool) []*BTreeBlock {
+	var blocks []*BTreeBlock
+	dm.treeByStart.DescendLessOrEqual(BlockByStart{&BTreeBlock{Start: start}}, func(item btree.Item) bool {
+		block := item.(BlockByStart).BTreeBlock
+		if blockEnd := block.Start + block.Size; blockEnd > start || (adjacent && blockEnd == start) {
+			blocks = append(blocks, block)
+		}
+		return false
+	})
+	if adjacent {
+		end++
+	}
+	dm.treeByStart.AscendRange(BlockByStart{&BTreeBlock{Start: start + 1}}, BlockByStart{&BTreeBlock{Start: end}}, func(item btree.Item) bool {
+		blocks = append(blocks, item.(BlockByStart).BTreeBlock)
+		return true
+	})
+	return blocks
+}
+
+// markUsed 将 [start, start+size) 中仍空闲的部分从空闲树移除，已占用的部分保持不变。
+// 与 Reserve 不同，该操作是幂等的，用于 WAL 重放
+func (dm *BTreeManager) markUsed(start, size uint64) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	end := start + size
+	for _, block := range dm.overlapping(start, end, false) {
+		from, to := max(block.Start, start), min(block.Start+block.Size, end)
+		dm.carve(block, from, to-from)
+	}
+}
+
+// markFree 将 [start, start+size) 并入空闲树，已空闲的部分保持不变。
+// 与 Free 不同，该操作是幂等的，用于 WAL 重放
+func (dm *BTreeManager) markFree(start, size uint64) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	from, to := start, start+size
+	for _, block := range dm.overlapping(start, to, true) {
+		from, to = min(from, block.Start), max(to, block.Start+block.Size)
+		dm.treeBySize.Delete(BlockBySize{block})
+		dm.treeByStart.Delete(BlockByStart{block})
+		dm.freeSpace -= block.Size
+	}
+
+	merged := &BTreeBlock{Start: from, Size: to - from}
+	dm.treeBySize.ReplaceOrInsert(BlockBySize{merged})
+	dm.treeByStart.ReplaceOrInsert(BlockByStart{merged})
+	dm.freeSpace += merged.Size
+	dm.touch(start, size)
+}
+
+// carve 从空闲块 block 中切出 [start, start+size)，剩余的首尾部分重新插入，调用方需持有写锁
+func (dm *BTreeManager) carve(block *BTreeBlock, start, size uint64) {
+	dm.treeBySize.Delete(BlockBySize{block})
+	dm.treeByStart.Delete(BlockByStart{block})
+
+	if start > block.Start {
+		leading := &BTreeBlock{Start: block.Start, Size: start - block.Start}
+		dm.treeBySize.ReplaceOrInsert(BlockBySize{leading})
+		dm.treeByStart.ReplaceOrInsert(BlockByStart{leading})
+	}
+	if end := block.Start + block.Size; end > start+size {
+		trailing := &BTreeBlock{Start: start + size, Size: end - start - size}
+		dm.treeBySize.ReplaceOrInsert(BlockBySize{trailing})
+		dm.treeByStart.ReplaceOrInsert(BlockByStart{trailing})
 	}
 
 	dm.freeSpace -= size
-	return start, nil
+	dm.touch(start, size)
+}
+
+// snapshot 返回全部空闲块的副本。持锁期间只做 O(1) 的 Clone，之后两棵树各自写时复制，
+// 遍历在锁外进行，不阻塞分配和释放。树中的 BTreeBlock 插入后不再修改，可以安全地共享。
+// 收缩中被隔离的空闲块同样作为空闲块返回，重启后隔离不再保留
+func (dm *BTreeManager) snapshot() []BTreeBlock {
+	dm.mu.Lock()
+	clone := dm.treeByStart.Clone()
+	fenced := slices.Clone(dm.fenced)
+	dm.mu.Unlock()
+
+	blocks := make([]BTreeBlock, 0, clone.Len()+len(fenced))
+	clone.Ascend(func(item btree.Item) bool {
+		blocks = append(blocks, *item.(BlockByStart).BTreeBlock)
+		return true
+	})
+	if len(fenced) > 0 {
+		blocks = mergeRanges(append(blocks, fenced...))
+	}
+	return blocks
+}
+
+// trackDirty 启用脏跟踪，需在并发访问开始之前调用
+func (dm *BTreeManager) trackDirty() {
+	dm.tracking = true
+}
+
+// touch 记录 [start, start+size) 的空闲状态已改变，调用方需持有写锁。
+// 记录过多时先合并重叠和相邻的范围，两次检查点之间的内存占用与被修改的区域数量成正比
+func (dm *BTreeManager) touch(start, size uint64) {
+	if !dm.tracking || size == 0 {
+		return
+	}
+	dm.dirty = append(dm.dirty, BTreeBlock{Start: start, Size: size})
+	if len(dm.dirty) >= dm.dirtyLimit {
+		dm.dirty = mergeRanges(dm.dirty)
+		dm.dirtyLimit = max(2*len(dm.dirty), 1024)
+	}
+}
+
+// takeDirty 返回上次调用之后变化过的范围及其中当前的空闲块，并清空脏记录
+func (dm *BTreeManager) takeDirty() []treeRange {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	ranges := make([]treeRange, 0, len(dm.dirty))
+	for _, r := range mergeRanges(dm.dirty) {
+		end := r.Start + r.Size
+		tr := treeRange{Range: r}
+		for _, block := range dm.overlapping(r.Start, end, false) {
+			from, to := max(block.Start, r.Start), min(block.Start+block.Size, end)
+			tr.Free = append(tr.Free, BTreeBlock{Start: from, Size: to - from})
+		}
+		if dm.fence != 0 && end > dm.fence {
+			for _, block := range dm.fenced {
+				if from, to := max(block.Start, r.Start), min(block.Start+block.Size, end); from < to {
+					tr.Free = append(tr.Free, BTreeBlock{Start: from, Size: to - from})
+				}
+			}
+			tr.Free = mergeRanges(tr.Free)
+		}
+		ranges = append(ranges, tr)
+	}
+	dm.dirty = nil
+	dm.dirtyLimit = 0
+	return ranges
+}
+
+// clearDirty 丢弃全部脏记录，写完整快照前调用
+func (dm *BTreeManager) clearDirty() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.dirty = nil
+	dm.dirtyLimit = 0
+}
+
+// mergeRanges 按起点排序并合并重叠或相邻的范围
+func mergeRanges(ranges []BTreeBlock) []BTreeBlock {
+	slices.SortFunc(ranges, func(a, b BTreeBlock) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && merged[n-1].Start+merged[n-1].Size >= r.Start {
+			merged[n-1].Size = max(merged[n-1].Start+merged[n-1].Size, r.Start+r.Size) - merged[n-1].Start
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func alignUp(value, alignment uint64) uint64 {
+	return (value + alignment - 1) / alignment * alignment
 }
 
 func (dm *BTreeManager) Free(start, size uint64) error {
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
+	dm.freeLocked(start, size)
+	return nil
+}
+
+// FreeBatch 在一次加锁内释放 blocks 中的全部空间
+func (dm *BTreeManager) FreeBatch(blocks []BTreeBlock) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	for _, block := range blocks {
+		dm.freeLocked(block.Start, block.Size)
+	}
+}
 
+func (dm *BTreeManager) freeLocked(start, size uint64) {
+	if dm.fence != 0 && start+size > dm.fence {
+		from := max(start, dm.fence)
+		dm.fenced = mergeRanges(append(dm.fenced, BTreeBlock{Start: from, Size: start + size - from}))
+		dm.touch(from, start+size-from)
+		if start >= dm.fence {
+			return
+		}
+		size = dm.fence - start
+	}
 	newBlock := &BTreeBlock{Start: start, Size: size}
 
 	var prevBlock, nextBlock *BTreeBlock
@@ -131,7 +440,7 @@ func (dm *BTreeManager) Free(start, size uint64) error {
 	dm.treeBySize.ReplaceOrInsert(BlockBySize{newBlock})
 	dm.treeByStart.ReplaceOrInsert(BlockByStart{newBlock})
 	dm.freeSpace += size
-	return nil
+	dm.touch(start, size)
 }
 
 func (dm *BTreeManager) GetAvailableSpace() uint64 {
@@ -139,3 +448,17 @@ func (dm *BTreeManager) GetAvailableSpace() uint64 {
 	defer dm.mu.RUnlock()
 	return dm.freeSpace
 }
+
+// GetFragmentation 返回 1 - 最大空闲块/总空闲空间，0 表示空闲空间完全连续
+func (dm *BTreeManager) GetFragmentation() float64 {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	if dm.freeSpace == 0 {
+		return 0
+	}
+	largest := dm.treeBySize.Max()
+	if largest == nil {
+		return 0
+	}
+	return 1 - float64(largest.(BlockBySize).Size)/float64(dm.freeSpace)
+}
@@ -1,6 +1,7 @@
 package allocator
 
 import (
+	"sync"
 	"testing"
 )
 
@@ -150,3 +151,141 @@ func TestBTreeConcurrentAllocateAndFree(t *testing.T) {
 		t.Errorf("Expected available space 1024*1024, got %d", dm.GetAvailableSpace())
 	}
 }
+
+func TestBTreeAllocateAligned(t *testing.T) {
+	dm := NewBTreeManager(1024)
+
+	// 先占用一个不对齐的前缀
+	if _, err := dm.Allocate(10); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+
+	start, err := dm.AllocateAligned(100, 64, 0)
+	if err != nil {
+		t.Fatalf("AllocateAligned() error = %v", err)
+	}
+	if start != 64 {
+		t.Errorf("AllocateAligned() start = %d, want 64", start)
+	}
+	// 前导空隙 [10,64) 应回到空闲树中
+	if dm.GetAvailableSpace() != 1024-10-100 {
+		t.Errorf("Expected available space %d, got %d", 1024-10-100, dm.GetAvailableSpace())
+	}
+	start, err = dm.Allocate(54)
+	if err != nil || start != 10 {
+		t.Errorf("Allocate() = %d, %v, want 10", start, err)
+	}
+
+	// offset 使对齐按全局地址计算
+	start, err = dm.AllocateAligned(16, 128, 100)
+	if err != nil {
+		t.Fatalf("AllocateAligned() error = %v", err)
+	}
+	if (start+100)%128 != 0 {
+		t.Errorf("AllocateAligned() start %d not aligned with offset", start)
+	}
+
+	if _, err := dm.AllocateAligned(1024, 64, 0); err == nil {
+		t.Error("AllocateAligned() should fail when no aligned range fits")
+	}
+}
+
+func TestBTreeReserve(t *testing.T) {
+	dm := NewBTreeManager(1024)
+
+	if err := dm.Reserve(100, 50); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if dm.GetAvailableSpace() != 1024-50 {
+		t.Errorf("Expected available space %d, got %d", 1024-50, dm.GetAvailableSpace())
+	}
+	if err := dm.Reserve(140, 20); err != ErrRangeInUse {
+		t.Errorf("Reserve() overlapping error = %v, want ErrRangeInUse", err)
+	}
+	if err := dm.Reserve(1000, 100); err != ErrOutOfRange {
+		t.Errorf("Reserve() out of range error = %v, want ErrOutOfRange", err)
+	}
+
+	start, err := dm.AllocateWithPolicy(100, PolicyFirstFit)
+	if err != nil || start != 0 {
+		t.Errorf("Allocate() = %d, %v, want 0", start, err)
+	}
+}
+
+func TestBTreeAllocateUpTo(t *testing.T) {
+	dm := NewBTreeManagerWithBlocks(1024, []BTreeBlock{
+		{Start: 0, Size: 100},
+		{Start: 200, Size: 300},
+	})
+
+	start, size, err := dm.AllocateUpTo(250, 10)
+	if err != nil || start != 200 || size != 250 {
+		t.Errorf("AllocateUpTo() = (%d, %d, %v), want (200, 250, nil)", start, size, err)
+	}
+	start, size, err = dm.AllocateUpTo(1000, 10)
+	if err != nil || start != 0 || size != 100 {
+		t.Errorf("AllocateUpTo() = (%d, %d, %v), want (0, 100, nil)", start, size, err)
+	}
+	if _, _, err := dm.AllocateUpTo(1000, 51); err != ErrNoSpaceLeft {
+		t.Errorf("AllocateUpTo() error = %v, want ErrNoSpaceLeft", err)
+	}
+}
+
+func TestBTreeBatch(t *testing.T) {
+	dm := NewBTreeManager(1024)
+
+	starts, errs := dm.AllocateBatch([]uint64{256, 512, 512, 256})
+	if errs[0] != nil || errs[1] != nil || errs[3] != nil {
+		t.Fatalf("AllocateBatch() errors = %v", errs)
+	}
+	if errs[2] != ErrNoSpaceLeft {
+		t.Errorf("AllocateBatch() item 2 error = %v, want ErrNoSpaceLeft", errs[2])
+	}
+
+	dm.FreeBatch([]BTreeBlock{{Start: starts[0], Size: 256}, {Start: starts[1], Size: 512}, {Start: starts[3], Size: 256}})
+	if dm.GetAvailableSpace() != 1024 || dm.treeByStart.Len() != 1 {
+		t.Errorf("Expected a single free block of 1024, got %d in %d blocks", dm.GetAvailableSpace(), dm.treeByStart.Len())
+	}
+}
+
+func TestBTreeSnapshotConcurrent(t *testing.T) {
+	dm := NewBTreeManager(1 << 20)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				start, err := dm.Allocate(16)
+				if err != nil {
+					t.Errorf("Allocate() error = %v", err)
+					return
+				}
+				dm.Free(start, 16)
+			}
+		}()
+	}
+
+	// 快照在锁外遍历克隆出的树，并发的分配和释放不能影响其一致性
+	for i := 0; i < 200; i++ {
+		var total, prevEnd uint64
+		for j, block := range dm.snapshot() {
+			if j > 0 && block.Start <= prevEnd {
+				t.Fatalf("snapshot block %+v overlaps previous block ending at %d", block, prevEnd)
+			}
+			total += block.Size
+			prevEnd = block.Start + block.Size
+		}
+		if total < (1<<20)-4*16 || total > 1<<20 {
+			t.Fatalf("snapshot free space = %d, want within [%d, %d]", total, (1<<20)-4*16, 1<<20)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
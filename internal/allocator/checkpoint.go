@@ -0,0 +1,206 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+var errBrokenDeltaChain = errors.New("delta checkpoint does not continue the chain")
+
+// 增量检查点保存在 StatePersistencePath + ".delta-<序号>" 中，每个文件只包含上一个检查点之后被修改的
+// 位图字、空闲树范围和分配表记录。加载时依次应用到当前快照上，达到 DeltaCheckpointLimit 后合并为完整快照
+const deltaSuffix = ".delta-"
+
+// checkpointRef 记录当前快照的检查点 ID 和其后已写入的增量检查点个数
+type checkpointRef struct {
+	ID     uint64
+	Deltas int
+}
+
+func deltaPath(cfg *config.Config, index int) string {
+	return fmt.Sprintf("%s%s%06d", cfg.StatePersistencePath, deltaSuffix, index)
+}
+
+// listDeltas 返回全部增量检查点文件，按序号从小到大排列
+func listDeltas(cfg *config.Config) ([]string, error) {
+	dir := filepath.Dir(cfg.StatePersistencePath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state dir: %w", err)
+	}
+	prefix := filepath.Base(cfg.StatePersistencePath) + deltaSuffix
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix) && entry.Type().IsRegular() {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// removeDeltas 删除全部增量检查点，写入新的完整快照或恢复历史快照后调用
+func removeDeltas(cfg *config.Config) error {
+	paths, err := listDeltas(cfg)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deltaEnabled 判断是否启用增量检查点
+func (da *diskAllocatorImpl) deltaEnabled() bool {
+	return da.cfg.DeltaCheckpointLimit > 0 && da.cfg.StatePersistencePath != ""
+}
+
+// trackDirty 在位图、空闲树和分配表上启用脏跟踪
+func (da *diskAllocatorImpl) trackDirty() {
+	da.bitmaps.trackDirty()
+	da.tree.trackDirty()
+	da.allocations.trackDirty()
+}
+
+// clearDirty 丢弃全部脏记录，之后的修改由下一个增量检查点保存
+func (da *diskAllocatorImpl) clearDirty() {
+	if !da.deltaEnabled() {
+		return
+	}
+	da.bitmaps.clearDirty()
+	da.tree.clearDirty()
+	da.allocations.clearDirty()
+}
+
+// saveDelta 写入一个增量检查点，只包含上一个检查点之后被修改的部分。
+// 会话和 TTL 表较小，每次完整写入。WAL 不做截断，增量检查点损坏时仍可从当前快照重放
+func (da *diskAllocatorImpl) saveDelta(walSeq uint64) error {
+	index := da.checkpoint.Deltas + 1
+	data := persistentData{
+		TracksAllocations: true,
+		WALSeq:            walSeq,
+		CheckpointID:      da.checkpoint.ID,
+		DeltaIndex:        uint64(index),
+	}
+
+	// 脏记录取出后写入失败就无法再生成增量，在成功之前要求下一次写完整快照
+	da.forceFull = true
+	data.BitmapWords = da.bitmaps.takeDirty()
+	data.AllocationUpserts, data.AllocationDeletes = da.allocations.takeDirty()
+	data.NextSessionID, data.Sessions = da.leases.snapshot()
+	data.TTLs = da.ttls.snapshot()
+	data.TenantQuotas, data.TenantOwners = da.tenants.snapshot()
+	data.TreeRanges = da.tree.takeDirty()
+
+	if err := writeStateFile(deltaPath(da.cfg, index), da.fingerprint(), &data); err != nil {
+		return fmt.Errorf("failed to write delta checkpoint: %w", err)
+	}
+	da.checkpoint.Deltas = index
+	da.forceFull = false
+	return nil
+}
+
+// loadDeltas 依次应用基于当前快照的增量检查点，返回最后一个已应用的检查点包含的 WAL 序号。
+// 增量链在缺失、损坏或不属于当前快照处中断，之后的文件被忽略，下一次保存写完整快照并删除它们
+func (da *diskAllocatorImpl) loadDeltas(walSeq uint64) (uint64, error) {
+	paths, err := listDeltas(da.cfg)
+	if err != nil {
+		return 0, err
+	}
+	for i, path := range paths {
+		index := i + 1
+		data, err := readDelta(da.fingerprint(), path)
+		if err == nil && (da.checkpoint.ID == 0 || path != deltaPath(da.cfg, index) ||
+			data.CheckpointID != da.checkpoint.ID || data.DeltaIndex != uint64(index)) {
+			err = errBrokenDeltaChain
+		}
+		if err == nil {
+			err = da.applyDelta(data)
+		}
+		if err != nil {
+			log.Printf("ignoring delta checkpoints from %s: %v", path, err)
+			da.forceFull = true
+			return walSeq, nil
+		}
+		walSeq = data.WALSeq
+		da.checkpoint.Deltas = index
+	}
+	return walSeq, nil
+}
+
+func readDelta(want stateFingerprint, path string) (*persistentData, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, fp, legacy, err := decodeState(file)
+	if err != nil {
+		return nil, err
+	}
+	if legacy || data.DeltaIndex == 0 {
+		return nil, fmt.Errorf("%w: not a delta checkpoint", ErrCorruptState)
+	}
+	if fp != want {
+		return nil, fmt.Errorf("%w: file has %v, config has %v", ErrConfigMismatch, fp, want)
+	}
+	return data, nil
+}
+
+// applyDelta 将增量检查点作用到内存状态，校验全部通过后才做修改
+func (da *diskAllocatorImpl) applyDelta(data *persistentData) error {
+	for _, word := range data.BitmapWords {
+		if word.Shard >= uint64(len(da.bitmaps.shards)) || word.Index >= uint64(len(da.bitmaps.shards[word.Shard].bits)) {
+			return fmt.Errorf("%w: bitmap word %d/%d out of range", ErrCorruptState, word.Shard, word.Index)
+		}
+	}
+	for _, r := range data.TreeRanges {
+		if r.Range.Start+r.Range.Size > da.tree.totalSpace {
+			return fmt.Errorf("%w: tree range %+v out of range", ErrCorruptState, r.Range)
+		}
+		for _, block := range r.Free {
+			if block.Start < r.Range.Start || block.Start+block.Size > r.Range.Start+r.Range.Size {
+				return fmt.Errorf("%w: free block %+v outside tree range %+v", ErrCorruptState, block, r.Range)
+			}
+		}
+	}
+
+	for _, word := range data.BitmapWords {
+		da.bitmaps.setWord(word.Shard, word.Index, word.Bits)
+	}
+	// 范围内先整体标记为已占用，再放回检查点时的空闲块；与范围外相邻的空闲块会被重新合并
+	for _, r := range data.TreeRanges {
+		da.tree.markUsed(r.Range.Start, r.Range.Size)
+		for _, block := range r.Free {
+			da.tree.markFree(block.Start, block.Size)
+		}
+	}
+	da.allocations.applyDelta(data.AllocationUpserts, data.AllocationDeletes)
+	da.leases.load(data.NextSessionID, data.Sessions)
+	da.ttls.load(data.TTLs)
+	da.tenants.load(data.TenantQuotas, data.TenantOwners)
+	return nil
+}
+
+// newCheckpointID 为完整快照生成一个非零的随机 ID
+func newCheckpointID() uint64 {
+	for {
+		if id := random.Uint64(); id != 0 {
+			return id
+		}
+	}
+}
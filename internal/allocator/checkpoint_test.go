@@ -0,0 +1,286 @@
+package allocator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/btree"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newCheckpointTestConfig(dir string, walPolicy string) *config.Config {
+	return &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: filepath.Join(dir, "state"),
+		BackupIntervalSec:    3600,
+		WALSyncPolicy:        walPolicy,
+		DeltaCheckpointLimit: 3,
+	}
+}
+
+func blocksOf(tree *BTreeManager) []BTreeBlock {
+	var blocks []BTreeBlock
+	tree.treeByStart.Ascend(func(item btree.Item) bool {
+		blocks = append(blocks, *item.(BlockByStart).BTreeBlock)
+		return true
+	})
+	return blocks
+}
+
+// checkTreeConsistent 检查空闲树的两个索引和 freeSpace 是否一致，且相邻空闲块都已合并
+func checkTreeConsistent(t *testing.T, tree *BTreeManager) {
+	t.Helper()
+	if tree.treeBySize.Len() != tree.treeByStart.Len() {
+		t.Errorf("treeBySize has %d blocks, treeByStart has %d", tree.treeBySize.Len(), tree.treeByStart.Len())
+	}
+	var free, prevEnd uint64
+	for i, block := range blocksOf(tree) {
+		if i > 0 && block.Start <= prevEnd {
+			t.Errorf("free block %+v overlaps or touches previous block ending at %d", block, prevEnd)
+		}
+		free += block.Size
+		prevEnd = block.Start + block.Size
+	}
+	if free != tree.freeSpace {
+		t.Errorf("free blocks sum to %d, freeSpace = %d", free, tree.freeSpace)
+	}
+}
+
+func TestDeltaCheckpointChain(t *testing.T) {
+	cfg := newCheckpointTestConfig(t.TempDir(), "")
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	var addresses []uint64
+	for _, size := range []uint64{4096, 64 * 4096, 1024 * 1024, 3 * 1024 * 1024} {
+		address, err := da.Allocate(size)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	base, err := os.ReadFile(cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	// 两个增量检查点，第二个释放了第一个中分配的空间
+	extra, err := da.Allocate(2 * 1024 * 1024)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.Free(addresses[1], 64*4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if err := da.Free(extra, 2*1024*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if _, err := da.Allocate(8 * 4096); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	current, err := os.ReadFile(cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(current) != string(base) {
+		t.Errorf("state file was rewritten by a delta checkpoint")
+	}
+	paths, err := listDeltas(cfg)
+	if err != nil {
+		t.Fatalf("listDeltas() error = %v", err)
+	}
+	if len(paths) != 2 || paths[0] != deltaPath(cfg, 1) || paths[1] != deltaPath(cfg, 2) {
+		t.Fatalf("listDeltas() = %v, want deltas 1 and 2", paths)
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.Size() >= int64(len(base)) {
+			t.Errorf("delta %s: info = %v, err = %v, want smaller than base snapshot (%d bytes)", path, info, err, len(base))
+		}
+	}
+
+	utilization := da.GetDiskUtilization()
+	freeBlocks := blocksOf(da.tree)
+	allocations := da.allocations.snapshot()
+	crash(da)
+
+	recovered := loadSnapshotTestAllocator(t, cfg)
+	defer recovered.Close()
+	if got := recovered.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after recovery = %v, want %v", got, utilization)
+	}
+	checkTreeConsistent(t, recovered.tree)
+	if got := blocksOf(recovered.tree); !reflect.DeepEqual(got, freeBlocks) {
+		t.Errorf("free blocks after recovery = %v, want %v", got, freeBlocks)
+	}
+	if err := recovered.Free(extra, 2*1024*1024); err != ErrNotAllocated {
+		t.Errorf("Free(extra) error = %v, want %v", err, ErrNotAllocated)
+	}
+	if got := recovered.allocations.snapshot(); !reflect.DeepEqual(got, allocations) {
+		t.Errorf("allocations after recovery = %v, want %v", got, allocations)
+	}
+	if err := recovered.Free(addresses[2], 1024*1024); err != nil {
+		t.Errorf("Free(addresses[2]) error = %v", err)
+	}
+}
+
+func TestDeltaCheckpointCompaction(t *testing.T) {
+	cfg := newCheckpointTestConfig(t.TempDir(), "group")
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	// 第一次保存写完整快照，之后写 DeltaCheckpointLimit 个增量，再合并为完整快照
+	for i := 0; i <= cfg.DeltaCheckpointLimit+1; i++ {
+		if _, err := da.Allocate(1024 * 1024); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if err := da.SaveState(); err != nil {
+			t.Fatalf("SaveState() error = %v", err)
+		}
+		paths, err := listDeltas(cfg)
+		if err != nil {
+			t.Fatalf("listDeltas() error = %v", err)
+		}
+		want := i
+		if i > cfg.DeltaCheckpointLimit {
+			want = 0
+		}
+		if len(paths) != want {
+			t.Fatalf("after save %d: %d delta checkpoints, want %d", i, len(paths), want)
+		}
+	}
+
+	// WAL 只在写完整快照时截断
+	records, _, err := readWAL(walPath(cfg))
+	if err != nil {
+		t.Fatalf("readWAL() error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("wal after compaction has %d records, want 0", len(records))
+	}
+}
+
+func TestDeltaCheckpointBrokenChain(t *testing.T) {
+	cfg := newCheckpointTestConfig(t.TempDir(), "group")
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	var addresses []uint64
+	for i := 0; i < 3; i++ {
+		address, err := da.Allocate(1024 * 1024)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		addresses = append(addresses, address)
+		if err := da.SaveState(); err != nil {
+			t.Fatalf("SaveState() error = %v", err)
+		}
+	}
+	if err := da.Free(addresses[0], 1024*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	utilization := da.GetDiskUtilization()
+	crash(da)
+
+	// 第二个增量损坏后被忽略，其中的修改从 WAL 重放
+	raw, err := os.ReadFile(deltaPath(cfg, 2))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	raw[len(raw)/2] ^= 0x10
+	if err := os.WriteFile(deltaPath(cfg, 2), raw, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	recovered := loadSnapshotTestAllocator(t, cfg)
+	defer recovered.Close()
+	if got := recovered.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after recovery = %v, want %v", got, utilization)
+	}
+	checkTreeConsistent(t, recovered.tree)
+	if err := recovered.Free(addresses[2], 1024*1024); err != nil {
+		t.Errorf("Free(addresses[2]) error = %v", err)
+	}
+	if !recovered.forceFull {
+		t.Fatalf("forceFull = false after broken delta chain")
+	}
+	if err := recovered.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if paths, err := listDeltas(cfg); err != nil || len(paths) != 0 {
+		t.Errorf("listDeltas() after full save = %v, %v, want none", paths, err)
+	}
+}
+
+func TestDeltaCheckpointReplacesSessionsAndTTLs(t *testing.T) {
+	cfg := newCheckpointTestConfig(t.TempDir(), "")
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	withTTL, err := da.AllocateWithTTL(8192, time.Minute)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	id, _ := da.OpenSession(time.Minute)
+	inSession, err := da.AllocateInSession(id, 8192)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	// 基准快照之后释放两段空间，再由不带 TTL、不属于会话的分配占用相同的起点
+	if err := da.Free(withTTL, 8192); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if err := da.Commit(id, inSession, 8192); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := da.CloseSession(id); err != nil {
+		t.Fatalf("CloseSession() error = %v", err)
+	}
+	if err := da.Free(inSession, 8192); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	for _, address := range []uint64{withTTL, inSession} {
+		if err := da.Reserve(address, 8192); err != nil {
+			t.Fatalf("Reserve(%d) error = %v", address, err)
+		}
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if da.checkpoint.Deltas != 1 {
+		t.Fatalf("checkpoint deltas = %d, want 1", da.checkpoint.Deltas)
+	}
+	crash(da)
+
+	// 增量检查点中的会话和 TTL 替换基准快照中的，重新分配的空间不会被回收
+	da = loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	if stats := da.GetTTLStats(); stats.Tracked != 0 {
+		t.Errorf("GetTTLStats().Tracked = %d, want 0", stats.Tracked)
+	}
+	later := time.Now().Add(time.Hour)
+	da.reapExpired(later)
+	da.expireSessions(later)
+	for _, address := range []uint64{withTTL, inSession} {
+		if err := da.Free(address, 8192); err != nil {
+			t.Errorf("Free(%d) after restart error = %v", address, err)
+		}
+	}
+}
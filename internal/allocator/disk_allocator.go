@@ -8,24 +8,98 @@ import (
 	"time"
 
 	"github.com/li1213987842/spaceweave/config"
+	pb "github.com/li1213987842/spaceweave/proto"
 )
 
 const MiBThreshold = 64 //64 * 4KB = 256kb
 
-var ErrNoSpaceLeft = errors.New("no space left")
+var (
+	ErrNoSpaceLeft      = errors.New("no space left")
+	ErrInvalidAlignment = errors.New("alignment must be a multiple of unit size")
+	ErrRangeInUse       = errors.New("range already allocated")
+	ErrOutOfRange       = errors.New("range out of bounds")
+	ErrNotAllocated     = errors.New("address not allocated")
+	ErrSizeMismatch     = errors.New("size does not match allocation")
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrNotLeased        = errors.New("allocation not leased by session")
+	ErrInvalidTTL       = errors.New("ttl must be positive")
+	ErrNoTTL            = errors.New("allocation has no ttl")
+	ErrInvalidTotalSize = errors.New("invalid total size")
+)
 
 type DiskAllocator interface {
 	Allocate(size uint64) (uint64, error)
+	AllocateWithPolicy(size uint64, policy PlacementPolicy) (uint64, error)
+	AllocateAligned(size uint64, alignment uint64) (uint64, error)
+	AllocateWithTTL(size uint64, ttl time.Duration) (uint64, error)
+	ExtendTTL(address uint64, ttl time.Duration) error
+	GetTTLStats() TTLStats
+	AllocateExtents(size uint64, maxExtents int, minExtentSize uint64) ([]Extent, error)
+	BatchAllocate(sizes []uint64) []AllocateResult
+	Reserve(address uint64, size uint64) error
+	Resize(address uint64, oldSize uint64, newSize uint64) (newAddress uint64, moved bool, err error)
 	Free(address uint64, size uint64) error
+	FreeExtents(extents []Extent) error
+	BatchFree(extents []Extent) []error
+	OpenSession(ttl time.Duration) (uint64, error)
+	KeepAliveSession(sessionID uint64) error
+	AllocateInSession(sessionID uint64, size uint64) (uint64, error)
+	Commit(sessionID uint64, address uint64, size uint64) error
+	CloseSession(sessionID uint64) error
 	GetDiskUtilization() float64
+	GetAllocationCount() int
+	Verify(repair bool) VerifyReport
+	GetVerifyStats() VerifyStats
+	ExportMap() *pb.AllocationMap
+	ImportMap(m *pb.AllocationMap) (int, error)
+	Grow(newTotalSize uint64) error
+	Shrink(newTotalSize uint64) (relocate []Extent, done bool, err error)
+	GetTotalSize() uint64
+	ForTenant(tenant string) *TenantAllocator
+	SetTenantQuota(q TenantQuota) error
+	GetTenantUsage(tenant string) []TenantUsage
+	GetReservationStats() ReservationStats
 	SaveState() error
 	Close() error
 }
 
+// Extent 表示一段连续的已分配空间，地址和大小均以字节为单位
+type Extent struct {
+	Address uint64
+	Size    uint64
+}
+
+// AllocateResult 是批量分配中单个请求的结果
+type AllocateResult struct {
+	Address uint64
+	Err     error
+}
+
 type diskAllocatorImpl struct {
-	bitmaps *ConcurrentBitMap
-	tree    *BTreeManager
-	cfg     *config.Config
+	bitmaps     *ConcurrentBitMap
+	tree        *BTreeManager
+	allocations *allocationTable
+	leases      *leaseTable
+	ttls        *ttlTable
+	tenants     *tenantTable
+	wal         *walLog
+	cfg         *config.Config
+
+	// total 是当前管理的字节数，初始为 cfg.TotalSize，Grow 在运行时扩大，原子读写
+	total uint64
+
+	// 修改分配状态的操作持读锁完成“写 WAL + 修改内存”，
+	// SaveState 持写锁读取快照序号，保证该序号之前的记录都已作用到内存
+	walMu sync.RWMutex
+
+	// saveMu 串行化 SaveState，current 记录当前快照的时间和 WAL 序号，
+	// checkpoint 记录当前快照之后已写入的增量检查点，forceFull 为 true 时下一次保存写完整快照
+	saveMu     sync.Mutex
+	current    snapshotRef
+	checkpoint checkpointRef
+	forceFull  bool
+
+	verify verifyCounters
 
 	operationCount        int64
 	lastBackupTime        time.Time
@@ -86,15 +160,30 @@ func (da *diskAllocatorImpl) incrementOperationCount() {
 	atomic.AddInt64(&da.operationCount, 1)
 }
 
-func (da *diskAllocatorImpl) Allocate(size uint64) (start uint64, err error) {
+func (da *diskAllocatorImpl) Allocate(size uint64) (uint64, error) {
+	return da.AllocateWithPolicy(size, PolicyDefault)
+}
+
+// AllocateWithPolicy 分配空间，policy 仅作用于大块（B 树）区域
+func (da *diskAllocatorImpl) AllocateWithPolicy(size uint64, policy PlacementPolicy) (uint64, error) {
+	return da.allocateWithPolicy("", size, policy)
+}
+
+// allocateWithPolicy 以 tenant 的名义分配空间，tenant 为空时不计配额
+func (da *diskAllocatorImpl) allocateWithPolicy(tenant string, size uint64, policy PlacementPolicy) (start uint64, err error) {
 	units := (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize // Round up to nearest unit
+	adm, err := da.admit(tenant, units)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { adm.finish(err, start) }()
 	if units <= MiBThreshold {
 		start, err = da.allocateSmall(units)
 		if err == nil {
 			return start, nil
 		}
 	}
-	start, err = da.allocateLarge(units)
+	start, err = da.allocateLarge(units, policy)
 	if err == nil {
 		return start, nil
 	}
@@ -102,28 +191,435 @@ func (da *diskAllocatorImpl) Allocate(size uint64) (start uint64, err error) {
 	return da.allocateSmall(units)
 }
 
+// AllocateAligned 分配起始地址为 alignment 整数倍的空间，alignment 为 0 时等同于 Allocate
+func (da *diskAllocatorImpl) AllocateAligned(size uint64, alignment uint64) (uint64, error) {
+	return da.allocateAligned("", size, alignment)
+}
+
+func (da *diskAllocatorImpl) allocateAligned(tenant string, size uint64, alignment uint64) (start uint64, err error) {
+	if alignment == 0 {
+		return da.allocateWithPolicy(tenant, size, PolicyDefault)
+	}
+	if alignment%da.cfg.UnitSize != 0 {
+		return 0, ErrInvalidAlignment
+	}
+
+	units := (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize // Round up to nearest unit
+	alignUnits := alignment / da.cfg.UnitSize
+	adm, err := da.admit(tenant, units)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { adm.finish(err, start) }()
+	if units <= MiBThreshold {
+		start, err = da.allocateSmallAligned(units, alignUnits)
+		if err == nil {
+			return start, nil
+		}
+	}
+	start, err = da.allocateLargeAligned(units, alignUnits)
+	if err == nil {
+		return start, nil
+	}
+
+	return da.allocateSmallAligned(units, alignUnits)
+}
+
+func (da *diskAllocatorImpl) allocateSmallAligned(units, alignUnits uint64) (uint64, error) {
+	start, err := da.bitmaps.AllocateAligned(units, alignUnits)
+	if err != nil {
+		return 0, err
+	}
+	if err := da.commitAllocs(BTreeBlock{Start: start, Size: units}); err != nil {
+		return 0, err
+	}
+	da.incrementOperationCount()
+	return start * da.cfg.UnitSize, nil
+}
+
+func (da *diskAllocatorImpl) allocateLargeAligned(units, alignUnits uint64) (uint64, error) {
+	start, err := da.tree.AllocateAligned(units, alignUnits, da.cfg.SmallBlockLimit)
+	if err != nil {
+		return 0, err
+	}
+	start += da.cfg.SmallBlockLimit
+	if err := da.commitAllocs(BTreeBlock{Start: start, Size: units}); err != nil {
+		return 0, err
+	}
+	da.incrementOperationCount()
+	return start * da.cfg.UnitSize, nil
+}
+
 func (da *diskAllocatorImpl) allocateSmall(units uint64) (uint64, error) {
 	start, err := da.bitmaps.Allocate(units)
 	if err != nil {
 		return 0, err
 	}
+	if err := da.commitAllocs(BTreeBlock{Start: start, Size: units}); err != nil {
+		return 0, err
+	}
 	da.incrementOperationCount()
 	return start * da.cfg.UnitSize, nil
 }
 
-func (da *diskAllocatorImpl) allocateLarge(units uint64) (uint64, error) {
-	start, err := da.tree.Allocate(units)
+func (da *diskAllocatorImpl) allocateLarge(units uint64, policy PlacementPolicy) (uint64, error) {
+	start, err := da.tree.AllocateWithPolicy(units, policy)
 	if err != nil {
 		return 0, err
 	}
+	start += da.cfg.SmallBlockLimit
+	if err := da.commitAllocs(BTreeBlock{Start: start, Size: units}); err != nil {
+		return 0, err
+	}
+	da.incrementOperationCount()
+	return start * da.cfg.UnitSize, nil
+}
+
+// AllocateExtents 分配总大小为 size 的空间，无法找到单段连续空间时由至多 maxExtents 段
+// （0 表示不限）、每段不小于 minExtentSize 的空间拼接而成。失败时不会保留任何已分配的片段。
+// 返回的每段大小都是单元的整数倍，各段之和为 size 向上取整到单元
+func (da *diskAllocatorImpl) AllocateExtents(size uint64, maxExtents int, minExtentSize uint64) ([]Extent, error) {
+	return da.allocateExtents("", size, maxExtents, minExtentSize)
+}
+
+func (da *diskAllocatorImpl) allocateExtents(tenant string, size uint64, maxExtents int, minExtentSize uint64) (extents []Extent, err error) {
+	if address, err := da.allocateWithPolicy(tenant, size, PolicyDefault); err == nil {
+		return []Extent{{Address: address, Size: da.roundUp(size)}}, nil
+	}
+
+	remaining := (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize
+	minUnits := max((minExtentSize+da.cfg.UnitSize-1)/da.cfg.UnitSize, 1)
+	adm, err := da.admit(tenant, remaining)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		addresses := make([]uint64, len(extents))
+		for i, e := range extents {
+			addresses[i] = e.Address
+		}
+		adm.finish(err, addresses...)
+	}()
+	if da.bitmaps.GetAvailableSpace()+da.tree.GetAvailableSpace() < remaining {
+		return nil, ErrNoSpaceLeft
+	}
+
+	extents = make([]Extent, 0)
+	for remaining > 0 {
+		if maxExtents > 0 && len(extents) >= maxExtents {
+			da.FreeExtents(extents)
+			return nil, ErrNoSpaceLeft
+		}
+		want := min(minUnits, remaining)
+		start, units, err := da.tree.AllocateUpTo(remaining, want)
+		if err == nil {
+			start += da.cfg.SmallBlockLimit
+		} else if start, units, err = da.bitmaps.AllocateUpTo(remaining, want); err != nil {
+			da.FreeExtents(extents)
+			return nil, err
+		}
+		if err := da.commitAllocs(BTreeBlock{Start: start, Size: units}); err != nil {
+			da.FreeExtents(extents)
+			return nil, err
+		}
+		extents = append(extents, Extent{Address: start * da.cfg.UnitSize, Size: units * da.cfg.UnitSize})
+		remaining -= units
+	}
 	da.incrementOperationCount()
-	return (start + da.cfg.SmallBlockLimit) * da.cfg.UnitSize, nil
+	return extents, nil
+}
+
+// BatchAllocate 批量分配，小块在每个位图分片上只加锁一次，大块共用一次 B 树加锁。
+// 与 Allocate 相同，位图和 B 树之间会互相兜底
+func (da *diskAllocatorImpl) BatchAllocate(sizes []uint64) []AllocateResult {
+	return da.batchAllocate("", sizes)
+}
+
+func (da *diskAllocatorImpl) batchAllocate(tenant string, sizes []uint64) []AllocateResult {
+	results := make([]AllocateResult, len(sizes))
+	units := make([]uint64, len(sizes))
+	admissions := make([]*admission, len(sizes))
+	var small, large []int
+	for i, size := range sizes {
+		units[i] = (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize // Round up to nearest unit
+		// 按顺序检查配额和预留，超过硬配额或会占用剩余预留的项不参与分配
+		adm, err := da.admit(tenant, units[i])
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		admissions[i] = adm
+		if units[i] <= MiBThreshold {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	large = append(da.batchAllocateSmall(small, units, results), large...)
+	failed := da.batchAllocateLarge(large, units, results)
+	for _, i := range da.batchAllocateSmall(failed, units, results) {
+		results[i].Err = ErrNoSpaceLeft
+	}
+	for i, adm := range admissions {
+		if adm != nil {
+			adm.finish(results[i].Err, results[i].Address)
+		}
+	}
+	return results
+}
+
+// batchAllocateSmall 在位图区分配 indexes 对应的请求，返回分配失败的下标
+func (da *diskAllocatorImpl) batchAllocateSmall(indexes []int, units []uint64, results []AllocateResult) []int {
+	if len(indexes) == 0 {
+		return nil
+	}
+	sizes := make([]uint64, len(indexes))
+	for j, i := range indexes {
+		sizes[j] = units[i]
+	}
+	starts, ok := da.bitmaps.AllocateBatch(sizes)
+
+	var failed, done []int
+	var blocks []BTreeBlock
+	for j, i := range indexes {
+		if !ok[j] {
+			failed = append(failed, i)
+			continue
+		}
+		done = append(done, i)
+		blocks = append(blocks, BTreeBlock{Start: starts[j], Size: units[i]})
+	}
+	da.commitBatch(done, blocks, results)
+	return failed
+}
+
+// batchAllocateLarge 在 B 树区分配 indexes 对应的请求，返回分配失败的下标
+func (da *diskAllocatorImpl) batchAllocateLarge(indexes []int, units []uint64, results []AllocateResult) []int {
+	if len(indexes) == 0 {
+		return nil
+	}
+	sizes := make([]uint64, len(indexes))
+	for j, i := range indexes {
+		sizes[j] = units[i]
+	}
+	starts, errs := da.tree.AllocateBatch(sizes)
+
+	var failed, done []int
+	var blocks []BTreeBlock
+	for j, i := range indexes {
+		if errs[j] != nil {
+			failed = append(failed, i)
+			continue
+		}
+		done = append(done, i)
+		blocks = append(blocks, BTreeBlock{Start: starts[j] + da.cfg.SmallBlockLimit, Size: units[i]})
+	}
+	da.commitBatch(done, blocks, results)
+	return failed
+}
+
+// commitBatch 提交批量分配中已占用的单元范围，blocks[j] 对应 results[indexes[j]]
+func (da *diskAllocatorImpl) commitBatch(indexes []int, blocks []BTreeBlock, results []AllocateResult) {
+	if len(blocks) == 0 {
+		return
+	}
+	err := da.commitAllocs(blocks...)
+	for j, i := range indexes {
+		if err != nil {
+			results[i] = AllocateResult{Err: err}
+			continue
+		}
+		da.incrementOperationCount()
+		results[i] = AllocateResult{Address: blocks[j].Start * da.cfg.UnitSize}
+	}
+}
+
+// commitAllocs 为已在位图或 B 树中占用的单元范围写 WAL，再登记到分配表。
+// WAL 写入失败时归还这些单元并返回错误
+func (da *diskAllocatorImpl) commitAllocs(blocks ...BTreeBlock) error {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+	return da.commitAllocsLocked(blocks...)
+}
+
+// commitAllocsLocked 同 commitAllocs，调用方需持有 walMu
+func (da *diskAllocatorImpl) commitAllocsLocked(blocks ...BTreeBlock) error {
+	records := make([]walRecord, len(blocks))
+	for i, b := range blocks {
+		records[i] = walRecord{Op: walOpAlloc, Start: b.Start, Units: b.Size}
+	}
+	if err := da.wal.append(records...); err != nil {
+		for _, b := range blocks {
+			da.freeUnits(b.Start, b.Size)
+		}
+		return err
+	}
+	for _, b := range blocks {
+		da.allocations.insert(b.Start, b.Size)
+	}
+	return nil
+}
+
+// Reserve 占用指定的地址范围（如超级块、元数据区或从旧分配器导入的范围），
+// 范围可以跨越位图区和 B 树区，任一部分已被占用时整体失败
+func (da *diskAllocatorImpl) Reserve(address uint64, size uint64) error {
+	if size == 0 {
+		return nil
+	}
+	if address+size > da.totalSize() {
+		return ErrOutOfRange
+	}
+	start := address / da.cfg.UnitSize
+	end := (address + size + da.cfg.UnitSize - 1) / da.cfg.UnitSize
+
+	adm, err := da.admit("", end-start)
+	if err != nil {
+		return err
+	}
+	defer adm.release()
+	if err := da.reserveUnits(start, end-start); err != nil {
+		return err
+	}
+	if err := da.commitAllocs(BTreeBlock{Start: start, Size: end - start}); err != nil {
+		return err
+	}
+	da.incrementOperationCount()
+	return nil
+}
+
+// reserveUnits 在位图区和 B 树区中占用单元范围 [start, start+units)，失败时回滚已占用的部分
+func (da *diskAllocatorImpl) reserveUnits(start, units uint64) error {
+	end := start + units
+	if end > da.totalSize()/da.cfg.UnitSize {
+		return ErrOutOfRange
+	}
+
+	var smallUnits uint64
+	if start < da.cfg.SmallBlockLimit {
+		smallUnits = min(end, da.cfg.SmallBlockLimit) - start
+		if err := da.bitmaps.Reserve(start, smallUnits); err != nil {
+			return err
+		}
+	}
+	if end > da.cfg.SmallBlockLimit {
+		treeStart := max(start, da.cfg.SmallBlockLimit)
+		if err := da.tree.Reserve(treeStart-da.cfg.SmallBlockLimit, end-treeStart); err != nil {
+			if smallUnits > 0 {
+				da.bitmaps.Free(start, smallUnits)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Resize 调整一段已分配空间的大小。缩小时将尾部归还给空闲结构；
+// 扩大时优先原地占用紧随其后的空闲单元，否则分配一段新空间并返回 moved=true。
+// 迁移时旧空间保持分配状态，调用方拷贝完数据后需自行 Free 旧空间
+func (da *diskAllocatorImpl) Resize(address uint64, oldSize uint64, newSize uint64) (uint64, bool, error) {
+	return da.resize("", address, oldSize, newSize)
+}
+
+// resize 以 tenant 的名义调整分配的大小：扩大时按增加的单元检查配额和预留，
+// 迁移到新地址时新空间按整段计入 tenant 的用量，旧空间在调用方释放前仍计入其所属租户
+func (da *diskAllocatorImpl) resize(tenant string, address uint64, oldSize uint64, newSize uint64) (uint64, bool, error) {
+	start := address / da.cfg.UnitSize
+	oldUnits := (oldSize + da.cfg.UnitSize - 1) / da.cfg.UnitSize
+	newUnits := (newSize + da.cfg.UnitSize - 1) / da.cfg.UnitSize
+
+	entry, ok := da.allocations.lookup(start)
+	if !ok {
+		return 0, false, ErrNotAllocated
+	}
+	if entry.Size != oldUnits {
+		return 0, false, ErrSizeMismatch
+	}
+
+	switch {
+	case newUnits == oldUnits:
+		return address, false, nil
+	case newUnits == 0:
+		return 0, false, da.Free(address, oldSize)
+	case newUnits < oldUnits:
+		if err := da.resizeUnits(start, oldUnits, newUnits); err != nil {
+			return 0, false, err
+		}
+		da.incrementOperationCount()
+		return address, false, nil
+	}
+
+	adm, err := da.admit(tenant, newUnits-oldUnits)
+	if err != nil {
+		return 0, false, err
+	}
+	if err := da.reserveUnits(start+oldUnits, newUnits-oldUnits); err == nil {
+		defer adm.refund()
+		if err := da.resizeUnits(start, oldUnits, newUnits); err != nil {
+			da.freeUnits(start+oldUnits, newUnits-oldUnits)
+			return 0, false, err
+		}
+		da.incrementOperationCount()
+		return address, false, nil
+	}
+	adm.refund()
+
+	newAddress, err := da.allocateWithPolicy(tenant, newSize, PolicyDefault)
+	if err != nil {
+		return 0, false, err
+	}
+	return newAddress, true, nil
+}
+
+// resizeUnits 原地调整分配表中的条目并写 WAL，分配的 TTL、租约和租户随之保留。
+// 扩大时调用方需已占用新增的尾部单元，缩小时由本函数归还多出的尾部
+func (da *diskAllocatorImpl) resizeUnits(start, oldUnits, newUnits uint64) error {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+
+	if err := da.allocations.resize(start, oldUnits, newUnits); err != nil {
+		return err
+	}
+	if err := da.wal.append(walRecord{Op: walOpResize, Start: start, Units: newUnits}); err != nil {
+		da.allocations.resize(start, newUnits, oldUnits)
+		return err
+	}
+	da.leases.resize(start, newUnits)
+	da.tenants.resize(start, oldUnits*da.cfg.UnitSize, newUnits*da.cfg.UnitSize)
+	if newUnits < oldUnits {
+		da.freeUnits(start+newUnits, oldUnits-newUnits)
+	}
+	return nil
 }
 
 func (da *diskAllocatorImpl) Free(address uint64, size uint64) error {
 	start := address / da.cfg.UnitSize
 	units := (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize // Round up to nearest unit
+	return da.free(start, units, da.ttls.claim(start))
+}
+
+// free 释放起点为 start 的 units 个单元，claim 的含义同 allocationTable.remove
+func (da *diskAllocatorImpl) free(start, units uint64, claim func() bool) error {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+
+	if err := da.allocations.remove(start, units, claim); err != nil {
+		return err
+	}
+	da.leases.forget(start)
+	da.tenants.forget(start, units*da.cfg.UnitSize)
+	// 先写 WAL 再归还空间，保证复用这段空间的分配记录排在释放记录之后。
+	// WAL 写入失败时这段空间暂不归还，重启后按日志恢复为已分配
+	if err := da.wal.append(walRecord{Op: walOpFree, Start: start, Units: units}); err != nil {
+		return err
+	}
+	da.freeUnits(start, units)
+	da.incrementOperationCount()
+	return nil
+}
 
+// freeUnits 将单元范围 [start, start+units) 归还给位图区和 B 树区
+func (da *diskAllocatorImpl) freeUnits(start, units uint64) {
 	if start < da.cfg.SmallBlockLimit {
 		blocks := units
 		if start+blocks > da.cfg.SmallBlockLimit {
@@ -136,19 +632,81 @@ func (da *diskAllocatorImpl) Free(address uint64, size uint64) error {
 	if start >= da.cfg.SmallBlockLimit && units > 0 {
 		da.tree.Free(start-da.cfg.SmallBlockLimit, units)
 	}
-	da.incrementOperationCount()
-	return nil
 }
 
+// FreeExtents 释放 AllocateExtents 返回的全部片段
+func (da *diskAllocatorImpl) FreeExtents(extents []Extent) error {
+	var errs []error
+	for _, e := range extents {
+		if err := da.Free(e.Address, e.Size); err != nil {
+			errs = append(errs, fmt.Errorf("free extent %d+%d: %w", e.Address, e.Size, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BatchFree 批量释放，返回每一项的错误。校验通过的项按区域分组，每个位图分片和 B 树只加锁一次
+func (da *diskAllocatorImpl) BatchFree(extents []Extent) []error {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+
+	errs := make([]error, len(extents))
+	var removed []int
+	var records []walRecord
+	for i, e := range extents {
+		start := e.Address / da.cfg.UnitSize
+		units := (e.Size + da.cfg.UnitSize - 1) / da.cfg.UnitSize // Round up to nearest unit
+		if errs[i] = da.allocations.remove(start, units, da.ttls.claim(start)); errs[i] != nil {
+			continue
+		}
+		da.leases.forget(start)
+		da.tenants.forget(start, units*da.cfg.UnitSize)
+		removed = append(removed, i)
+		records = append(records, walRecord{Op: walOpFree, Start: start, Units: units})
+	}
+	if err := da.wal.append(records...); err != nil {
+		for _, i := range removed {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	var small, large []BTreeBlock
+	for _, record := range records {
+		start, units := record.Start, record.Units
+		if start < da.cfg.SmallBlockLimit {
+			blocks := min(units, da.cfg.SmallBlockLimit-start)
+			small = append(small, BTreeBlock{Start: start, Size: blocks})
+			start += blocks
+			units -= blocks
+		}
+		if units > 0 {
+			large = append(large, BTreeBlock{Start: start - da.cfg.SmallBlockLimit, Size: units})
+		}
+		da.incrementOperationCount()
+	}
+
+	da.bitmaps.FreeBatch(small)
+	da.tree.FreeBatch(large)
+	return errs
+}
+
+// GetDiskUtilization 返回已分配空间占总大小的比例，收缩中被隔离的空闲块不计为已分配
 func (da *diskAllocatorImpl) GetDiskUtilization() float64 {
-	totalSpace := da.cfg.TotalSize
-	availableSpace := (da.bitmaps.GetAvailableSpace() + da.tree.GetAvailableSpace()) * da.cfg.UnitSize
+	totalSpace := da.totalSize()
+	availableSpace := (da.bitmaps.GetAvailableSpace() + da.tree.idleSpace()) * da.cfg.UnitSize
 	usedSpace := totalSpace - availableSpace
 	return float64(usedSpace) / float64(totalSpace)
 }
 
+// GetAllocationCount 返回存活分配的个数，包括会话中尚未提交的分配
+func (da *diskAllocatorImpl) GetAllocationCount() int {
+	return da.allocations.len()
+}
+
 func (da *diskAllocatorImpl) Close() error {
 	close(da.closeChan)
 	da.closeWg.Wait()
-	return da.SaveState() // Final backup on close
+	err := da.SaveState() // Final backup on close
+	return errors.Join(err, da.wal.close())
 }
@@ -92,4 +92,213 @@ func TestDiskAllocator(t *testing.T) {
 			da.Free(addr, size)
 		}
 	})
+
+	t.Run("Aligned Allocation", func(t *testing.T) {
+		cases := []struct {
+			size      uint64
+			alignment uint64
+		}{
+			{4096, 64 * 1024},
+			{100 * 1024, 64 * 1024},
+			{2 * 1024 * 1024, 1024 * 1024},
+			{8 * 1024 * 1024, 1024 * 1024},
+		}
+		for _, c := range cases {
+			addr, err := da.AllocateAligned(c.size, c.alignment)
+			if err != nil {
+				t.Fatalf("Failed to allocate %d bytes aligned to %d: %v", c.size, c.alignment, err)
+			}
+			if addr%c.alignment != 0 {
+				t.Errorf("Address %d not aligned to %d", addr, c.alignment)
+			}
+			da.Free(addr, c.size)
+		}
+
+		if _, err := da.AllocateAligned(4096, 1000); err != ErrInvalidAlignment {
+			t.Errorf("Expected ErrInvalidAlignment, got %v", err)
+		}
+	})
+
+	t.Run("Reserve Across Regions", func(t *testing.T) {
+		boundary := cfg.SmallBlockLimit * cfg.UnitSize
+		address, size := boundary-8*cfg.UnitSize, 16*cfg.UnitSize
+
+		before := da.GetDiskUtilization()
+		if err := da.Reserve(address, size); err != nil {
+			t.Fatalf("Failed to reserve range: %v", err)
+		}
+		if err := da.Reserve(address+8*cfg.UnitSize, cfg.UnitSize); err != ErrRangeInUse {
+			t.Errorf("Expected ErrRangeInUse, got %v", err)
+		}
+		da.Free(address, size)
+
+		// 位图部分空闲而 B 树部分已占用时，位图中的占用应被回滚
+		if err := da.Reserve(boundary+4*cfg.UnitSize, 4*cfg.UnitSize); err != nil {
+			t.Fatalf("Failed to reserve tree range: %v", err)
+		}
+		if err := da.Reserve(address, size); err != ErrRangeInUse {
+			t.Errorf("Expected ErrRangeInUse, got %v", err)
+		}
+		if err := da.Reserve(address, 8*cfg.UnitSize); err != nil {
+			t.Errorf("Failed to reserve rolled back range: %v", err)
+		}
+		da.Free(address, 8*cfg.UnitSize)
+		da.Free(boundary+4*cfg.UnitSize, 4*cfg.UnitSize)
+
+		if after := da.GetDiskUtilization(); after != before {
+			t.Errorf("Utilization after free = %f, want %f", after, before)
+		}
+	})
+
+	t.Run("Scatter Allocation", func(t *testing.T) {
+		cfg := &config.Config{
+			TotalSize:       64 * 1024 * 1024, // 64MB
+			UnitSize:        4096,
+			SmallBlockLimit: 4096, // 16MB
+			NumShards:       4,
+		}
+		da := NewDiskAllocator(cfg)
+
+		// 以 1MB 为间隔交替占用，制造大量碎片
+		var holes []uint64
+		for {
+			addr, err := da.Allocate(1024 * 1024)
+			if err != nil {
+				break
+			}
+			holes = append(holes, addr)
+		}
+		for i := 0; i < len(holes); i += 2 {
+			da.Free(holes[i], 1024*1024)
+		}
+
+		size := uint64(8 * 1024 * 1024)
+		if _, err := da.Allocate(size); err == nil {
+			t.Fatal("Expected contiguous allocation to fail")
+		}
+		extents, err := da.AllocateExtents(size, 0, 64*1024)
+		if err != nil {
+			t.Fatalf("Failed to allocate extents: %v", err)
+		}
+		var total uint64
+		for _, e := range extents {
+			total += e.Size
+		}
+		if total != size {
+			t.Errorf("Extents total = %d, want %d", total, size)
+		}
+
+		// 单段分配与多段分配一样返回取整到单元的大小
+		single, err := da.AllocateExtents(cfg.UnitSize+1, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to allocate single extent: %v", err)
+		}
+		if len(single) != 1 || single[0].Size != 2*cfg.UnitSize {
+			t.Errorf("Single extent = %v, want one extent of %d bytes", single, 2*cfg.UnitSize)
+		}
+		if err := da.FreeExtents(single); err != nil {
+			t.Fatalf("Failed to free single extent: %v", err)
+		}
+
+		if _, err := da.AllocateExtents(size, 2, 64*1024); err != ErrNoSpaceLeft {
+			t.Errorf("Expected ErrNoSpaceLeft with maxExtents=2, got %v", err)
+		}
+
+		used := da.GetDiskUtilization()
+		if err := da.FreeExtents(extents); err != nil {
+			t.Fatalf("Failed to free extents: %v", err)
+		}
+		if after := da.GetDiskUtilization(); after >= used {
+			t.Errorf("Utilization after FreeExtents = %f, want < %f", after, used)
+		}
+	})
+
+	t.Run("Invalid Free", func(t *testing.T) {
+		addr, err := da.Allocate(3 * cfg.UnitSize)
+		if err != nil {
+			t.Fatalf("Failed to allocate: %v", err)
+		}
+		if err := da.Free(addr, cfg.UnitSize); err != ErrSizeMismatch {
+			t.Errorf("Expected ErrSizeMismatch, got %v", err)
+		}
+		if err := da.Free(addr+cfg.UnitSize, cfg.UnitSize); err != ErrNotAllocated {
+			t.Errorf("Expected ErrNotAllocated for partial free, got %v", err)
+		}
+		if err := da.Free(addr, 3*cfg.UnitSize); err != nil {
+			t.Fatalf("Failed to free: %v", err)
+		}
+		if err := da.Free(addr, 3*cfg.UnitSize); err != ErrNotAllocated {
+			t.Errorf("Expected ErrNotAllocated for double free, got %v", err)
+		}
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		first, err := da.AllocateWithPolicy(4*1024*1024, PolicyFirstFit)
+		if err != nil {
+			t.Fatalf("Failed to allocate: %v", err)
+		}
+
+		// 紧随其后的空间空闲，可以原地扩展
+		addr, moved, err := da.Resize(first, 4*1024*1024, 8*1024*1024)
+		if err != nil || moved || addr != first {
+			t.Fatalf("Resize() grow = (%d, %v, %v), want (%d, false, nil)", addr, moved, err, first)
+		}
+
+		// 缩小后尾部空间可被重新分配
+		if _, _, err := da.Resize(first, 8*1024*1024, 2*1024*1024); err != nil {
+			t.Fatalf("Resize() shrink error = %v", err)
+		}
+		blocker, err := da.AllocateWithPolicy(1024*1024, PolicyFirstFit)
+		if err != nil || blocker != first+2*1024*1024 {
+			t.Fatalf("Allocate() after shrink = (%d, %v), want %d", blocker, err, first+2*1024*1024)
+		}
+
+		// 后续空间已被占用，只能迁移
+		addr, moved, err = da.Resize(first, 2*1024*1024, 4*1024*1024)
+		if err != nil || !moved || addr == first {
+			t.Fatalf("Resize() relocate = (%d, %v, %v), want moved", addr, moved, err)
+		}
+
+		if _, _, err := da.Resize(first, 4*1024*1024, 8*1024*1024); err != ErrSizeMismatch {
+			t.Errorf("Expected ErrSizeMismatch, got %v", err)
+		}
+
+		for _, e := range []Extent{{first, 2 * 1024 * 1024}, {blocker, 1024 * 1024}, {addr, 4 * 1024 * 1024}} {
+			if err := da.Free(e.Address, e.Size); err != nil {
+				t.Errorf("Failed to free %d: %v", e.Address, err)
+			}
+		}
+	})
+
+	t.Run("Batch Allocate And Free", func(t *testing.T) {
+		before := da.GetDiskUtilization()
+		sizes := []uint64{4096, 64 * 1024, 2 * 1024 * 1024, 1, 16 * 1024 * 1024}
+		results := da.BatchAllocate(sizes)
+
+		extents := make([]Extent, 0, len(sizes))
+		for i, r := range results {
+			if r.Err != nil {
+				t.Fatalf("BatchAllocate() item %d error = %v", i, r.Err)
+			}
+			if r.Address%cfg.UnitSize != 0 {
+				t.Errorf("BatchAllocate() item %d address %d not aligned", i, r.Address)
+			}
+			extents = append(extents, Extent{Address: r.Address, Size: sizes[i]})
+		}
+
+		// 混入一个重复项，只有该项失败
+		extents = append(extents, extents[0])
+		errs := da.BatchFree(extents)
+		for i, err := range errs[:len(sizes)] {
+			if err != nil {
+				t.Errorf("BatchFree() item %d error = %v", i, err)
+			}
+		}
+		if errs[len(sizes)] != ErrNotAllocated {
+			t.Errorf("BatchFree() duplicate item error = %v, want ErrNotAllocated", errs[len(sizes)])
+		}
+		if after := da.GetDiskUtilization(); after != before {
+			t.Errorf("Utilization after BatchFree = %f, want %f", after, before)
+		}
+	})
 }
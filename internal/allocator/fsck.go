@@ -0,0 +1,307 @@
+package allocator
+
+import (
+	"cmp"
+	"fmt"
+	"math/bits"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+// CheckKind 是一致性检查发现的问题类别
+type CheckKind string
+
+const (
+	CheckBitmapLayout      CheckKind = "bitmap-layout"      // 位图分片数或分片大小与配置不符
+	CheckEmptyBlock        CheckKind = "empty-block"        // 空闲块大小为 0
+	CheckOutOfBounds       CheckKind = "out-of-bounds"      // 空闲块或分配超出空间范围
+	CheckOverlap           CheckKind = "overlap"            // 空闲块互相重叠
+	CheckUncoalesced       CheckKind = "uncoalesced"        // 相邻空闲块没有合并
+	CheckAllocationOverlap CheckKind = "allocation-overlap" // 分配表中的记录互相重叠
+	CheckAllocationFree    CheckKind = "allocation-free"    // 分配表中的记录在位图或空闲树中被标记为空闲
+	CheckAccounting        CheckKind = "accounting"         // 已占用空间与分配表的总量不一致
+)
+
+// CheckProblem 描述一个具体问题，地址和大小均以单元为单位
+type CheckProblem struct {
+	Kind   CheckKind
+	Detail string
+}
+
+func (p CheckProblem) String() string {
+	return string(p.Kind) + ": " + p.Detail
+}
+
+// CheckReport 是状态文件一致性检查的结果
+type CheckReport struct {
+	Problems []CheckProblem
+
+	FreeBlocks      int    // 空闲树中的块数
+	TreeFreeUnits   uint64 // 空闲树覆盖的单元数（重叠部分只计一次）
+	BitmapFreeUnits uint64
+	AllocatedUnits  uint64 // 分配表中记录的单元数，旧格式文件没有分配表时为 0
+	Allocations     int
+}
+
+// OK 判断是否没有发现问题
+func (r *CheckReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *CheckReport) add(kind CheckKind, format string, args ...any) {
+	r.Problems = append(r.Problems, CheckProblem{Kind: kind, Detail: fmt.Sprintf(format, args...)})
+}
+
+// CheckState 按 cfg 读取 path 处的完整快照并检查位图、空闲树和分配表的不变量，不修改文件
+func CheckState(cfg *config.Config, path string) (*CheckReport, error) {
+	data, cfg, err := readFullState(cfg, path)
+	if err != nil {
+		return nil, err
+	}
+	return checkStateData(cfg, data), nil
+}
+
+// RepairState 检查 path 处的快照并重建空闲树后写回，返回修复后的检查结果。
+// 有分配表且分配表本身一致时，空闲树重建为树区中所有未分配的单元；否则只合并、裁剪已有的空闲块。
+// 原文件保留为 path + ".fsck-backup"
+func RepairState(cfg *config.Config, path string) (*CheckReport, error) {
+	data, cfg, err := readFullState(cfg, path)
+	if err != nil {
+		return nil, err
+	}
+
+	before := checkStateData(cfg, data)
+	treeUnits := cfg.TotalSize/cfg.UnitSize - cfg.SmallBlockLimit
+	if data.TracksAllocations && !before.has(CheckAllocationOverlap) {
+		data.TreeData = freeTreeFromAllocations(data.Allocations, cfg.SmallBlockLimit, treeUnits)
+	} else {
+		data.TreeData = normalizeBlocks(data.TreeData, treeUnits)
+	}
+
+	if err := linkOrCopy(path, path+".fsck-backup"); err != nil {
+		return nil, fmt.Errorf("failed to back up state file: %w", err)
+	}
+	if err := writeStateFile(path, fingerprintOf(cfg), data); err != nil {
+		return nil, err
+	}
+	return checkStateData(cfg, data), nil
+}
+
+func (r *CheckReport) has(kind CheckKind) bool {
+	for _, p := range r.Problems {
+		if p.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// readFullState 读取完整快照并校验配置指纹，返回按文件中的布局调整过的配置（在线调整大小后 TotalSize 为调整后的大小）
+func readFullState(cfg *config.Config, path string) (*persistentData, *config.Config, error) {
+	data, fp, err := readStateFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fp == nil {
+		return data, cfg, nil
+	}
+	if !geometryMatches(cfg, *fp, data.ResizedFrom) {
+		return nil, nil, fmt.Errorf("%w: file has %v, config has %v", ErrConfigMismatch, *fp, fingerprintOf(cfg))
+	}
+	effective := *cfg
+	effective.TotalSize = fp.TotalSize
+	return data, &effective, nil
+}
+
+// readStateFile 读取完整快照，旧 gob 格式的文件没有配置指纹，返回的 fp 为 nil
+func readStateFile(path string) (*persistentData, *stateFingerprint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	data, fp, legacy, err := decodeState(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data.DeltaIndex != 0 {
+		return nil, nil, fmt.Errorf("%w: %s is a delta checkpoint", ErrCorruptState, path)
+	}
+	if legacy {
+		return data, nil, nil
+	}
+	return data, &fp, nil
+}
+
+// checkStateData 检查快照内容的不变量，树区地址从 0 开始，分配表地址为全局单元号
+func checkStateData(cfg *config.Config, data *persistentData) *CheckReport {
+	report := &CheckReport{FreeBlocks: len(data.TreeData), Allocations: len(data.Allocations)}
+	limit := cfg.SmallBlockLimit
+	treeUnits := cfg.TotalSize/cfg.UnitSize - limit
+
+	// 位图布局与配置一致时才检查位图内容
+	bitmapOK := uint64(len(data.Bitmaps)) == cfg.NumShards
+	if !bitmapOK {
+		report.add(CheckBitmapLayout, "%d shards, want %d", len(data.Bitmaps), cfg.NumShards)
+	}
+	shardWords := limit / 64 / max(cfg.NumShards, 1)
+	for i, words := range data.Bitmaps {
+		if uint64(len(words)) != shardWords {
+			report.add(CheckBitmapLayout, "shard %d has %d words, want %d", i, len(words), shardWords)
+			bitmapOK = false
+		}
+		for _, word := range words {
+			report.BitmapFreeUnits += uint64(64 - bits.OnesCount64(word))
+		}
+	}
+
+	blocks := slices.Clone(data.TreeData)
+	slices.SortFunc(blocks, func(a, b BTreeBlock) int {
+		if a.Start != b.Start {
+			return cmp.Compare(a.Start, b.Start)
+		}
+		return cmp.Compare(a.Size, b.Size)
+	})
+	var prevEnd uint64
+	for i, block := range blocks {
+		end := block.Start + block.Size
+		switch {
+		case block.Size == 0:
+			report.add(CheckEmptyBlock, "free block at %d has size 0", block.Start)
+		case end < block.Start || end > treeUnits:
+			report.add(CheckOutOfBounds, "free block [%d, %d) exceeds tree size %d", block.Start, end, treeUnits)
+		}
+		if i > 0 {
+			if block.Start < prevEnd {
+				report.add(CheckOverlap, "free block [%d, %d) overlaps previous block ending at %d", block.Start, end, prevEnd)
+			} else if block.Start == prevEnd && block.Size > 0 {
+				report.add(CheckUncoalesced, "free block [%d, %d) is adjacent to previous block", block.Start, end)
+			}
+		}
+		prevEnd = max(prevEnd, end)
+	}
+	free := normalizeBlocks(blocks, treeUnits)
+	for _, block := range free {
+		report.TreeFreeUnits += block.Size
+	}
+
+	if !data.TracksAllocations {
+		return report
+	}
+
+	entries := slices.Clone(data.Allocations)
+	slices.SortFunc(entries, func(a, b AllocationEntry) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	var bitmapAllocated, treeAllocated, prevAllocEnd uint64
+	for i, entry := range entries {
+		end := entry.Start + entry.Size
+		report.AllocatedUnits += entry.Size
+		if entry.Size == 0 || end < entry.Start || end > limit+treeUnits {
+			report.add(CheckOutOfBounds, "allocation [%d, %d) exceeds space of %d units", entry.Start, end, limit+treeUnits)
+			continue
+		}
+		if i > 0 && entry.Start < prevAllocEnd {
+			report.add(CheckAllocationOverlap, "allocation [%d, %d) overlaps previous allocation ending at %d", entry.Start, end, prevAllocEnd)
+		}
+		prevAllocEnd = max(prevAllocEnd, end)
+
+		if entry.Start < limit {
+			n := min(end, limit) - entry.Start
+			bitmapAllocated += n
+			if bitmapOK && shardWords > 0 {
+				if unit, ok := firstClearBit(data.Bitmaps, shardWords*64, entry.Start, n); ok {
+					report.add(CheckAllocationFree, "allocation [%d, %d) has unit %d free in bitmap", entry.Start, end, unit)
+				}
+			}
+		}
+		if end > limit {
+			from := max(entry.Start, limit) - limit
+			treeAllocated += end - limit - from
+			if block, ok := firstOverlap(free, from, end-limit); ok {
+				report.add(CheckAllocationFree, "allocation [%d, %d) overlaps free block [%d, %d) in tree",
+					entry.Start, end, limit+block.Start, limit+block.Start+block.Size)
+			}
+		}
+	}
+
+	if used := cfg.NumShards*shardWords*64 - report.BitmapFreeUnits; bitmapOK && used != bitmapAllocated {
+		report.add(CheckAccounting, "bitmap has %d used units, allocations cover %d", used, bitmapAllocated)
+	}
+	if treeUnits-report.TreeFreeUnits != treeAllocated {
+		report.add(CheckAccounting, "tree has %d used units, allocations cover %d", treeUnits-report.TreeFreeUnits, treeAllocated)
+	}
+	return report
+}
+
+// normalizeBlocks 将空闲块裁剪到 [0, limit) 内，合并重叠和相邻的块并按起点排序
+func normalizeBlocks(blocks []BTreeBlock, limit uint64) []BTreeBlock {
+	clipped := make([]BTreeBlock, 0, len(blocks))
+	for _, block := range blocks {
+		end := block.Start + block.Size
+		if end < block.Start {
+			end = limit
+		}
+		end = min(end, limit)
+		if block.Start < end {
+			clipped = append(clipped, BTreeBlock{Start: block.Start, Size: end - block.Start})
+		}
+	}
+	if len(clipped) == 0 {
+		return clipped
+	}
+	return mergeRanges(clipped)
+}
+
+// freeTreeFromAllocations 返回树区 [0, treeUnits) 中不被任何分配覆盖的区间，分配表地址为全局单元号
+func freeTreeFromAllocations(entries []AllocationEntry, limit, treeUnits uint64) []BTreeBlock {
+	var used []BTreeBlock
+	for _, entry := range entries {
+		end := min(entry.Start+entry.Size, limit+treeUnits)
+		if end > limit && end > entry.Start {
+			from := max(entry.Start, limit)
+			used = append(used, BTreeBlock{Start: from - limit, Size: end - from})
+		}
+	}
+	if len(used) > 0 {
+		used = mergeRanges(used)
+	}
+
+	free := make([]BTreeBlock, 0, len(used)+1)
+	next := uint64(0)
+	for _, r := range used {
+		if r.Start > next {
+			free = append(free, BTreeBlock{Start: next, Size: r.Start - next})
+		}
+		next = r.Start + r.Size
+	}
+	if next < treeUnits {
+		free = append(free, BTreeBlock{Start: next, Size: treeUnits - next})
+	}
+	return free
+}
+
+// firstClearBit 返回 [start, start+size) 中第一个在位图里为 0 的单元
+func firstClearBit(shards [][]uint64, shardBits, start, size uint64) (uint64, bool) {
+	for unit := start; unit < start+size; unit++ {
+		local := unit % shardBits
+		if shards[unit/shardBits][local/64]&(1<<(local%64)) == 0 {
+			return unit, true
+		}
+	}
+	return 0, false
+}
+
+// firstOverlap 在按起点排序且互不重叠的 blocks 中查找与 [start, end) 相交的第一个块
+func firstOverlap(blocks []BTreeBlock, start, end uint64) (BTreeBlock, bool) {
+	i := sort.Search(len(blocks), func(i int) bool {
+		return blocks[i].Start+blocks[i].Size > start
+	})
+	if i < len(blocks) && blocks[i].Start < end {
+		return blocks[i], true
+	}
+	return BTreeBlock{}, false
+}
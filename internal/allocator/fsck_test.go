@@ -0,0 +1,118 @@
+package allocator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newFsckTestState(t *testing.T) (*config.Config, *diskAllocatorImpl) {
+	cfg := &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: filepath.Join(t.TempDir(), "state"),
+		BackupIntervalSec:    3600,
+	}
+	da := loadSnapshotTestAllocator(t, cfg)
+	for _, size := range []uint64{4096, 64 * 4096, 1024 * 1024, 3 * 1024 * 1024, 1024 * 1024} {
+		if _, err := da.Allocate(size); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	return cfg, da
+}
+
+func kindsOf(report *CheckReport) map[CheckKind]bool {
+	kinds := make(map[CheckKind]bool)
+	for _, p := range report.Problems {
+		kinds[p.Kind] = true
+	}
+	return kinds
+}
+
+func TestCheckStateClean(t *testing.T) {
+	cfg, da := newFsckTestState(t)
+	defer da.Close()
+
+	report, err := CheckState(cfg, cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("CheckState() error = %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("CheckState() problems = %v, want none", report.Problems)
+	}
+	if report.Allocations != 5 || report.AllocatedUnits != 1+64+256+768+256 {
+		t.Errorf("report = %+v, want 5 allocations of %d units", report, 1+64+256+768+256)
+	}
+}
+
+func TestCheckStateDetectsAndRepairs(t *testing.T) {
+	cfg, da := newFsckTestState(t)
+	utilization := da.GetDiskUtilization()
+	crash(da)
+
+	data, _, err := readFullState(cfg, cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("readFullState() error = %v", err)
+	}
+	treeUnits := cfg.TotalSize/cfg.UnitSize - cfg.SmallBlockLimit
+	last := data.TreeData[len(data.TreeData)-1]
+	// 将末尾空闲块拆成相邻的两块，再加入一个越界块和一个与已分配区间重叠的块
+	data.TreeData = append(data.TreeData[:len(data.TreeData)-1],
+		BTreeBlock{Start: last.Start, Size: 10},
+		BTreeBlock{Start: last.Start + 10, Size: last.Size - 10},
+		BTreeBlock{Start: treeUnits - 5, Size: 10},
+		BTreeBlock{Start: 0, Size: 100},
+	)
+	if err := writeStateFile(cfg.StatePersistencePath, fingerprintOf(cfg), data); err != nil {
+		t.Fatalf("writeStateFile() error = %v", err)
+	}
+
+	report, err := CheckState(cfg, cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("CheckState() error = %v", err)
+	}
+	kinds := kindsOf(report)
+	for _, kind := range []CheckKind{CheckUncoalesced, CheckOutOfBounds, CheckOverlap, CheckAllocationFree, CheckAccounting} {
+		if !kinds[kind] {
+			t.Errorf("CheckState() did not report %s, problems = %v", kind, report.Problems)
+		}
+	}
+
+	repaired, err := RepairState(cfg, cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("RepairState() error = %v", err)
+	}
+	if !repaired.OK() {
+		t.Errorf("RepairState() problems = %v, want none", repaired.Problems)
+	}
+	if _, err := os.Stat(cfg.StatePersistencePath + ".fsck-backup"); err != nil {
+		t.Errorf("backup of original state file: %v", err)
+	}
+
+	recovered := loadSnapshotTestAllocator(t, cfg)
+	defer recovered.Close()
+	if got := recovered.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after repair = %v, want %v", got, utilization)
+	}
+	checkTreeConsistent(t, recovered.tree)
+}
+
+func TestCheckStateConfigMismatch(t *testing.T) {
+	cfg, da := newFsckTestState(t)
+	defer da.Close()
+
+	other := *cfg
+	other.NumShards = 8
+	if _, err := CheckState(&other, cfg.StatePersistencePath); !errors.Is(err, ErrConfigMismatch) {
+		t.Errorf("CheckState() with different shard count error = %v, want %v", err, ErrConfigMismatch)
+	}
+}
@@ -0,0 +1,92 @@
+package allocator
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// totalSize 返回当前管理的字节数
+func (da *diskAllocatorImpl) totalSize() uint64 {
+	return atomic.LoadUint64(&da.total)
+}
+
+// GetTotalSize 返回当前管理的字节数，反映在线扩容和收缩
+func (da *diskAllocatorImpl) GetTotalSize() uint64 {
+	return da.totalSize()
+}
+
+// resizedFrom 返回在线调整大小前配置中的 TotalSize，未调整时为 0
+func (da *diskAllocatorImpl) resizedFrom() uint64 {
+	if da.totalSize() == da.cfg.TotalSize {
+		return 0
+	}
+	return da.cfg.TotalSize
+}
+
+// fingerprint 返回当前布局，TotalSize 为在线调整后的大小
+func (da *diskAllocatorImpl) fingerprint() stateFingerprint {
+	fp := fingerprintOf(da.cfg)
+	fp.TotalSize = da.totalSize()
+	return fp
+}
+
+// Grow 将管理的空间扩大到 newTotalSize 字节，新增部分加入 B 树区末尾并与末尾的空闲块合并。
+// 扩容先写入 WAL，再写一次完整快照记录新的布局；重启时只要配置中的 TotalSize 仍为扩容前的值，就沿用扩容后的大小
+func (da *diskAllocatorImpl) Grow(newTotalSize uint64) error {
+	unit := da.cfg.UnitSize
+	if newTotalSize%unit != 0 {
+		return fmt.Errorf("%w: %d is not a multiple of unit size %d", ErrInvalidTotalSize, newTotalSize, unit)
+	}
+
+	// saveMu 保证进行中的保存不会把扩容前的空闲树写在扩容后的文件头之下，
+	// walMu 写锁排除其他修改，保证扩容记录与内存中的扩容对所有 WAL 记录有确定的先后
+	da.saveMu.Lock()
+	da.walMu.Lock()
+	old := da.totalSize()
+	if newTotalSize <= old {
+		da.walMu.Unlock()
+		da.saveMu.Unlock()
+		return fmt.Errorf("%w: %d is not larger than current size %d", ErrInvalidTotalSize, newTotalSize, old)
+	}
+	record := walRecord{Op: walOpGrow, Start: old / unit, Units: (newTotalSize - old) / unit}
+	if err := da.wal.append(record); err != nil {
+		da.walMu.Unlock()
+		da.saveMu.Unlock()
+		return err
+	}
+	da.growUnits(newTotalSize / unit)
+	da.walMu.Unlock()
+	da.saveMu.Unlock()
+	log.Printf("grew managed space from %d to %d bytes", old, newTotalSize)
+
+	if da.cfg.StatePersistencePath == "" {
+		return nil
+	}
+	return da.SaveState()
+}
+
+// growUnits 将总单元数扩大到 totalUnits，不小于当前大小时不做修改，WAL 重放时可以重复调用。
+// 调用方需持有 saveMu 或处于加载阶段
+func (da *diskAllocatorImpl) growUnits(totalUnits uint64) {
+	if totalUnits <= da.totalSize()/da.cfg.UnitSize {
+		return
+	}
+	da.tree.grow(totalUnits - da.cfg.SmallBlockLimit)
+	atomic.StoreUint64(&da.total, totalUnits*da.cfg.UnitSize)
+	// 之前的增量检查点基于旧的布局，下一次保存必须是完整快照
+	da.forceFull = true
+}
+
+// grow 将树区扩大到 totalSpace 个单元，新增部分作为空闲块与末尾的空闲块合并，未完成的收缩随之取消
+func (dm *BTreeManager) grow(totalSpace uint64) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if totalSpace <= dm.totalSpace {
+		return
+	}
+	dm.setFenceLocked(0)
+	start := dm.totalSpace
+	dm.totalSpace = totalSpace
+	dm.freeLocked(start, totalSpace-start)
+}
@@ -0,0 +1,156 @@
+package allocator
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newGrowTestConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		UnitSize:             4096,
+		TotalSize:            16 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: filepath.Join(t.TempDir(), "state"),
+		BackupIntervalSec:    3600,
+		WALSyncPolicy:        "per-op",
+		DeltaCheckpointLimit: 3,
+	}
+}
+
+// checkGrown 检查扩容后末尾的空闲块延伸到新的总大小，并且可以分配跨越原边界的空间
+func checkGrown(t *testing.T, da *diskAllocatorImpl, oldTotal, newTotal uint64) {
+	t.Helper()
+	if got := da.GetTotalSize(); got != newTotal {
+		t.Fatalf("GetTotalSize() = %d, want %d", got, newTotal)
+	}
+	checkTreeConsistent(t, da.tree)
+	if report := da.Verify(false); !report.OK() {
+		t.Errorf("Verify() after grow problems = %v", report.Problems)
+	}
+	blocks := blocksOf(da.tree)
+	last := blocks[len(blocks)-1]
+	treeEnd := newTotal/da.cfg.UnitSize - da.cfg.SmallBlockLimit
+	if last.Start+last.Size != treeEnd || last.Start*da.cfg.UnitSize >= oldTotal {
+		t.Errorf("last free block = %+v, want one ending at %d and merged across the old end", last, treeEnd)
+	}
+
+	size := newTotal - oldTotal + 4096
+	address, err := da.Allocate(size)
+	if err != nil {
+		t.Fatalf("Allocate(%d) after grow error = %v", size, err)
+	}
+	if address+size > newTotal {
+		t.Errorf("Allocate(%d) = %d, exceeds total size %d", size, address, newTotal)
+	}
+	if err := da.Free(address, size); err != nil {
+		t.Errorf("Free() error = %v", err)
+	}
+}
+
+func TestGrow(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+	if _, err := da.Allocate(1024 * 1024); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	utilization := da.GetDiskUtilization()
+
+	newTotal := 2 * cfg.TotalSize
+	if err := da.Grow(newTotal); err != nil {
+		t.Fatalf("Grow() error = %v", err)
+	}
+	checkGrown(t, da, cfg.TotalSize, newTotal)
+	if got := da.GetDiskUtilization(); got != utilization/2 {
+		t.Errorf("utilization after grow = %v, want %v", got, utilization/2)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 配置不变时重启沿用扩容后的大小，不触发迁移
+	da = loadSnapshotTestAllocator(t, cfg)
+	if got := da.GetTotalSize(); got != newTotal {
+		t.Errorf("GetTotalSize() after restart = %d, want %d", got, newTotal)
+	}
+	if fp, resizedFrom, ok := readFingerprint(cfg.StatePersistencePath); !ok || fp.TotalSize != newTotal || resizedFrom != cfg.TotalSize {
+		t.Errorf("state file has total size %d grown from %d, want %d grown from %d", fp.TotalSize, resizedFrom, newTotal, cfg.TotalSize)
+	}
+	checkTreeConsistent(t, da.tree)
+
+	report, err := CheckState(cfg, cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("CheckState() error = %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("CheckState() problems = %v", report.Problems)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 迁移到新的分片数时同样保留扩容后的大小
+	reshard := *cfg
+	reshard.NumShards = 8
+	da = loadSnapshotTestAllocator(t, &reshard)
+	defer da.Close()
+	if got := da.GetTotalSize(); got != newTotal {
+		t.Errorf("GetTotalSize() after migration = %d, want %d", got, newTotal)
+	}
+	checkTreeConsistent(t, da.tree)
+}
+
+func TestGrowReplaysFromWAL(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	// 只写入 WAL 记录，模拟保存快照之前崩溃
+	newTotal := cfg.TotalSize + 8*1024*1024
+	unit := cfg.UnitSize
+	da.walMu.Lock()
+	err := da.wal.append(walRecord{Op: walOpGrow, Start: cfg.TotalSize / unit, Units: (newTotal - cfg.TotalSize) / unit})
+	if err == nil {
+		da.growUnits(newTotal / unit)
+	}
+	da.walMu.Unlock()
+	if err != nil {
+		t.Fatalf("append() error = %v", err)
+	}
+	address, err := da.Allocate(newTotal - cfg.TotalSize)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	crash(da)
+
+	da = loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	if got := da.GetTotalSize(); got != newTotal {
+		t.Fatalf("GetTotalSize() after replay = %d, want %d", got, newTotal)
+	}
+	if err := da.Free(address, newTotal-cfg.TotalSize); err != nil {
+		t.Fatalf("Free() of allocation in grown space error = %v", err)
+	}
+	checkGrown(t, da, cfg.TotalSize, newTotal)
+}
+
+func TestGrowRejectsInvalidSize(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	cfg.StatePersistencePath = ""
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	for _, size := range []uint64{cfg.TotalSize, cfg.TotalSize / 2, cfg.TotalSize + 100} {
+		if err := da.Grow(size); !errors.Is(err, ErrInvalidTotalSize) {
+			t.Errorf("Grow(%d) error = %v, want %v", size, err, ErrInvalidTotalSize)
+		}
+	}
+	if got := da.GetTotalSize(); got != cfg.TotalSize {
+		t.Errorf("GetTotalSize() after rejected grows = %d, want %d", got, cfg.TotalSize)
+	}
+}
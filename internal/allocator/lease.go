@@ -0,0 +1,307 @@
+package allocator
+
+import (
+	"sync"
+	"time"
+)
+
+const leaseCheckInterval = time.Second
+
+// SessionState 是一个会话及其尚未提交的分配，Extents 为起始单元到单元数的映射
+type SessionState struct {
+	ID        uint64
+	TTL       time.Duration
+	ExpiresAt time.Time
+	Extents   map[uint64]uint64
+}
+
+// leaseTable 记录会话持有的未提交分配，会话过期或关闭时这些分配会被释放
+type leaseTable struct {
+	mu       sync.Mutex
+	nextID   uint64
+	sessions map[uint64]*SessionState
+	owners   map[uint64]uint64 // 起始单元 -> 会话 ID
+}
+
+func newLeaseTable() *leaseTable {
+	return &leaseTable{
+		sessions: make(map[uint64]*SessionState),
+		owners:   make(map[uint64]uint64),
+	}
+}
+
+func (t *leaseTable) open(ttl time.Duration) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	t.sessions[t.nextID] = &SessionState{
+		ID:        t.nextID,
+		TTL:       ttl,
+		ExpiresAt: time.Now().Add(ttl),
+		Extents:   make(map[uint64]uint64),
+	}
+	return t.nextID
+}
+
+// reopen 在重放 WAL 时恢复打开的会话，过期时间从现在起算，会话已存在时不做修改
+func (t *leaseTable) reopen(id uint64, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID = max(t.nextID, id)
+	if _, ok := t.sessions[id]; ok {
+		return
+	}
+	t.sessions[id] = &SessionState{
+		ID:        id,
+		TTL:       ttl,
+		ExpiresAt: time.Now().Add(ttl),
+		Extents:   make(map[uint64]uint64),
+	}
+}
+
+func (t *leaseTable) keepAlive(id uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	session.ExpiresAt = time.Now().Add(session.TTL)
+	return nil
+}
+
+func (t *leaseTable) add(id, start, units uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	session.Extents[start] = units
+	t.owners[start] = id
+	return nil
+}
+
+// commit 将会话中的一段分配转为永久分配
+func (t *leaseTable) commit(id, start, units uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	leased, ok := session.Extents[start]
+	if !ok {
+		return ErrNotLeased
+	}
+	if leased != units {
+		return ErrSizeMismatch
+	}
+	delete(session.Extents, start)
+	delete(t.owners, start)
+	return nil
+}
+
+// forget 在分配被直接释放时移除对应的租约
+func (t *leaseTable) forget(start uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id, ok := t.owners[start]; ok {
+		delete(t.sessions[id].Extents, start)
+		delete(t.owners, start)
+	}
+}
+
+// resize 在原地调整分配大小后更新租约记录的单元数，分配不属于任何会话时不做修改
+func (t *leaseTable) resize(start, units uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id, ok := t.owners[start]; ok {
+		t.sessions[id].Extents[start] = units
+	}
+}
+
+// close 删除会话并返回其尚未提交的分配
+func (t *leaseTable) close(id uint64) (map[uint64]uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	delete(t.sessions, id)
+	for start := range session.Extents {
+		delete(t.owners, start)
+	}
+	return session.Extents, nil
+}
+
+func (t *leaseTable) expired(now time.Time) []uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var ids []uint64
+	for id, session := range t.sessions {
+		if now.After(session.ExpiresAt) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (t *leaseTable) snapshot() (uint64, []SessionState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sessions := make([]SessionState, 0, len(t.sessions))
+	for _, session := range t.sessions {
+		extents := make(map[uint64]uint64, len(session.Extents))
+		for start, units := range session.Extents {
+			extents[start] = units
+		}
+		s := *session
+		s.Extents = extents
+		sessions = append(sessions, s)
+	}
+	return t.nextID, sessions
+}
+
+// load 用检查点中的会话替换当前内容
+func (t *leaseTable) load(nextID uint64, sessions []SessionState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID = nextID
+	clear(t.sessions)
+	clear(t.owners)
+	for i := range sessions {
+		session := sessions[i]
+		if session.Extents == nil {
+			session.Extents = make(map[uint64]uint64)
+		}
+		t.sessions[session.ID] = &session
+		for start := range session.Extents {
+			t.owners[start] = session.ID
+		}
+	}
+}
+
+func (da *diskAllocatorImpl) startLeaseRoutine() {
+	da.closeWg.Add(1)
+	go func() {
+		defer da.closeWg.Done()
+		ticker := time.NewTicker(leaseCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				da.expireSessions(time.Now())
+			case <-da.closeChan:
+				return
+			}
+		}
+	}()
+}
+
+func (da *diskAllocatorImpl) expireSessions(now time.Time) {
+	for _, id := range da.leases.expired(now) {
+		da.CloseSession(id)
+	}
+}
+
+// OpenSession 创建一个会话，ttl 内没有续期的会话会被关闭并释放其未提交的分配
+func (da *diskAllocatorImpl) OpenSession(ttl time.Duration) (uint64, error) {
+	if ttl <= 0 {
+		return 0, ErrInvalidTTL
+	}
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+	id := da.leases.open(ttl)
+	if err := da.wal.append(walRecord{Op: walOpSessionOpen, Start: id, Units: uint64(ttl)}); err != nil {
+		da.leases.close(id)
+		return 0, err
+	}
+	return id, nil
+}
+
+func (da *diskAllocatorImpl) KeepAliveSession(sessionID uint64) error {
+	return da.leases.keepAlive(sessionID)
+}
+
+// AllocateInSession 分配空间并将其登记在会话下，直到 Commit 前都可能随会话一起被释放
+func (da *diskAllocatorImpl) AllocateInSession(sessionID uint64, size uint64) (uint64, error) {
+	return da.allocateInSession("", sessionID, size)
+}
+
+func (da *diskAllocatorImpl) allocateInSession(tenant string, sessionID uint64, size uint64) (uint64, error) {
+	if err := da.leases.keepAlive(sessionID); err != nil {
+		return 0, err
+	}
+	address, err := da.allocateWithPolicy(tenant, size, PolicyDefault)
+	if err != nil {
+		return 0, err
+	}
+	units := (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize
+	if err := da.lease(sessionID, address/da.cfg.UnitSize, units); err != nil {
+		da.Free(address, size)
+		return 0, err
+	}
+	return address, nil
+}
+
+// lease 将分配登记在会话下并写 WAL
+func (da *diskAllocatorImpl) lease(sessionID, start, units uint64) error {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+	if err := da.leases.add(sessionID, start, units); err != nil {
+		return err
+	}
+	if err := da.wal.append(walRecord{Op: walOpLease, Start: start, Units: sessionID}); err != nil {
+		da.leases.forget(start)
+		return err
+	}
+	return nil
+}
+
+// Commit 将会话中的一段分配转为永久分配
+func (da *diskAllocatorImpl) Commit(sessionID uint64, address uint64, size uint64) error {
+	start := address / da.cfg.UnitSize
+	units := (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+	if err := da.leases.commit(sessionID, start, units); err != nil {
+		return err
+	}
+	if err := da.wal.append(walRecord{Op: walOpCommit, Start: start, Units: sessionID}); err != nil {
+		da.leases.add(sessionID, start, units)
+		return err
+	}
+	da.incrementOperationCount()
+	return nil
+}
+
+// CloseSession 关闭会话并释放其全部未提交的分配
+func (da *diskAllocatorImpl) CloseSession(sessionID uint64) error {
+	extents, err := da.closeSession(sessionID)
+	if err != nil {
+		return err
+	}
+	for start, units := range extents {
+		da.Free(start*da.cfg.UnitSize, units*da.cfg.UnitSize)
+	}
+	da.incrementOperationCount()
+	return nil
+}
+
+// closeSession 删除会话并写 WAL，返回其尚未提交的分配。
+// 写 WAL 失败时不释放这些分配，重启后会话按快照和 WAL 恢复，过期后再释放
+func (da *diskAllocatorImpl) closeSession(sessionID uint64) (map[uint64]uint64, error) {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+	extents, err := da.leases.close(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := da.wal.append(walRecord{Op: walOpSessionClose, Start: sessionID}); err != nil {
+		return nil, err
+	}
+	return extents, nil
+}
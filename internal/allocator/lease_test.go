@@ -0,0 +1,114 @@
+package allocator
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newLeaseTestAllocator(t *testing.T, path string) *diskAllocatorImpl {
+	cfg := &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: path,
+		BackupIntervalSec:    5,
+	}
+	da, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	return da.(*diskAllocatorImpl)
+}
+
+func TestSessionCommitAndClose(t *testing.T) {
+	da := newLeaseTestAllocator(t, "")
+	defer da.Close()
+
+	id, err := da.OpenSession(time.Minute)
+	if err != nil {
+		t.Fatalf("OpenSession() error = %v", err)
+	}
+	committed, err := da.AllocateInSession(id, 1024*1024)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	leased, err := da.AllocateInSession(id, 2*1024*1024)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	if err := da.Commit(id, committed, 1024*1024); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := da.Commit(id, committed, 1024*1024); err != ErrNotLeased {
+		t.Errorf("Commit() twice error = %v, want ErrNotLeased", err)
+	}
+
+	if err := da.CloseSession(id); err != nil {
+		t.Fatalf("CloseSession() error = %v", err)
+	}
+	if err := da.Free(leased, 2*1024*1024); err != ErrNotAllocated {
+		t.Errorf("Uncommitted allocation should be freed on close, Free() error = %v", err)
+	}
+	if err := da.Free(committed, 1024*1024); err != nil {
+		t.Errorf("Committed allocation should survive close, Free() error = %v", err)
+	}
+	if _, err := da.AllocateInSession(id, 4096); err != ErrSessionNotFound {
+		t.Errorf("AllocateInSession() on closed session error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	da := newLeaseTestAllocator(t, "")
+	defer da.Close()
+
+	id, _ := da.OpenSession(time.Second)
+	addr, err := da.AllocateInSession(id, 4096)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+
+	da.expireSessions(time.Now())
+	if err := da.KeepAliveSession(id); err != nil {
+		t.Fatalf("Session expired too early: %v", err)
+	}
+
+	da.expireSessions(time.Now().Add(2 * time.Second))
+	if err := da.KeepAliveSession(id); err != ErrSessionNotFound {
+		t.Errorf("KeepAliveSession() after expiry error = %v, want ErrSessionNotFound", err)
+	}
+	if err := da.Free(addr, 4096); err != ErrNotAllocated {
+		t.Errorf("Expired lease should be freed, Free() error = %v", err)
+	}
+}
+
+func TestSessionPersistence(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test-lease-state-*.gob")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+
+	da := newLeaseTestAllocator(t, tmpfile.Name())
+	id, _ := da.OpenSession(time.Minute)
+	addr, err := da.AllocateInSession(id, 8192)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reloaded := newLeaseTestAllocator(t, tmpfile.Name())
+	defer reloaded.Close()
+	if err := reloaded.Commit(id, addr, 8192); err != nil {
+		t.Errorf("Commit() after reload error = %v", err)
+	}
+	if next, _ := reloaded.OpenSession(time.Minute); next <= id {
+		t.Errorf("OpenSession() after reload = %d, want > %d", next, id)
+	}
+}
@@ -0,0 +1,109 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+// ErrMigrationDataLoss 表示新配置的空间放不下现有的存活分配
+var ErrMigrationDataLoss = errors.New("config change would lose live allocations")
+
+// migrateState 在状态文件的 NumShards、SmallBlockLimit 或 TotalSize 与 cfg 不同时，
+// 按文件中的布局加载快照、增量检查点和 WAL，将已占用的单元投影到新布局后写入完整快照。
+// 单元号与布局无关，分配表、会话和 TTL 原样保留；已占用的单元超出新的总大小，
+// 或落在新位图覆盖不到的位图区尾部时拒绝迁移，文件保持不变。
+// 迁移前的文件保留为 path + ".migrate-backup"。
+// 状态文件不存在、损坏或为旧 gob 格式时不做处理，由 openState 按原有逻辑处理
+func migrateState(cfg *config.Config) error {
+	path := cfg.StatePersistencePath
+	if path == "" {
+		return nil
+	}
+	fp, resizedFrom, ok := readFingerprint(path)
+	if !ok || geometryMatches(cfg, fp, resizedFrom) {
+		return nil
+	}
+	if fp.UnitSize != cfg.UnitSize {
+		return fmt.Errorf("%w: file has %v, config has %v (unit size cannot be migrated)", ErrConfigMismatch, fp, fingerprintOf(cfg))
+	}
+
+	oldCfg := *cfg
+	oldCfg.TotalSize = fp.TotalSize
+	oldCfg.NumShards = fp.NumShards
+	oldCfg.SmallBlockLimit = fp.SmallBlockLimit
+	da, err := openState(&oldCfg)
+	if err != nil {
+		return fmt.Errorf("failed to load state for migration: %w", err)
+	}
+	defer da.wal.close()
+
+	// 配置中的 TotalSize 仍是在线调整大小前的值时保留调整后的大小
+	total := cfg.TotalSize
+	if resizedFrom == cfg.TotalSize {
+		total = da.totalSize()
+	}
+
+	// 旧布局中所有已占用的单元，包括分配表之外的部分，迁移后保持已占用
+	used := rebuildLegacyAllocations(da.bitmaps, da.tree, oldCfg.SmallBlockLimit)
+	totalUnits := total / cfg.UnitSize
+	for _, run := range used {
+		if run.Start+run.Size > totalUnits {
+			return fmt.Errorf("%w: units [%d, %d) are in use, new config has %d units",
+				ErrMigrationDataLoss, run.Start, run.Start+run.Size, totalUnits)
+		}
+	}
+
+	// 新位图的分片向下取整后覆盖不到 [capacity, SmallBlockLimit)，这部分单元不属于任何区域，不能有存活的分配
+	bitmaps := NewBitMap(cfg.SmallBlockLimit, cfg.NumShards)
+	if capacity := bitmaps.capacity(); capacity < cfg.SmallBlockLimit {
+		for _, run := range used {
+			if run.Start < cfg.SmallBlockLimit && run.Start+run.Size > capacity {
+				return fmt.Errorf("%w: units [%d, %d) are in use, new bitmap with %d shards covers only %d of %d small block units",
+					ErrMigrationDataLoss, run.Start, run.Start+run.Size, cfg.NumShards, capacity, cfg.SmallBlockLimit)
+			}
+		}
+	}
+
+	policy := da.tree.policy
+	da.cfg = cfg
+	da.bitmaps = bitmaps
+	da.tree = NewBTreeManager(totalUnits - cfg.SmallBlockLimit)
+	da.tree.SetPolicy(policy)
+	atomic.StoreUint64(&da.total, total)
+	for _, run := range used {
+		da.markUnits(run.Start, run.Size, true)
+	}
+
+	// 不保留历史快照时也留下迁移前的文件，便于回退到旧配置
+	backup := path + ".migrate-backup"
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := linkOrCopy(path, backup); err != nil {
+		return fmt.Errorf("failed to back up state file: %w", err)
+	}
+	if err := da.saveFull(da.wal.lastSeq()); err != nil {
+		return fmt.Errorf("failed to save migrated state: %w", err)
+	}
+	log.Printf("migrated state %s from %v to %v (%d used runs)", path, fp, da.fingerprint(), len(used))
+	return nil
+}
+
+// readFingerprint 返回状态文件记录的布局和在线调整大小前的 TotalSize，文件不存在、为空、无法解析或为旧 gob 格式时 ok 为 false
+func readFingerprint(path string) (fp stateFingerprint, resizedFrom uint64, ok bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return fp, 0, false
+	}
+	defer file.Close()
+	data, fp, legacy, err := decodeState(file)
+	if err != nil || legacy || data.DeltaIndex != 0 {
+		return fp, 0, false
+	}
+	return fp, data.ResizedFrom, true
+}
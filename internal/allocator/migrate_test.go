@@ -0,0 +1,186 @@
+package allocator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+type migrateTestExtent struct {
+	address, size uint64
+}
+
+// newMigrateTestState 写入一个完整快照，之后再做一些只记录在 WAL 中的修改，然后模拟崩溃
+func newMigrateTestState(t *testing.T) (*config.Config, []migrateTestExtent, []AllocationEntry) {
+	cfg := &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: filepath.Join(t.TempDir(), "state"),
+		BackupIntervalSec:    3600,
+		WALSyncPolicy:        "per-op",
+		DeltaCheckpointLimit: 3,
+	}
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	var live []migrateTestExtent
+	allocate := func(size uint64) uint64 {
+		address, err := da.Allocate(size)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		live = append(live, migrateTestExtent{address, size})
+		return address
+	}
+	for _, size := range []uint64{4096, 8 * 4096, 64 * 4096, 1024 * 1024, 3 * 1024 * 1024} {
+		allocate(size)
+	}
+	hole := allocate(2 * 1024 * 1024)
+	allocate(1024 * 1024)
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	if err := da.Free(hole, 2*1024*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	live = slices.DeleteFunc(live, func(e migrateTestExtent) bool { return e.address == hole })
+	allocate(16 * 4096)
+	allocate(5 * 1024 * 1024)
+	entries := da.allocations.snapshot()
+	crash(da)
+	return cfg, live, entries
+}
+
+func TestMigrateState(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(cfg *config.Config)
+	}{
+		{"reshard", func(cfg *config.Config) { cfg.NumShards = 8 }},
+		{"grow bitmap", func(cfg *config.Config) { cfg.SmallBlockLimit = 4096 }},
+		{"shrink bitmap", func(cfg *config.Config) { cfg.SmallBlockLimit = 256; cfg.NumShards = 2 }},
+		{"grow total", func(cfg *config.Config) { cfg.TotalSize = 256 * 1024 * 1024 }},
+		{"shrink total", func(cfg *config.Config) { cfg.TotalSize = 32 * 1024 * 1024 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, live, entries := newMigrateTestState(t)
+			newCfg := *cfg
+			tt.mutate(&newCfg)
+
+			da := loadSnapshotTestAllocator(t, &newCfg)
+			if got := da.allocations.snapshot(); !slices.Equal(got, entries) {
+				t.Errorf("allocations after migration = %v, want %v", got, entries)
+			}
+			if report := da.Verify(false); !report.OK() {
+				t.Errorf("Verify() after migration problems = %v", report.Problems)
+			}
+			if fp, _, ok := readFingerprint(newCfg.StatePersistencePath); !ok || fp != fingerprintOf(&newCfg) {
+				t.Errorf("state file fingerprint = %v, want %v", fp, fingerprintOf(&newCfg))
+			}
+			if _, err := os.Stat(newCfg.StatePersistencePath + ".migrate-backup"); err != nil {
+				t.Errorf("backup of original state file: %v", err)
+			}
+
+			var used uint64
+			for _, e := range live {
+				used += e.size
+			}
+			want := float64(used) / float64(newCfg.TotalSize)
+			if got := da.GetDiskUtilization(); got != want {
+				t.Errorf("utilization after migration = %v, want %v", got, want)
+			}
+			for _, e := range live {
+				if err := da.Free(e.address, e.size); err != nil {
+					t.Errorf("Free(%d, %d) after migration error = %v", e.address, e.size, err)
+				}
+			}
+			if got := da.GetDiskUtilization(); got != 0 {
+				t.Errorf("utilization after freeing everything = %v, want 0", got)
+			}
+			checkTreeConsistent(t, da.tree)
+			da.Close()
+		})
+	}
+}
+
+func TestMigrateStateRefusesDataLoss(t *testing.T) {
+	cfg, _, entries := newMigrateTestState(t)
+	before, err := os.ReadFile(cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	newCfg := *cfg
+	newCfg.TotalSize = 8 * 1024 * 1024
+	if _, err := LoadState(&newCfg); !errors.Is(err, ErrMigrationDataLoss) {
+		t.Fatalf("LoadState() with smaller total size error = %v, want %v", err, ErrMigrationDataLoss)
+	}
+	unitCfg := *cfg
+	unitCfg.UnitSize = 8192
+	if _, err := LoadState(&unitCfg); !errors.Is(err, ErrConfigMismatch) {
+		t.Fatalf("LoadState() with different unit size error = %v, want %v", err, ErrConfigMismatch)
+	}
+
+	after, err := os.ReadFile(cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !slices.Equal(before, after) {
+		t.Errorf("state file changed after refused migration")
+	}
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	if got := da.allocations.snapshot(); !slices.Equal(got, entries) {
+		t.Errorf("allocations after refused migration = %v, want %v", got, entries)
+	}
+}
+
+func TestMigrateStateUnevenBitmap(t *testing.T) {
+	cfg := newSnapshotTestConfig(t.TempDir())
+	da := loadSnapshotTestAllocator(t, cfg)
+	for _, start := range []uint64{0, 800, 2000} {
+		if err := da.Reserve(start*4096, 4096); err != nil {
+			t.Fatalf("Reserve() error = %v", err)
+		}
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 1000 个单元分成 4 个分片，每个分片向下取整到 192 个单元，单元 800 落在新位图覆盖不到的尾部；
+	// 200 个单元分成 4 个分片时每个分片为空
+	for _, geometry := range []struct{ smallBlockLimit, numShards uint64 }{{1000, 4}, {200, 4}} {
+		newCfg := *cfg
+		newCfg.SmallBlockLimit, newCfg.NumShards = geometry.smallBlockLimit, geometry.numShards
+		if _, err := LoadState(&newCfg); !errors.Is(err, ErrMigrationDataLoss) {
+			t.Errorf("LoadState() with %d small block units in %d shards error = %v, want %v",
+				geometry.smallBlockLimit, geometry.numShards, err, ErrMigrationDataLoss)
+		}
+	}
+
+	// 覆盖不到的尾部没有存活分配时照常迁移
+	da = loadSnapshotTestAllocator(t, cfg)
+	if err := da.Free(800*4096, 4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	newCfg := *cfg
+	newCfg.SmallBlockLimit = 1000
+	da = loadSnapshotTestAllocator(t, &newCfg)
+	defer da.Close()
+	for _, start := range []uint64{0, 2000} {
+		if err := da.Free(start*4096, 4096); err != nil {
+			t.Errorf("Free(%d) after migration error = %v", start*4096, err)
+		}
+	}
+	checkTreeConsistent(t, da.tree)
+}
@@ -0,0 +1,86 @@
+package allocator
+
+import (
+	"fmt"
+
+	"github.com/google/btree"
+)
+
+// PlacementPolicy 决定 BTreeManager 从哪个空闲块中切分空间
+type PlacementPolicy int
+
+const (
+	PolicyDefault  PlacementPolicy = iota // 使用配置的默认策略
+	PolicyBestFit                         // 大小 >= size 的最小块
+	PolicyFirstFit                        // 地址最低的足够大的块
+	PolicyNextFit                         // 从上次分配结束处开始查找的首个足够大的块
+	PolicyWorstFit                        // 最大的空闲块
+)
+
+var policyNames = map[PlacementPolicy]string{
+	PolicyDefault:  "default",
+	PolicyBestFit:  "best-fit",
+	PolicyFirstFit: "first-fit",
+	PolicyNextFit:  "next-fit",
+	PolicyWorstFit: "worst-fit",
+}
+
+func (p PlacementPolicy) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("PlacementPolicy(%d)", int(p))
+}
+
+// ParsePlacementPolicy 将配置中的策略名转换为 PlacementPolicy，空字符串表示默认策略
+func ParsePlacementPolicy(name string) (PlacementPolicy, error) {
+	if name == "" {
+		return PolicyDefault, nil
+	}
+	for p, n := range policyNames {
+		if n == name {
+			return p, nil
+		}
+	}
+	return PolicyDefault, fmt.Errorf("unknown placement policy %q", name)
+}
+
+// findBlock 按策略查找可容纳 size 的空闲块，调用方需持有写锁
+func (dm *BTreeManager) findBlock(size uint64, policy PlacementPolicy) *BTreeBlock {
+	switch policy {
+	case PolicyFirstFit:
+		return dm.firstFitFrom(0, ^uint64(0), size)
+	case PolicyNextFit:
+		if block := dm.firstFitFrom(dm.cursor, ^uint64(0), size); block != nil {
+			return block
+		}
+		return dm.firstFitFrom(0, dm.cursor, size)
+	case PolicyWorstFit:
+		item := dm.treeBySize.Max()
+		if item == nil || item.(BlockBySize).Size < size {
+			return nil
+		}
+		return item.(BlockBySize).BTreeBlock
+	default:
+		var found *BTreeBlock
+		dm.treeBySize.AscendGreaterOrEqual(BlockBySize{&BTreeBlock{Size: size}}, func(item btree.Item) bool {
+			found = item.(BlockBySize).BTreeBlock
+			return false
+		})
+		return found
+	}
+}
+
+// firstFitFrom 在 [from, to) 内按地址顺序查找第一个足够大的块
+func (dm *BTreeManager) firstFitFrom(from, to, size uint64) *BTreeBlock {
+	var found *BTreeBlock
+	dm.treeByStart.AscendRange(BlockByStart{&BTreeBlock{Start: from}}, BlockByStart{&BTreeBlock{Start: to}}, func(item btree.Item) bool {
+		block := item.(BlockByStart).BTreeBlock
+		if block.Size >= size {
+			found = block
+			return false
+		}
+		return true
+	})
+	return found
+}
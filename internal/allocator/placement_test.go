@@ -0,0 +1,107 @@
+package allocator
+
+import (
+	"testing"
+)
+
+// newFragmentedTree 构造空闲块为 [0,100) [200,250) [400,700) [800,830) 的管理器
+func newFragmentedTree() *BTreeManager {
+	return NewBTreeManagerWithBlocks(1024, []BTreeBlock{
+		{Start: 0, Size: 100},
+		{Start: 200, Size: 50},
+		{Start: 400, Size: 300},
+		{Start: 800, Size: 30},
+	})
+}
+
+func TestPlacementPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   PlacementPolicy
+		sizes    []uint64
+		expected []uint64
+	}{
+		{"BestFit", PolicyBestFit, []uint64{40, 30, 90}, []uint64{200, 800, 0}},
+		{"FirstFit", PolicyFirstFit, []uint64{40, 30, 90}, []uint64{0, 40, 400}},
+		{"NextFit", PolicyNextFit, []uint64{40, 60, 50, 30}, []uint64{0, 40, 200, 400}},
+		{"WorstFit", PolicyWorstFit, []uint64{40, 30, 200}, []uint64{400, 440, 470}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dm := newFragmentedTree()
+			for i, size := range tt.sizes {
+				start, err := dm.AllocateWithPolicy(size, tt.policy)
+				if err != nil {
+					t.Fatalf("Allocate(%d) error = %v", size, err)
+				}
+				if start != tt.expected[i] {
+					t.Errorf("Allocate(%d) start = %d, want %d", size, start, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextFitWrapsAround(t *testing.T) {
+	dm := newFragmentedTree()
+	dm.SetPolicy(PolicyNextFit)
+
+	if start, _ := dm.Allocate(300); start != 400 {
+		t.Fatalf("Allocate() start = %d, want 400", start)
+	}
+	// 游标之后只剩 [800,830)，80 需要回绕到开头
+	start, err := dm.Allocate(80)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if start != 0 {
+		t.Errorf("Allocate() start = %d, want 0", start)
+	}
+}
+
+func TestEqualSizedBlocksAreKept(t *testing.T) {
+	dm := NewBTreeManagerWithBlocks(1024, []BTreeBlock{
+		{Start: 0, Size: 64},
+		{Start: 128, Size: 64},
+		{Start: 256, Size: 64},
+	})
+	if dm.treeBySize.Len() != 3 {
+		t.Fatalf("treeBySize len = %d, want 3", dm.treeBySize.Len())
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := dm.Allocate(64); err != nil {
+			t.Fatalf("Allocate() #%d error = %v", i, err)
+		}
+	}
+	if dm.GetAvailableSpace() != 0 {
+		t.Errorf("Expected available space 0, got %d", dm.GetAvailableSpace())
+	}
+}
+
+func TestParsePlacementPolicy(t *testing.T) {
+	for _, p := range []PlacementPolicy{PolicyBestFit, PolicyFirstFit, PolicyNextFit, PolicyWorstFit} {
+		parsed, err := ParsePlacementPolicy(p.String())
+		if err != nil || parsed != p {
+			t.Errorf("ParsePlacementPolicy(%q) = %v, %v", p.String(), parsed, err)
+		}
+	}
+	if p, err := ParsePlacementPolicy(""); err != nil || p != PolicyDefault {
+		t.Errorf("ParsePlacementPolicy(\"\") = %v, %v", p, err)
+	}
+	if _, err := ParsePlacementPolicy("random-fit"); err == nil {
+		t.Error("ParsePlacementPolicy should reject unknown policy")
+	}
+}
+
+func TestGetFragmentation(t *testing.T) {
+	dm := NewBTreeManager(1024)
+	if f := dm.GetFragmentation(); f != 0 {
+		t.Errorf("GetFragmentation() = %f, want 0", f)
+	}
+	dm = newFragmentedTree()
+	want := 1 - 300.0/480.0
+	if f := dm.GetFragmentation(); f != want {
+		t.Errorf("GetFragmentation() = %f, want %f", f, want)
+	}
+}
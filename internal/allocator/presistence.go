@@ -1,129 +1,270 @@
 package allocator
 
 import (
-	"encoding/gob"
+	"errors"
 	"fmt"
+	"log"
 	"os"
-	"path/filepath"
+	"sync/atomic"
 	"time"
 
-	"github.com/google/btree"
-
 	"github.com/li1213987842/spaceweave/config"
 )
 
 type persistentData struct {
 	Bitmaps  [][]uint64
 	TreeData []BTreeBlock
+	// 旧版本状态文件没有分配表，TracksAllocations 为 false 时从位图和空闲树重建
+	TracksAllocations bool
+	Allocations       []AllocationEntry
+	NextSessionID     uint64
+	Sessions          []SessionState
+	TTLs              []TTLEntry
+	TenantQuotas      []TenantQuota
+	TenantOwners      []TenantEntry
+	// WALSeq 是快照已包含的最后一条 WAL 记录的序号，加载时只重放之后的记录
+	WALSeq uint64
+	// ResizedFrom 是 Grow/Shrink 在线调整大小前配置中的 TotalSize，文件头中的 TotalSize 为调整后的大小，0 表示未调整
+	ResizedFrom uint64
+
+	// CheckpointID 标识一个完整快照。增量检查点的 CheckpointID 为其所基于的完整快照，
+	// DeltaIndex 为其在检查点链中的序号（从 1 开始），完整快照的 DeltaIndex 为 0
+	CheckpointID uint64
+	DeltaIndex   uint64
+	// 以下字段只出现在增量检查点中
+	BitmapWords       []bitmapWord
+	TreeRanges        []treeRange
+	AllocationUpserts []AllocationEntry
+	AllocationDeletes []uint64
 }
 
 func (da *diskAllocatorImpl) SaveState() error {
+	da.saveMu.Lock()
+	defer da.saveMu.Unlock()
+
+	// 序号之前的记录都已作用到内存，之后的修改即使部分进入快照，重放时也是幂等的
+	da.walMu.Lock()
+	walSeq := da.wal.lastSeq()
+	da.walMu.Unlock()
+
+	if da.deltaEnabled() && da.checkpoint.ID != 0 && !da.forceFull && da.checkpoint.Deltas < da.cfg.DeltaCheckpointLimit {
+		return da.saveDelta(walSeq)
+	}
+	return da.saveFull(walSeq)
+}
+
+// saveFull 写入完整快照，之后的增量检查点以它为基准
+func (da *diskAllocatorImpl) saveFull(walSeq uint64) error {
 	data := persistentData{
-		Bitmaps:  make([][]uint64, len(da.bitmaps.shards)),
-		TreeData: make([]BTreeBlock, 0),
-	}
-
-	// Save bitmap data
-	for i, shard := range da.bitmaps.shards {
-		shard.mu.RLock()
-		data.Bitmaps[i] = make([]uint64, len(shard.bits))
-		copy(data.Bitmaps[i], shard.bits)
-		shard.mu.RUnlock()
-	}
-
-	aval := uint64(0)
-	// Save btree data
-	da.tree.mu.RLock()
-	da.tree.treeByStart.Ascend(func(item btree.Item) bool {
-		block := item.(BlockByStart).BTreeBlock
-		data.TreeData = append(data.TreeData, *block)
-		aval += block.Size
-		return true
-	})
-	da.tree.mu.RUnlock()
-
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(da.cfg.StatePersistencePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+		WALSeq:       walSeq,
+		ResizedFrom:  da.resizedFrom(),
+		CheckpointID: newCheckpointID(),
+	}
+
+	// 复制之前清空脏记录，复制期间的修改会同时进入快照和下一个增量检查点
+	da.forceFull = true
+	da.clearDirty()
+
+	// 位图、分配表和空闲树都以写时复制的方式取快照，只在取引用时短暂加锁，
+	// 编码和写文件期间分配和释放照常进行
+	data.Bitmaps = da.bitmaps.snapshot()
+	data.TracksAllocations = true
+	data.Allocations = da.allocations.snapshot()
+	data.NextSessionID, data.Sessions = da.leases.snapshot()
+	data.TTLs = da.ttls.snapshot()
+	data.TenantQuotas, data.TenantOwners = da.tenants.snapshot()
+	data.TreeData = da.tree.snapshot()
+
+	// 当前快照先转入历史，再原子地替换为新快照
+	if err := da.rotateSnapshot(); err != nil {
+		return fmt.Errorf("failed to rotate snapshot: %w", err)
+	}
+	now := time.Now()
+	if err := writeStateFile(da.cfg.StatePersistencePath, da.fingerprint(), &data); err != nil {
 		return err
 	}
+	da.current = snapshotRef{Time: now, WALSeq: walSeq}
+	da.checkpoint = checkpointRef{ID: data.CheckpointID}
+	da.forceFull = false
+	if err := removeDeltas(da.cfg); err != nil {
+		return fmt.Errorf("failed to remove delta checkpoints: %w", err)
+	}
 
-	tempFile, err := os.CreateTemp(filepath.Dir(da.cfg.StatePersistencePath), "temp_state_*")
+	// 快照已落地，截断所有保留快照中都已包含的 WAL 记录，回退到任一历史快照时仍可重放到最新状态
+	retained, err := pruneSnapshots(da.cfg, now)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return fmt.Errorf("failed to prune snapshots: %w", err)
 	}
-	tempFilePath := tempFile.Name()
-	defer os.Remove(tempFilePath)
-
-	// 写入临时文件
-	encoder := gob.NewEncoder(tempFile)
-	if err := encoder.Encode(data); err != nil {
-		tempFile.Close()
-		return fmt.Errorf("failed to encode state: %w", err)
+	truncateSeq := walSeq
+	for _, snapshot := range retained {
+		truncateSeq = min(truncateSeq, snapshot.WALSeq)
 	}
-	tempFile.Close()
+	return da.wal.truncate(truncateSeq)
+}
 
-	// 重命名临时文件
-	if err := os.Rename(tempFilePath, da.cfg.StatePersistencePath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+func LoadState(cfg *config.Config) (DiskAllocator, error) {
+	// 状态文件的布局与配置不同时先迁移到新布局
+	if err := migrateState(cfg); err != nil {
+		return nil, err
 	}
-
-	return nil
+	da, err := openState(cfg)
+	if err != nil {
+		return nil, err
+	}
+	da.startLeaseRoutine()
+	da.startTTLReaperRoutine()
+	da.startVerifyRoutine()
+	if cfg.StatePersistencePath != "" {
+		da.startBackupRoutine()
+	}
+	return da, nil
 }
 
-func LoadState(cfg *config.Config) (DiskAllocator, error) {
+// openState 按 cfg 加载快照、增量检查点并重放 WAL，不启动后台任务
+func openState(cfg *config.Config) (*diskAllocatorImpl, error) {
+	policy, err := ParsePlacementPolicy(cfg.PlacementPolicy)
+	if err != nil {
+		return nil, err
+	}
+	walPolicy, err := ParseWALSyncPolicy(cfg.WALSyncPolicy)
+	if err != nil {
+		return nil, err
+	}
+
 	da := &diskAllocatorImpl{
 		cfg:            cfg,
 		bitmaps:        NewBitMap(cfg.SmallBlockLimit, cfg.NumShards),
 		tree:           NewBTreeManager(cfg.TotalSize/cfg.UnitSize - cfg.SmallBlockLimit),
+		allocations:    newAllocationTable(),
+		leases:         newLeaseTable(),
+		ttls:           newTTLTable(),
+		tenants:        newTenantTable(),
+		total:          cfg.TotalSize,
 		lastBackupTime: time.Now(),
 		closeChan:      make(chan struct{}),
 	}
+	da.tree.SetPolicy(policy)
 
 	// No state persistence
 	if cfg.StatePersistencePath == "" {
 		return da, nil
 	}
 
-	if _, err := os.Stat(cfg.StatePersistencePath); os.IsNotExist(err) {
-		da.startBackupRoutine()
-		return da, nil
+	walSeq, err := da.loadSnapshot(cfg.StatePersistencePath)
+	if errors.Is(err, ErrCorruptState) || errors.Is(err, ErrChecksumMismatch) {
+		walSeq, err = da.loadPreviousSnapshot(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	da.tree.SetPolicy(policy)
+	if da.deltaEnabled() {
+		if walSeq, err = da.loadDeltas(walSeq); err != nil {
+			return nil, err
+		}
+		// 之后 WAL 重放的修改尚未写入任何检查点，需要记录
+		da.trackDirty()
+	}
+
+	if walPolicy != WALSyncOff {
+		path := walPath(cfg)
+		records, validSize, err := readWAL(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > 0 && records[0].Seq > walSeq+1 {
+			log.Printf("wal %s starts at seq %d but snapshot ends at %d, records in between are lost", path, records[0].Seq, walSeq)
+		}
+		for len(records) > 0 && records[0].Seq <= walSeq {
+			records = records[1:]
+		}
+		da.replay(records)
+		if len(records) > 0 {
+			walSeq = records[len(records)-1].Seq
+		}
+		interval := time.Duration(cfg.WALSyncIntervalMs) * time.Millisecond
+		if da.wal, err = openWAL(path, walPolicy, interval, validSize, walSeq); err != nil {
+			return nil, err
+		}
+	}
+	da.tenants.reconcile(da.allocations, cfg.UnitSize)
+
+	return da, nil
+}
+
+// walPath 返回 WAL 文件路径，未配置时放在快照文件旁边
+func walPath(cfg *config.Config) string {
+	if cfg.WALPath != "" {
+		return cfg.WALPath
+	}
+	return cfg.StatePersistencePath + ".wal"
+}
+
+// loadSnapshot 从快照文件 path 恢复内存状态，返回快照包含的最后一条 WAL 记录的序号。
+// 快照文件不存在或为空时保持初始状态。校验全部通过后才修改内存状态，失败时可以换一个快照重试
+func (da *diskAllocatorImpl) loadSnapshot(path string) (uint64, error) {
+	cfg := da.cfg
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
 	}
 
 	// Open file for reading
-	file, err := os.Open(cfg.StatePersistencePath)
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open state file: %w", err)
+		return 0, fmt.Errorf("failed to open state file: %w", err)
 	}
 	defer file.Close()
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %w", err)
+		return 0, fmt.Errorf("failed to get file info: %w", err)
 	}
 	// Check if file is empty
 	if fileInfo.Size() == 0 {
-		return da, nil
+		return 0, nil
 	}
 	// Decode data
-	var data persistentData
-	decoder := gob.NewDecoder(file)
-	if err := decoder.Decode(&data); err != nil {
-		return nil, err
+	data, fp, legacy, err := decodeState(file)
+	if err != nil {
+		return 0, err
+	}
+	if want := fingerprintOf(cfg); !legacy && !geometryMatches(cfg, fp, data.ResizedFrom) {
+		return 0, fmt.Errorf("%w: file has %v, config has %v", ErrConfigMismatch, fp, want)
+	}
+	if data.DeltaIndex != 0 {
+		return 0, fmt.Errorf("%w: %s is a delta checkpoint", ErrCorruptState, path)
 	}
 
 	// Restore bitmap data
 	if len(data.Bitmaps) != len(da.bitmaps.shards) {
-		return nil, fmt.Errorf("mismatch in number of bitmap shards")
+		return 0, fmt.Errorf("mismatch in number of bitmap shards")
 	}
 	for i, bits := range data.Bitmaps {
 		if len(bits) != len(da.bitmaps.shards[i].bits) {
-			return nil, fmt.Errorf("mismatch in bitmap size for shard %d", i)
+			return 0, fmt.Errorf("mismatch in bitmap size for shard %d", i)
 		}
+	}
+	for i, bits := range data.Bitmaps {
 		copy(da.bitmaps.shards[i].bits, bits)
 	}
+	da.bitmaps.recount()
 	// Restore btree data
-	da.tree = NewBTreeManagerWithBlocks(cfg.TotalSize/cfg.UnitSize-cfg.SmallBlockLimit, data.TreeData)
-	da.startBackupRoutine()
-	return da, nil
+	total := cfg.TotalSize
+	if !legacy {
+		total = fp.TotalSize
+	}
+	da.tree = NewBTreeManagerWithBlocks(total/cfg.UnitSize-cfg.SmallBlockLimit, data.TreeData)
+	atomic.StoreUint64(&da.total, total)
+
+	if data.TracksAllocations {
+		da.allocations.load(data.Allocations)
+	} else {
+		da.allocations.load(rebuildLegacyAllocations(da.bitmaps, da.tree, cfg.SmallBlockLimit))
+	}
+	da.leases.load(data.NextSessionID, data.Sessions)
+	da.ttls.load(data.TTLs)
+	da.tenants.load(data.TenantQuotas, data.TenantOwners)
+	da.current = snapshotRef{Time: fileInfo.ModTime(), WALSeq: data.WALSeq}
+	da.checkpoint = checkpointRef{ID: data.CheckpointID}
+	return data.WALSeq, nil
 }
@@ -1,6 +1,7 @@
 package allocator
 
 import (
+	"encoding/gob"
 	"math"
 	"os"
 	"testing"
@@ -134,3 +135,54 @@ func TestSaveAndLoadStateDetailed(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadLegacyStateWithoutAllocationTable(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test-legacy-state-*.gob")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	cfg := &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: tmpfile.Name(),
+		BackupIntervalSec:    5,
+	}
+
+	// 旧格式只包含位图和空闲树：位图单元 [0,4) 与 B 树单元 [0,256) 已分配
+	legacy := struct {
+		Bitmaps  [][]uint64
+		TreeData []BTreeBlock
+	}{
+		Bitmaps:  [][]uint64{{0xf, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}},
+		TreeData: []BTreeBlock{{Start: 256, Size: 16384 - 1024 - 256}},
+	}
+	if err := gob.NewEncoder(tmpfile).Encode(legacy); err != nil {
+		t.Fatalf("Failed to encode legacy state: %v", err)
+	}
+	tmpfile.Close()
+
+	da, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load legacy state: %v", err)
+	}
+	defer da.Close()
+
+	// 旧的已分配区间可以按原来的粒度逐段释放
+	if err := da.Free(cfg.UnitSize, cfg.UnitSize); err != nil {
+		t.Errorf("Failed to free legacy bitmap unit: %v", err)
+	}
+	if err := da.Free(cfg.UnitSize, cfg.UnitSize); err != ErrNotAllocated {
+		t.Errorf("Expected ErrNotAllocated on double free, got %v", err)
+	}
+	treeBase := cfg.SmallBlockLimit * cfg.UnitSize
+	if err := da.Free(treeBase, 128*cfg.UnitSize); err != nil {
+		t.Errorf("Failed to free legacy tree range: %v", err)
+	}
+	if err := da.Free(treeBase+200*cfg.UnitSize, 100*cfg.UnitSize); err != ErrSizeMismatch {
+		t.Errorf("Expected ErrSizeMismatch when freeing past legacy range, got %v", err)
+	}
+}
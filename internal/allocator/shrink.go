@@ -0,0 +1,130 @@
+package allocator
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// Shrink 将管理的空间缩小到 newTotalSize 字节，只能移除 B 树区末尾的空间。
+// 末尾 [newTotalSize, 当前大小) 已全部空闲时立即完成，写入 WAL 和完整快照后 done 为 true；
+// 否则禁止在该范围内分配（之后释放到该范围的空间也不再参与分配），返回其中仍存活、需要先迁移的分配，done 为 false。
+// 迁移完成后以相同的大小再次调用即可完成收缩；以当前大小调用会取消未完成的收缩。
+// 未完成收缩的分配限制只保存在内存中，重启后需要重新调用
+func (da *diskAllocatorImpl) Shrink(newTotalSize uint64) (relocate []Extent, done bool, err error) {
+	unit, limit := da.cfg.UnitSize, da.cfg.SmallBlockLimit
+	if newTotalSize%unit != 0 {
+		return nil, false, fmt.Errorf("%w: %d is not a multiple of unit size %d", ErrInvalidTotalSize, newTotalSize, unit)
+	}
+	if newTotalSize <= limit*unit {
+		return nil, false, fmt.Errorf("%w: %d does not leave any space above the bitmap region", ErrInvalidTotalSize, newTotalSize)
+	}
+
+	// 与 Grow 相同，saveMu 避免进行中的保存混用收缩前后的布局
+	da.saveMu.Lock()
+	da.walMu.Lock()
+	old := da.totalSize()
+	if newTotalSize > old {
+		da.walMu.Unlock()
+		da.saveMu.Unlock()
+		return nil, false, fmt.Errorf("%w: %d is larger than current size %d", ErrInvalidTotalSize, newTotalSize, old)
+	}
+	newUnits := newTotalSize / unit
+	if !da.tree.setFence(newUnits - limit) {
+		da.walMu.Unlock()
+		da.saveMu.Unlock()
+		// 已从空闲树取出但还没有写入分配表的分配不在列表中，下一次调用时会出现
+		for _, entry := range da.allocations.beyond(newUnits) {
+			relocate = append(relocate, Extent{Address: entry.Start * unit, Size: entry.Size * unit})
+		}
+		log.Printf("shrink to %d bytes pending: %d extents to relocate", newTotalSize, len(relocate))
+		return relocate, false, nil
+	}
+	if newTotalSize == old {
+		da.walMu.Unlock()
+		da.saveMu.Unlock()
+		return nil, true, nil
+	}
+	record := walRecord{Op: walOpShrink, Start: newUnits, Units: old/unit - newUnits}
+	if err := da.wal.append(record); err != nil {
+		da.walMu.Unlock()
+		da.saveMu.Unlock()
+		return nil, false, err
+	}
+	da.shrinkUnits(newUnits)
+	da.walMu.Unlock()
+	da.saveMu.Unlock()
+	log.Printf("shrank managed space from %d to %d bytes", old, newTotalSize)
+
+	if da.cfg.StatePersistencePath == "" {
+		return nil, true, nil
+	}
+	return nil, true, da.SaveState()
+}
+
+// shrinkUnits 将总单元数缩小到 totalUnits，不大于当前大小时不做修改，WAL 重放时可以重复调用。
+// 调用方需持有 saveMu 或处于加载阶段
+func (da *diskAllocatorImpl) shrinkUnits(totalUnits uint64) {
+	if totalUnits >= da.totalSize()/da.cfg.UnitSize {
+		return
+	}
+	da.tree.shrink(totalUnits - da.cfg.SmallBlockLimit)
+	atomic.StoreUint64(&da.total, totalUnits*da.cfg.UnitSize)
+	// 之前的增量检查点基于旧的布局，下一次保存必须是完整快照
+	da.forceFull = true
+}
+
+// setFence 禁止在 [fence, totalSpace) 中分配，fence 不小于 totalSpace 时取消限制。返回该范围是否已全部空闲
+func (dm *BTreeManager) setFence(fence uint64) bool {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if fence >= dm.totalSpace {
+		fence = 0
+	}
+	dm.setFenceLocked(fence)
+	return dm.fence == 0 || len(dm.fenced) == 1 && dm.fenced[0] == BTreeBlock{Start: dm.fence, Size: dm.totalSpace - dm.fence}
+}
+
+// setFenceLocked 先将之前隔离的空闲块按新的 fence 重新释放，再把 fence 之后仍在索引中的空闲块移入 fenced，调用方需持有写锁
+func (dm *BTreeManager) setFenceLocked(fence uint64) {
+	held := dm.fenced
+	dm.fence, dm.fenced = fence, nil
+	for _, block := range held {
+		dm.freeLocked(block.Start, block.Size)
+	}
+	if fence == 0 {
+		return
+	}
+	for _, block := range dm.overlapping(fence, dm.totalSpace, false) {
+		from, to := max(block.Start, fence), block.Start+block.Size
+		dm.carve(block, from, to-from)
+		dm.fenced = mergeRanges(append(dm.fenced, BTreeBlock{Start: from, Size: to - from}))
+	}
+}
+
+// idleSpace 返回空闲单元数，包括收缩中被隔离、不参与分配的空闲块
+func (dm *BTreeManager) idleSpace() uint64 {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	idle := dm.freeSpace
+	for _, block := range dm.fenced {
+		idle += block.Size
+	}
+	return idle
+}
+
+// shrink 将树区缩小到 totalSpace 个单元并取消收缩限制，超出部分的空闲块直接丢弃
+func (dm *BTreeManager) shrink(totalSpace uint64) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if totalSpace >= dm.totalSpace {
+		return
+	}
+	dm.fence, dm.fenced = 0, nil
+	for _, block := range dm.overlapping(totalSpace, dm.totalSpace, false) {
+		from := max(block.Start, totalSpace)
+		dm.carve(block, from, block.Start+block.Size-from)
+	}
+	dm.totalSpace = totalSpace
+	dm.cursor = min(dm.cursor, totalSpace)
+}
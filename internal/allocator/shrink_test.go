@@ -0,0 +1,178 @@
+package allocator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// checkShrunk 检查收缩后树区末尾的空闲块止于新的总大小，状态一致
+func checkShrunk(t *testing.T, da *diskAllocatorImpl, newTotal uint64) {
+	t.Helper()
+	if got := da.GetTotalSize(); got != newTotal {
+		t.Fatalf("GetTotalSize() = %d, want %d", got, newTotal)
+	}
+	checkTreeConsistent(t, da.tree)
+	if report := da.Verify(false); !report.OK() {
+		t.Errorf("Verify() after shrink problems = %v", report.Problems)
+	}
+	blocks := blocksOf(da.tree)
+	last := blocks[len(blocks)-1]
+	if treeEnd := newTotal/da.cfg.UnitSize - da.cfg.SmallBlockLimit; last.Start+last.Size != treeEnd {
+		t.Errorf("last free block = %+v, want one ending at %d", last, treeEnd)
+	}
+	if err := da.Reserve(newTotal, da.cfg.UnitSize); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Reserve() beyond new total size error = %v, want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestShrinkFreeTail(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+	if _, err := da.Allocate(1024 * 1024); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+
+	newTotal := cfg.TotalSize / 2
+	relocate, done, err := da.Shrink(newTotal)
+	if err != nil || !done || len(relocate) != 0 {
+		t.Fatalf("Shrink() = %v, %v, %v, want done", relocate, done, err)
+	}
+	checkShrunk(t, da, newTotal)
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 配置不变时重启沿用收缩后的大小
+	da = loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	checkShrunk(t, da, newTotal)
+	report, err := CheckState(cfg, cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("CheckState() error = %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("CheckState() problems = %v", report.Problems)
+	}
+}
+
+func TestShrinkRelocation(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	newTotal := cfg.TotalSize / 2
+	tail := newTotal + 1024*1024
+	if err := da.Reserve(tail, 64*4096); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	utilization := da.GetDiskUtilization()
+
+	relocate, done, err := da.Shrink(newTotal)
+	if err != nil || done {
+		t.Fatalf("Shrink() = %v, %v, %v, want pending", relocate, done, err)
+	}
+	if want := []Extent{{Address: tail, Size: 64 * 4096}}; !slices.Equal(relocate, want) {
+		t.Errorf("Shrink() relocate = %v, want %v", relocate, want)
+	}
+	checkTreeConsistent(t, da.tree)
+	// 被隔离的空闲块仍按空闲计算利用率
+	if got := da.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization with fenced tail = %v, want %v", got, utilization)
+	}
+	if report := da.Verify(false); !report.OK() || report.Utilization != utilization {
+		t.Errorf("Verify() with fenced tail = %+v, want utilization %v", report, utilization)
+	}
+
+	// 末尾不再参与分配，保存的快照中仍为空闲
+	if err := da.Reserve(tail+64*4096, 4096); !errors.Is(err, ErrRangeInUse) {
+		t.Errorf("Reserve() in fenced tail error = %v, want %v", err, ErrRangeInUse)
+	}
+	treeSize := newTotal - cfg.SmallBlockLimit*cfg.UnitSize
+	if _, err := da.Allocate(treeSize + 4096); !errors.Is(err, ErrNoSpaceLeft) {
+		t.Errorf("Allocate() across fenced tail error = %v, want %v", err, ErrNoSpaceLeft)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if report, err := CheckState(cfg, cfg.StatePersistencePath); err != nil || !report.OK() {
+		t.Errorf("CheckState() with pending shrink = %v, %v", report, err)
+	}
+
+	// 取消后末尾恢复可用
+	if _, done, err := da.Shrink(cfg.TotalSize); err != nil || !done {
+		t.Fatalf("Shrink() to current size = %v, %v, want done", done, err)
+	}
+	if got := da.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after cancel = %v, want %v", got, utilization)
+	}
+	if err := da.Reserve(tail+64*4096, 4096); err != nil {
+		t.Errorf("Reserve() after cancel error = %v", err)
+	}
+	if err := da.Free(tail+64*4096, 4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+
+	// 迁移后再次调用完成收缩
+	if _, done, _ := da.Shrink(newTotal); done {
+		t.Fatalf("Shrink() with live tail allocation is done")
+	}
+	moved, err := da.Allocate(64 * 4096)
+	if err != nil {
+		t.Fatalf("Allocate() for relocation error = %v", err)
+	}
+	if moved+64*4096 > newTotal {
+		t.Errorf("Allocate() = %d, landed in fenced tail", moved)
+	}
+	if err := da.Free(tail, 64*4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if relocate, done, err := da.Shrink(newTotal); err != nil || !done || len(relocate) != 0 {
+		t.Fatalf("Shrink() after relocation = %v, %v, %v, want done", relocate, done, err)
+	}
+	checkShrunk(t, da, newTotal)
+}
+
+func TestShrinkReplaysFromWAL(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	// 只写入 WAL 记录，模拟保存快照之前崩溃
+	newTotal := cfg.TotalSize - 8*1024*1024
+	unit := cfg.UnitSize
+	da.saveMu.Lock()
+	da.walMu.Lock()
+	err := da.wal.append(walRecord{Op: walOpShrink, Start: newTotal / unit, Units: (cfg.TotalSize - newTotal) / unit})
+	if err == nil {
+		da.shrinkUnits(newTotal / unit)
+	}
+	da.walMu.Unlock()
+	da.saveMu.Unlock()
+	if err != nil {
+		t.Fatalf("append() error = %v", err)
+	}
+	crash(da)
+
+	da = loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	checkShrunk(t, da, newTotal)
+}
+
+func TestShrinkRejectsInvalidSize(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	cfg.StatePersistencePath = ""
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	for _, size := range []uint64{cfg.TotalSize + 4096, cfg.TotalSize - 100, cfg.SmallBlockLimit * cfg.UnitSize} {
+		if _, _, err := da.Shrink(size); !errors.Is(err, ErrInvalidTotalSize) {
+			t.Errorf("Shrink(%d) error = %v, want %v", size, err, ErrInvalidTotalSize)
+		}
+	}
+	if got := da.GetTotalSize(); got != cfg.TotalSize {
+		t.Errorf("GetTotalSize() after rejected shrinks = %d, want %d", got, cfg.TotalSize)
+	}
+}
@@ -0,0 +1,333 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// 历史快照保存在 StatePersistencePath + ".snapshots" 目录下，文件名为 "<UTC 时间>-<WAL 序号>"，
+// 按文件名排序即按时间排序
+const snapshotTimeFormat = "20060102T150405.000000000Z"
+
+// SnapshotInfo 描述一个快照。当前快照的 Name 为 "current"，其 WAL 序号不从文件名中解析，总为 0
+type SnapshotInfo struct {
+	Name    string
+	Path    string
+	Time    time.Time
+	WALSeq  uint64
+	Size    int64
+	Current bool
+}
+
+const currentSnapshotName = "current"
+
+// snapshotRef 记录当前快照的写入时间和 WAL 序号，转入历史时用于命名
+type snapshotRef struct {
+	Time   time.Time
+	WALSeq uint64
+}
+
+func snapshotDir(cfg *config.Config) string {
+	return cfg.StatePersistencePath + ".snapshots"
+}
+
+func snapshotName(t time.Time, seq uint64) string {
+	return t.UTC().Format(snapshotTimeFormat) + "-" + strconv.FormatUint(seq, 10)
+}
+
+func parseSnapshotName(name string) (time.Time, uint64, bool) {
+	ts, seqText, ok := strings.Cut(name, "-")
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	t, err := time.Parse(snapshotTimeFormat, ts)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return t, seq, true
+}
+
+// ListSnapshots 列出当前快照和保留的历史快照，按时间从新到旧排列
+func ListSnapshots(cfg *config.Config) ([]SnapshotInfo, error) {
+	history, err := listHistory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(cfg.StatePersistencePath)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	current := SnapshotInfo{
+		Name:    currentSnapshotName,
+		Path:    cfg.StatePersistencePath,
+		Time:    info.ModTime(),
+		Size:    info.Size(),
+		Current: true,
+	}
+	return append([]SnapshotInfo{current}, history...), nil
+}
+
+// listHistory 列出历史快照，按时间从新到旧排列，忽略无法识别的文件
+func listHistory(cfg *config.Config) ([]SnapshotInfo, error) {
+	dir := snapshotDir(cfg)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot dir: %w", err)
+	}
+
+	snapshots := make([]SnapshotInfo, 0, len(entries))
+	for _, entry := range entries {
+		t, seq, ok := parseSnapshotName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, SnapshotInfo{
+			Name:   entry.Name(),
+			Path:   filepath.Join(dir, entry.Name()),
+			Time:   t,
+			WALSeq: seq,
+			Size:   info.Size(),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name > snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+// rotateSnapshot 在覆盖当前快照之前将其链接（或复制）到历史目录。
+// 当前快照在替换前一直存在，崩溃时不会出现没有快照的窗口
+func (da *diskAllocatorImpl) rotateSnapshot() error {
+	if da.cfg.SnapshotRetention <= 1 {
+		return nil
+	}
+	info, err := os.Stat(da.cfg.StatePersistencePath)
+	if os.IsNotExist(err) || (err == nil && info.Size() == 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ref := da.current
+	if ref.Time.IsZero() {
+		ref.Time = info.ModTime()
+	}
+	dir := snapshotDir(da.cfg)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return linkOrCopy(da.cfg.StatePersistencePath, filepath.Join(dir, snapshotName(ref.Time, ref.WALSeq)))
+}
+
+// pruneSnapshots 按保留策略删除多余的历史快照，返回保留下来的历史快照。
+// 当前快照计入 SnapshotRetention，SnapshotMaxAgeSec 为 0 时不按时间淘汰
+func pruneSnapshots(cfg *config.Config, now time.Time) ([]SnapshotInfo, error) {
+	history, err := listHistory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	maxAge := time.Duration(cfg.SnapshotMaxAgeSec) * time.Second
+
+	var retained []SnapshotInfo
+	var errs []error
+	for i, snapshot := range history {
+		if i < cfg.SnapshotRetention-1 && (maxAge == 0 || now.Sub(snapshot.Time) <= maxAge) {
+			retained = append(retained, snapshot)
+			continue
+		}
+		if err := os.Remove(snapshot.Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return retained, errors.Join(errs...)
+}
+
+// loadPreviousSnapshot 在当前快照无法解码或校验失败时，依次尝试较新的历史快照。
+// 成功后将损坏的当前快照改名为 ".corrupt" 留待排查
+func (da *diskAllocatorImpl) loadPreviousSnapshot(cause error) (uint64, error) {
+	history, err := listHistory(da.cfg)
+	if err != nil {
+		return 0, errors.Join(cause, err)
+	}
+
+	errs := []error{cause}
+	for _, snapshot := range history {
+		seq, err := da.loadSnapshot(snapshot.Path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("snapshot %s: %w", snapshot.Name, err))
+			continue
+		}
+		da.current = snapshotRef{Time: snapshot.Time, WALSeq: seq}
+		// 当前快照已不可用，增量检查点无法接在历史快照之后，下一次保存写完整快照
+		da.forceFull = true
+		if err := os.Rename(da.cfg.StatePersistencePath, da.cfg.StatePersistencePath+".corrupt"); err != nil {
+			return 0, err
+		}
+		log.Printf("state file %s is unreadable (%v), fell back to snapshot %s", da.cfg.StatePersistencePath, cause, snapshot.Name)
+		return seq, nil
+	}
+	return 0, fmt.Errorf("no usable snapshot: %w", errors.Join(errs...))
+}
+
+// RestoreSnapshot 将历史快照 name 恢复为当前快照，需在服务停止时执行。
+// 恢复前的当前快照会先转入历史，WAL 被清空，之后的操作从所选快照的时间点继续
+func RestoreSnapshot(cfg *config.Config, name string) error {
+	history, err := listHistory(cfg)
+	if err != nil {
+		return err
+	}
+	var chosen *SnapshotInfo
+	maxSeq := uint64(0)
+	for i := range history {
+		if history[i].Name == name {
+			chosen = &history[i]
+		}
+		maxSeq = max(maxSeq, history[i].WALSeq)
+	}
+	if chosen == nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+
+	file, err := os.Open(chosen.Path)
+	if err != nil {
+		return err
+	}
+	data, fp, legacy, err := decodeState(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("snapshot %s: %w", name, err)
+	}
+	if want := fingerprintOf(cfg); !legacy && !geometryMatches(cfg, fp, data.ResizedFrom) {
+		return fmt.Errorf("%w: file has %v, config has %v", ErrConfigMismatch, fp, want)
+	}
+	if legacy {
+		fp = fingerprintOf(cfg)
+	}
+
+	// 保留恢复前的当前快照，便于撤销本次恢复
+	if file, err := os.Open(cfg.StatePersistencePath); err == nil {
+		current, _, _, derr := decodeState(file)
+		info, serr := file.Stat()
+		file.Close()
+		if serr != nil {
+			return serr
+		}
+		var seq uint64
+		if derr == nil {
+			seq = current.WALSeq
+			maxSeq = max(maxSeq, seq)
+		}
+		if info.Size() > 0 {
+			if err := os.MkdirAll(snapshotDir(cfg), 0755); err != nil {
+				return err
+			}
+			if err := linkOrCopy(cfg.StatePersistencePath, filepath.Join(snapshotDir(cfg), snapshotName(info.ModTime(), seq))); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 新的 WAL 序号不能与被放弃的记录重复
+	records, _, err := readWAL(walPath(cfg))
+	if err != nil {
+		return err
+	}
+	if len(records) > 0 {
+		maxSeq = max(maxSeq, records[len(records)-1].Seq)
+	}
+	data.WALSeq = maxSeq
+	data.CheckpointID = newCheckpointID()
+
+	if err := writeStateFile(cfg.StatePersistencePath, fp, data); err != nil {
+		return err
+	}
+	if err := removeDeltas(cfg); err != nil {
+		return fmt.Errorf("failed to remove delta checkpoints: %w", err)
+	}
+	if err := os.Truncate(walPath(cfg), 0); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to reset wal: %w", err)
+	}
+	return nil
+}
+
+// writeStateFile 将状态写入临时文件并落盘，再原子地替换 path
+func writeStateFile(path string, fp stateFingerprint, data *persistentData) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), "temp_state_*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+
+	if err := encodeState(tempFile, fp, data); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to sync state: %w", err)
+	}
+	tempFile.Close()
+
+	if err := os.Rename(tempFilePath, path); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// linkOrCopy 优先用硬链接保留文件，文件系统不支持时退化为复制
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil || os.IsExist(err) {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
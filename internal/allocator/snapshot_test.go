@@ -0,0 +1,148 @@
+package allocator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newSnapshotTestConfig(dir string) *config.Config {
+	return &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: filepath.Join(dir, "state"),
+		BackupIntervalSec:    3600,
+		WALSyncPolicy:        "group",
+		SnapshotRetention:    3,
+	}
+}
+
+func loadSnapshotTestAllocator(t *testing.T, cfg *config.Config) *diskAllocatorImpl {
+	da, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	return da.(*diskAllocatorImpl)
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	cfg := newSnapshotTestConfig(t.TempDir())
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := da.Allocate(1024 * 1024); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if err := da.SaveState(); err != nil {
+			t.Fatalf("SaveState() error = %v", err)
+		}
+	}
+
+	snapshots, err := ListSnapshots(cfg)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 3 || !snapshots[0].Current {
+		t.Fatalf("ListSnapshots() = %+v, want current plus 2 history snapshots", snapshots)
+	}
+	// 历史快照从新到旧排列，WAL 中保留最旧快照之后的全部记录
+	if snapshots[1].WALSeq != 4 || snapshots[2].WALSeq != 3 {
+		t.Errorf("history WAL seqs = %d, %d, want 4, 3", snapshots[1].WALSeq, snapshots[2].WALSeq)
+	}
+	records, _, err := readWAL(walPath(cfg))
+	if err != nil {
+		t.Fatalf("readWAL() error = %v", err)
+	}
+	if len(records) != 2 || records[0].Seq != 4 {
+		t.Errorf("wal records = %+v, want seq 4 and 5", records)
+	}
+}
+
+func TestSnapshotFallbackOnCorruption(t *testing.T) {
+	cfg := newSnapshotTestConfig(t.TempDir())
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	for i := 0; i < 3; i++ {
+		if _, err := da.Allocate(1024 * 1024); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if err := da.SaveState(); err != nil {
+			t.Fatalf("SaveState() error = %v", err)
+		}
+	}
+	if _, err := da.Allocate(1024 * 1024); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	utilization := da.GetDiskUtilization()
+	crash(da)
+
+	raw, err := os.ReadFile(cfg.StatePersistencePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	raw[len(raw)/2] ^= 0xff
+	if err := os.WriteFile(cfg.StatePersistencePath, raw, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	recovered := loadSnapshotTestAllocator(t, cfg)
+	defer recovered.Close()
+	if got := recovered.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after fallback = %v, want %v", got, utilization)
+	}
+	if _, err := os.Stat(cfg.StatePersistencePath + ".corrupt"); err != nil {
+		t.Errorf("corrupt state file was not kept: %v", err)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	cfg := newSnapshotTestConfig(t.TempDir())
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	if _, err := da.Allocate(1024 * 1024); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	restoredUtilization := da.GetDiskUtilization()
+	if _, err := da.Allocate(4 * 1024 * 1024); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	snapshots, err := ListSnapshots(cfg)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("ListSnapshots() = %+v, want current plus 1 history snapshot", snapshots)
+	}
+	if err := RestoreSnapshot(cfg, "missing"); err == nil {
+		t.Errorf("RestoreSnapshot(missing) succeeded")
+	}
+	if err := RestoreSnapshot(cfg, snapshots[1].Name); err != nil {
+		t.Fatalf("RestoreSnapshot() error = %v", err)
+	}
+
+	restored := loadSnapshotTestAllocator(t, cfg)
+	defer restored.Close()
+	if got := restored.GetDiskUtilization(); got != restoredUtilization {
+		t.Errorf("utilization after restore = %v, want %v", got, restoredUtilization)
+	}
+	// 恢复前的当前快照转入历史，可以再恢复回去
+	after, err := ListSnapshots(cfg)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(after) != 3 {
+		t.Errorf("ListSnapshots() after restore = %+v, want 3 snapshots", after)
+	}
+}
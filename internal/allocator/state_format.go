@@ -0,0 +1,513 @@
+package allocator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+// 状态文件格式（整数均为小端序）：
+//
+//	头部  magic "SPWV" | version uint32 | UnitSize | TotalSize | NumShards | SmallBlockLimit（uint64）| crc32c uint32
+//	分区  id uint32 | length uint64 | payload | crc32c(id、length 和 payload) uint32，按 id 依次出现
+//	结尾  id 为 0、长度为 0 的分区，缺失说明文件被截断
+//
+// 增量检查点使用相同的格式，以位图字、空闲树范围和分配表变更三个分区代替完整的位图、空闲树和分配表。
+// 经 Grow/Shrink 在线调整大小的状态，头部记录调整后的 TotalSize，geometry 分区记录调整前配置中的 TotalSize。
+//
+// 头部的 crc32c 覆盖之前的全部头部字节。读取时跳过未知 id 的分区，
+// 同一版本内新增分区不会破坏旧版本的读取。不以 magic 开头的文件按旧的 gob 格式解析
+const (
+	stateMagic         = "SPWV"
+	stateFormatVersion = 1
+	stateHeaderSize    = 4 + 4 + 4*8 + 4
+)
+
+const (
+	sectionEnd uint32 = iota
+	sectionMeta
+	sectionBitmaps
+	sectionTree
+	sectionAllocations
+	sectionSessions
+	sectionTTLs
+	sectionCheckpoint
+	sectionBitmapDelta
+	sectionTreeDelta
+	sectionAllocationDelta
+	sectionGeometry
+	sectionTenants
+)
+
+var sectionNames = map[uint32]string{
+	sectionMeta:        "meta",
+	sectionBitmaps:     "bitmaps",
+	sectionTree:        "tree",
+	sectionAllocations: "allocations",
+	sectionSessions:    "sessions",
+	sectionTTLs:        "ttls",
+
+	sectionCheckpoint:      "checkpoint",
+	sectionBitmapDelta:     "bitmap delta",
+	sectionTreeDelta:       "tree delta",
+	sectionAllocationDelta: "allocation delta",
+	sectionGeometry:        "geometry",
+	sectionTenants:         "tenants",
+}
+
+var (
+	ErrCorruptState       = errors.New("corrupt state file")
+	ErrChecksumMismatch   = errors.New("state file checksum mismatch")
+	ErrUnsupportedVersion = errors.New("unsupported state file version")
+	ErrConfigMismatch     = errors.New("state file was written with a different config")
+)
+
+var stateCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// stateFingerprint 是决定状态文件布局的配置项，加载时必须与当前配置一致
+type stateFingerprint struct {
+	UnitSize        uint64
+	TotalSize       uint64
+	NumShards       uint64
+	SmallBlockLimit uint64
+}
+
+func fingerprintOf(cfg *config.Config) stateFingerprint {
+	return stateFingerprint{
+		UnitSize:        cfg.UnitSize,
+		TotalSize:       cfg.TotalSize,
+		NumShards:       cfg.NumShards,
+		SmallBlockLimit: cfg.SmallBlockLimit,
+	}
+}
+
+// geometryMatches 判断文件布局 fp 是否可以直接按 cfg 加载：与配置一致，
+// 或者是按 cfg 创建后经 Grow/Shrink 从 resizedFrom 调整大小而来
+func geometryMatches(cfg *config.Config, fp stateFingerprint, resizedFrom uint64) bool {
+	want := fingerprintOf(cfg)
+	if fp == want {
+		return true
+	}
+	base := fp
+	base.TotalSize = resizedFrom
+	return resizedFrom == cfg.TotalSize && fp.TotalSize != resizedFrom && base == want
+}
+
+func (fp stateFingerprint) String() string {
+	return fmt.Sprintf("UnitSize=%d TotalSize=%d NumShards=%d SmallBlockLimit=%d",
+		fp.UnitSize, fp.TotalSize, fp.NumShards, fp.SmallBlockLimit)
+}
+
+// encodeState 按上述格式写出状态
+func encodeState(w io.Writer, fp stateFingerprint, data *persistentData) error {
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, 0, stateHeaderSize)
+	header = append(header, stateMagic...)
+	header = binary.LittleEndian.AppendUint32(header, stateFormatVersion)
+	header = binary.LittleEndian.AppendUint64(header, fp.UnitSize)
+	header = binary.LittleEndian.AppendUint64(header, fp.TotalSize)
+	header = binary.LittleEndian.AppendUint64(header, fp.NumShards)
+	header = binary.LittleEndian.AppendUint64(header, fp.SmallBlockLimit)
+	header = binary.LittleEndian.AppendUint32(header, crc32.Checksum(header, stateCRCTable))
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	var meta sectionWriter
+	meta.bool(data.TracksAllocations)
+	meta.u64(data.NextSessionID)
+	meta.u64(data.WALSeq)
+
+	var checkpoint sectionWriter
+	checkpoint.u64(data.CheckpointID)
+	checkpoint.u64(data.DeltaIndex)
+
+	var geometry sectionWriter
+	geometry.u64(data.ResizedFrom)
+
+	var bitmaps sectionWriter
+	bitmaps.u64(uint64(len(data.Bitmaps)))
+	for _, bits := range data.Bitmaps {
+		bitmaps.u64(uint64(len(bits)))
+		for _, word := range bits {
+			bitmaps.u64(word)
+		}
+	}
+
+	var tree sectionWriter
+	tree.u64(uint64(len(data.TreeData)))
+	for _, block := range data.TreeData {
+		tree.u64(block.Start)
+		tree.u64(block.Size)
+	}
+
+	var allocations sectionWriter
+	allocations.u64(uint64(len(data.Allocations)))
+	for _, entry := range data.Allocations {
+		allocations.u64(entry.Start)
+		allocations.u64(entry.Size)
+		allocations.bool(entry.Legacy)
+	}
+
+	var bitmapDelta sectionWriter
+	bitmapDelta.u64(uint64(len(data.BitmapWords)))
+	for _, word := range data.BitmapWords {
+		bitmapDelta.u64(word.Shard)
+		bitmapDelta.u64(word.Index)
+		bitmapDelta.u64(word.Bits)
+	}
+
+	var treeDelta sectionWriter
+	treeDelta.u64(uint64(len(data.TreeRanges)))
+	for _, r := range data.TreeRanges {
+		treeDelta.u64(r.Range.Start)
+		treeDelta.u64(r.Range.Size)
+		treeDelta.u64(uint64(len(r.Free)))
+		for _, block := range r.Free {
+			treeDelta.u64(block.Start)
+			treeDelta.u64(block.Size)
+		}
+	}
+
+	var allocationDelta sectionWriter
+	allocationDelta.u64(uint64(len(data.AllocationUpserts)))
+	for _, entry := range data.AllocationUpserts {
+		allocationDelta.u64(entry.Start)
+		allocationDelta.u64(entry.Size)
+		allocationDelta.bool(entry.Legacy)
+	}
+	allocationDelta.u64(uint64(len(data.AllocationDeletes)))
+	for _, start := range data.AllocationDeletes {
+		allocationDelta.u64(start)
+	}
+
+	var sessions sectionWriter
+	sessions.u64(uint64(len(data.Sessions)))
+	for _, session := range data.Sessions {
+		sessions.u64(session.ID)
+		sessions.u64(uint64(session.TTL))
+		sessions.time(session.ExpiresAt)
+		sessions.u64(uint64(len(session.Extents)))
+		for start, size := range session.Extents {
+			sessions.u64(start)
+			sessions.u64(size)
+		}
+	}
+
+	var ttls sectionWriter
+	ttls.u64(uint64(len(data.TTLs)))
+	for _, entry := range data.TTLs {
+		ttls.u64(entry.Start)
+		ttls.time(entry.ExpiresAt)
+	}
+
+	var tenants sectionWriter
+	tenants.u64(uint64(len(data.TenantQuotas)))
+	for _, q := range data.TenantQuotas {
+		tenants.str(q.Tenant)
+		tenants.u64(q.SoftLimit)
+		tenants.u64(q.HardLimit)
+		tenants.u64(q.Reserved)
+	}
+	tenants.u64(uint64(len(data.TenantOwners)))
+	for _, e := range data.TenantOwners {
+		tenants.u64(e.Start)
+		tenants.str(e.Tenant)
+	}
+
+	type section struct {
+		id      uint32
+		payload []byte
+	}
+	sections := []section{{sectionMeta, meta.buf}}
+	if data.DeltaIndex == 0 {
+		sections = append(sections,
+			section{sectionBitmaps, bitmaps.buf},
+			section{sectionTree, tree.buf},
+			section{sectionAllocations, allocations.buf})
+	}
+	sections = append(sections,
+		section{sectionSessions, sessions.buf},
+		section{sectionTTLs, ttls.buf},
+		section{sectionCheckpoint, checkpoint.buf})
+	if data.DeltaIndex != 0 {
+		sections = append(sections,
+			section{sectionBitmapDelta, bitmapDelta.buf},
+			section{sectionTreeDelta, treeDelta.buf},
+			section{sectionAllocationDelta, allocationDelta.buf})
+	}
+	if data.ResizedFrom != 0 {
+		sections = append(sections, section{sectionGeometry, geometry.buf})
+	}
+	if len(data.TenantQuotas) > 0 || len(data.TenantOwners) > 0 {
+		sections = append(sections, section{sectionTenants, tenants.buf})
+	}
+	sections = append(sections, section{sectionEnd, nil})
+	for _, s := range sections {
+		if err := writeSection(bw, s.id, s.payload); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSection(w io.Writer, id uint32, payload []byte) error {
+	head := binary.LittleEndian.AppendUint32(make([]byte, 0, 12), id)
+	head = binary.LittleEndian.AppendUint64(head, uint64(len(payload)))
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	sum := crc32.Update(crc32.Checksum(head, stateCRCTable), stateCRCTable, payload)
+	_, err := w.Write(binary.LittleEndian.AppendUint32(nil, sum))
+	return err
+}
+
+// decodeState 读取状态文件，返回状态和文件头中的配置指纹。
+// 旧的 gob 格式没有指纹，legacy 为 true 时 fp 无意义
+func decodeState(r io.Reader) (data *persistentData, fp stateFingerprint, legacy bool, err error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(stateMagic))
+	if err != nil || string(magic) != stateMagic {
+		data = &persistentData{}
+		if err := gob.NewDecoder(br).Decode(data); err != nil {
+			return nil, fp, true, fmt.Errorf("%w: %v", ErrCorruptState, err)
+		}
+		return data, fp, true, nil
+	}
+
+	header := make([]byte, stateHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fp, false, fmt.Errorf("%w: truncated header", ErrCorruptState)
+	}
+	body := header[:stateHeaderSize-4]
+	if crc32.Checksum(body, stateCRCTable) != binary.LittleEndian.Uint32(header[stateHeaderSize-4:]) {
+		return nil, fp, false, fmt.Errorf("%w: header", ErrChecksumMismatch)
+	}
+	if version := binary.LittleEndian.Uint32(body[4:]); version != stateFormatVersion {
+		return nil, fp, false, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	fp = stateFingerprint{
+		UnitSize:        binary.LittleEndian.Uint64(body[8:]),
+		TotalSize:       binary.LittleEndian.Uint64(body[16:]),
+		NumShards:       binary.LittleEndian.Uint64(body[24:]),
+		SmallBlockLimit: binary.LittleEndian.Uint64(body[32:]),
+	}
+
+	data = &persistentData{}
+	for {
+		id, payload, err := readSection(br)
+		if err != nil {
+			return nil, fp, false, err
+		}
+		if id == sectionEnd {
+			return data, fp, false, nil
+		}
+		s := &sectionReader{buf: payload}
+		decodeSection(id, s, data)
+		if s.err != nil || len(s.buf) != 0 {
+			return nil, fp, false, fmt.Errorf("%w: malformed %s section", ErrCorruptState, sectionNames[id])
+		}
+	}
+}
+
+func readSection(r io.Reader) (uint32, []byte, error) {
+	head := make([]byte, 12)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return 0, nil, fmt.Errorf("%w: missing end section", ErrCorruptState)
+	}
+	id := binary.LittleEndian.Uint32(head)
+	length := binary.LittleEndian.Uint64(head[4:])
+
+	var payload bytes.Buffer
+	if n, err := io.CopyN(&payload, r, int64(length)); err != nil || uint64(n) != length {
+		return 0, nil, fmt.Errorf("%w: truncated section %d", ErrCorruptState, id)
+	}
+	sum := make([]byte, 4)
+	if _, err := io.ReadFull(r, sum); err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated section %d", ErrCorruptState, id)
+	}
+	if crc32.Update(crc32.Checksum(head, stateCRCTable), stateCRCTable, payload.Bytes()) != binary.LittleEndian.Uint32(sum) {
+		return 0, nil, fmt.Errorf("%w: section %d", ErrChecksumMismatch, id)
+	}
+	return id, payload.Bytes(), nil
+}
+
+func decodeSection(id uint32, s *sectionReader, data *persistentData) {
+	switch id {
+	case sectionMeta:
+		data.TracksAllocations = s.bool()
+		data.NextSessionID = s.u64()
+		data.WALSeq = s.u64()
+	case sectionBitmaps:
+		data.Bitmaps = make([][]uint64, s.count(8))
+		for i := range data.Bitmaps {
+			data.Bitmaps[i] = make([]uint64, s.count(8))
+			for j := range data.Bitmaps[i] {
+				data.Bitmaps[i][j] = s.u64()
+			}
+		}
+	case sectionTree:
+		data.TreeData = make([]BTreeBlock, s.count(16))
+		for i := range data.TreeData {
+			data.TreeData[i] = BTreeBlock{Start: s.u64(), Size: s.u64()}
+		}
+	case sectionAllocations:
+		data.Allocations = make([]AllocationEntry, s.count(17))
+		for i := range data.Allocations {
+			data.Allocations[i] = AllocationEntry{Start: s.u64(), Size: s.u64(), Legacy: s.bool()}
+		}
+	case sectionSessions:
+		data.Sessions = make([]SessionState, s.count(32))
+		for i := range data.Sessions {
+			session := SessionState{ID: s.u64(), TTL: time.Duration(s.u64()), ExpiresAt: s.time()}
+			session.Extents = make(map[uint64]uint64)
+			for n := s.count(16); n > 0; n-- {
+				start := s.u64()
+				session.Extents[start] = s.u64()
+			}
+			data.Sessions[i] = session
+		}
+	case sectionTTLs:
+		data.TTLs = make([]TTLEntry, s.count(16))
+		for i := range data.TTLs {
+			data.TTLs[i] = TTLEntry{Start: s.u64(), ExpiresAt: s.time()}
+		}
+	case sectionCheckpoint:
+		data.CheckpointID = s.u64()
+		data.DeltaIndex = s.u64()
+	case sectionBitmapDelta:
+		data.BitmapWords = make([]bitmapWord, s.count(24))
+		for i := range data.BitmapWords {
+			data.BitmapWords[i] = bitmapWord{Shard: s.u64(), Index: s.u64(), Bits: s.u64()}
+		}
+	case sectionTreeDelta:
+		data.TreeRanges = make([]treeRange, s.count(24))
+		for i := range data.TreeRanges {
+			r := treeRange{Range: BTreeBlock{Start: s.u64(), Size: s.u64()}}
+			r.Free = make([]BTreeBlock, s.count(16))
+			for j := range r.Free {
+				r.Free[j] = BTreeBlock{Start: s.u64(), Size: s.u64()}
+			}
+			data.TreeRanges[i] = r
+		}
+	case sectionAllocationDelta:
+		data.AllocationUpserts = make([]AllocationEntry, s.count(17))
+		for i := range data.AllocationUpserts {
+			data.AllocationUpserts[i] = AllocationEntry{Start: s.u64(), Size: s.u64(), Legacy: s.bool()}
+		}
+		data.AllocationDeletes = make([]uint64, s.count(8))
+		for i := range data.AllocationDeletes {
+			data.AllocationDeletes[i] = s.u64()
+		}
+	case sectionGeometry:
+		data.ResizedFrom = s.u64()
+	case sectionTenants:
+		data.TenantQuotas = make([]TenantQuota, s.count(32))
+		for i := range data.TenantQuotas {
+			data.TenantQuotas[i] = TenantQuota{Tenant: s.str(), SoftLimit: s.u64(), HardLimit: s.u64(), Reserved: s.u64()}
+		}
+		data.TenantOwners = make([]TenantEntry, s.count(16))
+		for i := range data.TenantOwners {
+			data.TenantOwners[i] = TenantEntry{Start: s.u64(), Tenant: s.str()}
+		}
+	default:
+		// 未知分区来自更新的写入方，跳过
+		s.buf = nil
+	}
+}
+
+type sectionWriter struct {
+	buf []byte
+}
+
+func (s *sectionWriter) u64(v uint64) {
+	s.buf = binary.LittleEndian.AppendUint64(s.buf, v)
+}
+
+func (s *sectionWriter) bool(v bool) {
+	if v {
+		s.buf = append(s.buf, 1)
+	} else {
+		s.buf = append(s.buf, 0)
+	}
+}
+
+// time 以 UnixNano 写入时间，零值写为 0
+func (s *sectionWriter) time(t time.Time) {
+	if t.IsZero() {
+		s.u64(0)
+		return
+	}
+	s.u64(uint64(t.UnixNano()))
+}
+
+// str 以长度加内容写入字符串
+func (s *sectionWriter) str(v string) {
+	s.u64(uint64(len(v)))
+	s.buf = append(s.buf, v...)
+}
+
+// sectionReader 按顺序读取分区内容，越界时记录错误并返回零值
+type sectionReader struct {
+	buf []byte
+	err error
+}
+
+func (s *sectionReader) u64() uint64 {
+	if len(s.buf) < 8 {
+		s.err = ErrCorruptState
+		s.buf = nil
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(s.buf)
+	s.buf = s.buf[8:]
+	return v
+}
+
+func (s *sectionReader) bool() bool {
+	if len(s.buf) < 1 {
+		s.err = ErrCorruptState
+		return false
+	}
+	v := s.buf[0] != 0
+	s.buf = s.buf[1:]
+	return v
+}
+
+func (s *sectionReader) time() time.Time {
+	v := s.u64()
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(v))
+}
+
+func (s *sectionReader) str() string {
+	n := s.count(1)
+	v := string(s.buf[:n])
+	s.buf = s.buf[n:]
+	return v
+}
+
+// count 读取元素个数，每个元素至少占 minSize 字节，超出剩余长度时视为损坏，避免按错误的长度分配内存
+func (s *sectionReader) count(minSize uint64) uint64 {
+	n := s.u64()
+	if n > uint64(len(s.buf))/minSize {
+		s.err = ErrCorruptState
+		s.buf = nil
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,121 @@
+package allocator
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func testStateData() *persistentData {
+	return &persistentData{
+		Bitmaps:           [][]uint64{{0xf, 0}, {0, 1 << 63}},
+		TreeData:          []BTreeBlock{{Start: 10, Size: 20}, {Start: 40, Size: 5}},
+		TracksAllocations: true,
+		Allocations:       []AllocationEntry{{Start: 0, Size: 4}, {Start: 30, Size: 10, Legacy: true}},
+		NextSessionID:     7,
+		Sessions: []SessionState{
+			{ID: 6, TTL: time.Minute, ExpiresAt: time.Unix(1700000000, 42), Extents: map[uint64]uint64{0: 4}},
+		},
+		TTLs:   []TTLEntry{{Start: 30, ExpiresAt: time.Unix(1700000100, 0)}},
+		WALSeq: 99,
+	}
+}
+
+func TestStateFormatRoundTrip(t *testing.T) {
+	fp := stateFingerprint{UnitSize: 4096, TotalSize: 1 << 30, NumShards: 2, SmallBlockLimit: 128}
+	var buf bytes.Buffer
+	if err := encodeState(&buf, fp, testStateData()); err != nil {
+		t.Fatalf("encodeState() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte(stateMagic)) {
+		t.Fatalf("encoded state does not start with magic")
+	}
+
+	data, gotFP, legacy, err := decodeState(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("decodeState() error = %v", err)
+	}
+	if legacy {
+		t.Errorf("decodeState() legacy = true, want false")
+	}
+	if gotFP != fp {
+		t.Errorf("fingerprint = %v, want %v", gotFP, fp)
+	}
+	if want := testStateData(); !reflect.DeepEqual(data, want) {
+		t.Errorf("decoded state = %+v, want %+v", data, want)
+	}
+}
+
+func TestStateFormatDetectsCorruption(t *testing.T) {
+	fp := stateFingerprint{UnitSize: 4096, TotalSize: 1 << 30, NumShards: 2, SmallBlockLimit: 128}
+	var buf bytes.Buffer
+	if err := encodeState(&buf, fp, testStateData()); err != nil {
+		t.Fatalf("encodeState() error = %v", err)
+	}
+	encoded := buf.Bytes()
+
+	// 逐字节翻转一位，任何位置的损坏都必须被发现
+	for i := len(stateMagic); i < len(encoded); i++ {
+		corrupted := bytes.Clone(encoded)
+		corrupted[i] ^= 0x10
+		if _, _, _, err := decodeState(bytes.NewReader(corrupted)); err == nil {
+			t.Fatalf("decodeState() with bit flip at byte %d succeeded", i)
+		}
+	}
+
+	for _, n := range []int{stateHeaderSize - 1, stateHeaderSize + 5, len(encoded) - 1} {
+		_, _, _, err := decodeState(bytes.NewReader(encoded[:n]))
+		if !errors.Is(err, ErrCorruptState) {
+			t.Errorf("decodeState() truncated to %d bytes error = %v, want %v", n, err, ErrCorruptState)
+		}
+	}
+
+	corrupted := bytes.Clone(encoded)
+	corrupted[stateHeaderSize+20] ^= 0x01
+	if _, _, _, err := decodeState(bytes.NewReader(corrupted)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("decodeState() with corrupted section error = %v, want %v", err, ErrChecksumMismatch)
+	}
+}
+
+func TestLoadStateConfigMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	cfg := &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: path,
+		BackupIntervalSec:    5,
+	}
+	da, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if _, err := da.Allocate(1024 * 1024); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	other := *cfg
+	other.UnitSize = 8192
+	if _, err := LoadState(&other); !errors.Is(err, ErrConfigMismatch) {
+		t.Errorf("LoadState() with different unit size error = %v, want %v", err, ErrConfigMismatch)
+	}
+
+	// 旧 gob 格式的文件以不同的首字节开始，不能被误认成新格式
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.HasPrefix(raw, []byte(stateMagic)) {
+		t.Errorf("state file does not start with magic")
+	}
+}
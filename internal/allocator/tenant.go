@@ -0,0 +1,445 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQuotaExceeded 表示分配后租户的用量会超过硬配额
+var ErrQuotaExceeded = errors.New("tenant quota exceeded")
+
+// TenantQuota 是租户的字节配额，0 表示不限。超过软配额只提示，硬配额拒绝分配。
+// Reserved 是为租户预留的字节数：不指定地址，租户自身的分配先用掉预留，其余分配不能占用剩余的预留
+type TenantQuota struct {
+	Tenant    string
+	SoftLimit uint64
+	HardLimit uint64
+	Reserved  uint64
+}
+
+// ReservationStats 是预留空间的统计，单位为字节
+type ReservationStats struct {
+	Reserved    uint64 // 全部租户的预留之和
+	Outstanding uint64 // 尚未被租户自身的分配用掉的预留
+	Unreserved  uint64 // 扣除剩余预留后一般分配可用的空闲空间
+}
+
+// TenantUsage 是租户的配额和已用字节数，已用字节数按单元向上取整
+type TenantUsage struct {
+	TenantQuota
+	Used uint64
+}
+
+// TenantEntry 记录一段分配所属的租户，Start 以单元为单位
+type TenantEntry struct {
+	Start  uint64
+	Tenant string
+}
+
+// tenantTable 记录分配所属的租户、各租户的用量和配额。
+// 配额只随检查点保存，归属的变更同时写入 WAL，重启时用量按分配表和归属重新计算
+type tenantTable struct {
+	mu     sync.Mutex
+	owners map[uint64]string
+	used   map[string]uint64 // 包括已预占、尚未完成分配的字节数
+	quotas map[string]TenantQuota
+
+	// reserved 是全部租户的预留之和，为 0 时分配不做预留检查，原子读取；
+	// inflight 是已通过预留检查、尚未完成的分配字节数
+	reserved uint64
+	inflight uint64
+}
+
+func newTenantTable() *tenantTable {
+	return &tenantTable{
+		owners: make(map[uint64]string),
+		used:   make(map[string]uint64),
+		quotas: make(map[string]TenantQuota),
+	}
+}
+
+// chargeLocked 将 bytes 字节计入租户的用量，超过硬配额时返回 ErrQuotaExceeded 且不做修改，调用方需持有锁
+func (t *tenantTable) chargeLocked(tenant string, bytes uint64) error {
+	used := t.used[tenant] + bytes
+	if q := t.quotas[tenant]; q.HardLimit > 0 && used > q.HardLimit {
+		return ErrQuotaExceeded
+	}
+	t.used[tenant] = used
+	return nil
+}
+
+// overSoft 判断租户的用量是否超过了软配额
+func (t *tenantTable) overSoft(tenant string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q := t.quotas[tenant]
+	return q.SoftLimit > 0 && t.used[tenant] > q.SoftLimit
+}
+
+func (t *tenantTable) uncharge(tenant string, bytes uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sub(tenant, bytes)
+}
+
+// sub 扣减用量，调用方需持有锁
+func (t *tenantTable) sub(tenant string, bytes uint64) {
+	if used := t.used[tenant]; used > bytes {
+		t.used[tenant] = used - bytes
+	} else {
+		delete(t.used, tenant)
+	}
+}
+
+func (t *tenantTable) assign(start uint64, tenant string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.owners[start] = tenant
+}
+
+// forget 在释放起点为 start 的分配时扣减其租户的用量
+func (t *tenantTable) forget(start, bytes uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tenant, ok := t.owners[start]; ok {
+		delete(t.owners, start)
+		t.sub(tenant, bytes)
+	}
+}
+
+// resize 在原地调整分配大小后按差值更新其租户的用量
+func (t *tenantTable) resize(start, oldBytes, newBytes uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tenant, ok := t.owners[start]
+	if !ok {
+		return
+	}
+	t.sub(tenant, oldBytes)
+	t.used[tenant] += newBytes
+}
+
+// setQuota 设置租户的配额和预留，全部为 0 时删除。增加预留后空闲空间不足以保证全部剩余预留时返回 ErrNoSpaceLeft
+func (t *tenantTable) setQuota(q TenantQuota, free uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	old := t.quotas[q.Tenant]
+	if q.SoftLimit == 0 && q.HardLimit == 0 && q.Reserved == 0 {
+		delete(t.quotas, q.Tenant)
+	} else {
+		t.quotas[q.Tenant] = q
+	}
+	if q.Reserved > old.Reserved && t.outstandingLocked() > free-min(free, t.inflight) {
+		if old == (TenantQuota{}) {
+			delete(t.quotas, q.Tenant)
+		} else {
+			t.quotas[q.Tenant] = old
+		}
+		return fmt.Errorf("%w: cannot reserve %d bytes for tenant %s", ErrNoSpaceLeft, q.Reserved, q.Tenant)
+	}
+	t.updateReservedLocked()
+	return nil
+}
+
+// updateReservedLocked 重新计算预留之和，调用方需持有锁
+func (t *tenantTable) updateReservedLocked() {
+	var reserved uint64
+	for _, q := range t.quotas {
+		reserved += q.Reserved
+	}
+	atomic.StoreUint64(&t.reserved, reserved)
+}
+
+// outstandingLocked 返回尚未被租户自身的分配用掉的预留之和，调用方需持有锁
+func (t *tenantTable) outstandingLocked() uint64 {
+	var outstanding uint64
+	for name, q := range t.quotas {
+		if used := t.used[name]; q.Reserved > used {
+			outstanding += q.Reserved - used
+		}
+	}
+	return outstanding
+}
+
+// admit 在分配 bytes 字节之前检查配额和预留。tenant 不为空时先按硬配额将这些字节计入其用量，相应地减少了它的剩余预留；
+// 再检查分配后剩余的空闲空间是否仍足以保证全部剩余预留，通过时这些字节在 release 之前计为在途。
+// 两项检查在同一次加锁中完成，其他分配不会在计入用量之后、计为在途之前占用租户的预留；任一项失败时不做修改。
+// 没有预留时不调用 freeBytes，既没有预留也没有租户时不加锁
+func (t *tenantTable) admit(tenant string, bytes uint64, freeBytes func() uint64) (release func(), err error) {
+	reserved := atomic.LoadUint64(&t.reserved) != 0
+	if !reserved && tenant == "" {
+		return func() {}, nil
+	}
+	var free uint64
+	if reserved {
+		free = freeBytes()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tenant != "" {
+		if err := t.chargeLocked(tenant, bytes); err != nil {
+			return nil, err
+		}
+	}
+	if !reserved {
+		return func() {}, nil
+	}
+	if free-min(free, t.inflight) < bytes+t.outstandingLocked() {
+		if tenant != "" {
+			t.sub(tenant, bytes)
+		}
+		return nil, fmt.Errorf("%w: remaining free space is reserved", ErrNoSpaceLeft)
+	}
+	t.inflight += bytes
+	return func() {
+		t.mu.Lock()
+		t.inflight -= bytes
+		t.mu.Unlock()
+	}, nil
+}
+
+func (t *tenantTable) reservationStats(free uint64) ReservationStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	outstanding := t.outstandingLocked()
+	return ReservationStats{
+		Reserved:    atomic.LoadUint64(&t.reserved),
+		Outstanding: outstanding,
+		Unreserved:  free - min(free, outstanding),
+	}
+}
+
+// usage 返回 tenant 的用量，tenant 为空时返回全部有配额或有用量的租户，按名称排序
+func (t *tenantTable) usage(tenant string) []TenantUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tenant != "" {
+		q := t.quotas[tenant]
+		q.Tenant = tenant
+		return []TenantUsage{{TenantQuota: q, Used: t.used[tenant]}}
+	}
+	var res []TenantUsage
+	for name, q := range t.quotas {
+		res = append(res, TenantUsage{TenantQuota: q, Used: t.used[name]})
+	}
+	for name, used := range t.used {
+		if _, ok := t.quotas[name]; !ok {
+			res = append(res, TenantUsage{TenantQuota: TenantQuota{Tenant: name}, Used: used})
+		}
+	}
+	slices.SortFunc(res, func(a, b TenantUsage) int {
+		return strings.Compare(a.Tenant, b.Tenant)
+	})
+	return res
+}
+
+func (t *tenantTable) snapshot() ([]TenantQuota, []TenantEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	quotas := make([]TenantQuota, 0, len(t.quotas))
+	for _, q := range t.quotas {
+		quotas = append(quotas, q)
+	}
+	owners := make([]TenantEntry, 0, len(t.owners))
+	for start, tenant := range t.owners {
+		owners = append(owners, TenantEntry{Start: start, Tenant: tenant})
+	}
+	return quotas, owners
+}
+
+// load 用检查点中的配额和归属替换当前内容，用量在 WAL 重放后由 reconcile 重新计算
+func (t *tenantTable) load(quotas []TenantQuota, owners []TenantEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.quotas)
+	for _, q := range quotas {
+		t.quotas[q.Tenant] = q
+	}
+	t.updateReservedLocked()
+	clear(t.owners)
+	for _, e := range owners {
+		t.owners[e.Start] = e.Tenant
+	}
+}
+
+// reconcile 丢弃分配已不存在的归属记录，并按分配表重新计算各租户的用量
+func (t *tenantTable) reconcile(allocations *allocationTable, unitSize uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.used)
+	for start, tenant := range t.owners {
+		entry, ok := allocations.lookup(start)
+		if !ok {
+			delete(t.owners, start)
+			continue
+		}
+		t.used[tenant] += entry.Size * unitSize
+	}
+}
+
+// assignTenant 将起点为 address 的分配记为 tenant 所有，释放时从其用量中扣除。
+// 写 WAL 失败时只记录日志：之后的分配和释放同样会失败，归属在内存中仍然有效
+func (da *diskAllocatorImpl) assignTenant(tenant string, address uint64) {
+	start := address / da.cfg.UnitSize
+	da.walMu.RLock()
+	da.tenants.assign(start, tenant)
+	err := da.wal.append(walRecord{Op: walOpTenant, Start: start, Tenant: tenant})
+	da.walMu.RUnlock()
+	if err != nil {
+		log.Printf("failed to log tenant %s of allocation %d: %v", tenant, address, err)
+	}
+	da.incrementOperationCount()
+}
+
+// SetTenantQuota 设置租户的配额和预留，全部为 0 时删除。空闲空间不足以保证增加后的预留时返回 ErrNoSpaceLeft。
+// 配额随状态文件持久化，启用持久化时立即保存
+func (da *diskAllocatorImpl) SetTenantQuota(q TenantQuota) error {
+	if err := da.tenants.setQuota(q, da.freeBytes()); err != nil {
+		return err
+	}
+	if da.cfg.StatePersistencePath == "" {
+		return nil
+	}
+	return da.SaveState()
+}
+
+// GetTenantUsage 返回 tenant 的配额和用量，tenant 为空时返回全部有配额或有用量的租户
+func (da *diskAllocatorImpl) GetTenantUsage(tenant string) []TenantUsage {
+	return da.tenants.usage(tenant)
+}
+
+// GetReservationStats 返回预留空间的统计
+func (da *diskAllocatorImpl) GetReservationStats() ReservationStats {
+	return da.tenants.reservationStats(da.freeBytes())
+}
+
+// admission 是一次已通过配额和预留检查、尚未完成的分配
+type admission struct {
+	da      *diskAllocatorImpl
+	tenant  string
+	bytes   uint64
+	release func()
+}
+
+// admit 检查分配 units 个单元是否超过 tenant 的硬配额、分配后是否仍能保证全部租户的剩余预留，tenant 为空时不计配额。
+// 通过时这些单元已计入 tenant 的用量，分配完成（无论成败）后调用 finish
+func (da *diskAllocatorImpl) admit(tenant string, units uint64) (*admission, error) {
+	bytes := units * da.cfg.UnitSize
+	release, err := da.tenants.admit(tenant, bytes, da.freeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &admission{da: da, tenant: tenant, bytes: bytes, release: release}, nil
+}
+
+// finish 结束在途计数。分配失败时归还计入的用量，成功时将起点为 addresses 的分配记为租户所有
+func (a *admission) finish(err error, addresses ...uint64) {
+	a.release()
+	if a.tenant == "" {
+		return
+	}
+	if err != nil {
+		a.da.tenants.uncharge(a.tenant, a.bytes)
+		return
+	}
+	for _, address := range addresses {
+		a.da.assignTenant(a.tenant, address)
+	}
+}
+
+// refund 结束在途计数并归还计入的用量。原地扩大后分配器已按差值更新了分配所属租户的用量，无论成败都归还
+func (a *admission) refund() {
+	a.release()
+	if a.tenant != "" {
+		a.da.tenants.uncharge(a.tenant, a.bytes)
+	}
+}
+
+// freeBytes 返回位图区和 B 树区可以分配的空闲字节数，只读取两者的空闲计数。
+// 收缩中被隔离的空闲块收缩完成后即被移除，不能用来保证预留，不计入
+func (da *diskAllocatorImpl) freeBytes() uint64 {
+	return (da.bitmaps.GetAvailableSpace() + da.tree.GetAvailableSpace()) * da.cfg.UnitSize
+}
+
+func (da *diskAllocatorImpl) roundUp(size uint64) uint64 {
+	return (size + da.cfg.UnitSize - 1) / da.cfg.UnitSize * da.cfg.UnitSize
+}
+
+// TenantAllocator 以租户的名义分配空间：检查硬配额、计入用量和检查预留在同一次加锁中完成，
+// 分配成功后记录归属，失败时归还用量。其余方法与所属的分配器相同
+type TenantAllocator struct {
+	*diskAllocatorImpl
+	tenant   string
+	overSoft uint32
+}
+
+// ForTenant 返回以 tenant 的名义分配的视图，tenant 为空时不计配额。视图只应在一次请求内使用
+func (da *diskAllocatorImpl) ForTenant(tenant string) *TenantAllocator {
+	return &TenantAllocator{diskAllocatorImpl: da, tenant: tenant}
+}
+
+// OverSoftQuota 判断经由该视图完成的分配是否使租户的用量超过了软配额
+func (ta *TenantAllocator) OverSoftQuota() bool {
+	return atomic.LoadUint32(&ta.overSoft) != 0
+}
+
+// note 在分配成功后检查软配额
+func (ta *TenantAllocator) note(err error) {
+	if err == nil && ta.tenant != "" && ta.tenants.overSoft(ta.tenant) {
+		atomic.StoreUint32(&ta.overSoft, 1)
+	}
+}
+
+func (ta *TenantAllocator) Allocate(size uint64) (uint64, error) {
+	return ta.AllocateWithPolicy(size, PolicyDefault)
+}
+
+func (ta *TenantAllocator) AllocateWithPolicy(size uint64, policy PlacementPolicy) (uint64, error) {
+	address, err := ta.allocateWithPolicy(ta.tenant, size, policy)
+	ta.note(err)
+	return address, err
+}
+
+func (ta *TenantAllocator) AllocateAligned(size uint64, alignment uint64) (uint64, error) {
+	address, err := ta.allocateAligned(ta.tenant, size, alignment)
+	ta.note(err)
+	return address, err
+}
+
+func (ta *TenantAllocator) AllocateWithTTL(size uint64, ttl time.Duration) (uint64, error) {
+	address, err := ta.allocateWithTTL(ta.tenant, size, ttl)
+	ta.note(err)
+	return address, err
+}
+
+func (ta *TenantAllocator) AllocateInSession(sessionID uint64, size uint64) (uint64, error) {
+	address, err := ta.allocateInSession(ta.tenant, sessionID, size)
+	ta.note(err)
+	return address, err
+}
+
+func (ta *TenantAllocator) AllocateExtents(size uint64, maxExtents int, minExtentSize uint64) ([]Extent, error) {
+	extents, err := ta.allocateExtents(ta.tenant, size, maxExtents, minExtentSize)
+	ta.note(err)
+	return extents, err
+}
+
+func (ta *TenantAllocator) BatchAllocate(sizes []uint64) []AllocateResult {
+	results := ta.batchAllocate(ta.tenant, sizes)
+	for _, r := range results {
+		ta.note(r.Err)
+	}
+	return results
+}
+
+func (ta *TenantAllocator) Resize(address uint64, oldSize uint64, newSize uint64) (uint64, bool, error) {
+	newAddress, moved, err := ta.resize(ta.tenant, address, oldSize, newSize)
+	ta.note(err)
+	return newAddress, moved, err
+}
@@ -0,0 +1,247 @@
+package allocator
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func checkTenantUsed(t *testing.T, da *diskAllocatorImpl, tenant string, want uint64) {
+	t.Helper()
+	if got := da.GetTenantUsage(tenant)[0].Used; got != want {
+		t.Errorf("usage of %s = %d, want %d", tenant, got, want)
+	}
+}
+
+func TestTenantQuota(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	cfg.StatePersistencePath = ""
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	if err := da.SetTenantQuota(TenantQuota{Tenant: "a", SoftLimit: 8 * 4096, HardLimit: 16 * 4096}); err != nil {
+		t.Fatalf("SetTenantQuota() error = %v", err)
+	}
+	first, err := da.ForTenant("a").Allocate(8*4096 - 100)
+	if err != nil {
+		t.Fatalf("Allocate() within quota error = %v", err)
+	}
+	checkTenantUsed(t, da, "a", 8*4096)
+
+	view := da.ForTenant("a")
+	soft, err := view.Allocate(4096)
+	if err != nil || !view.OverSoftQuota() {
+		t.Errorf("Allocate() above soft limit = %v, over soft %v, want over soft", err, view.OverSoftQuota())
+	}
+	if err := da.Free(soft, 4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if _, err := da.ForTenant("a").Allocate(9 * 4096); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Allocate() above hard limit error = %v, want %v", err, ErrQuotaExceeded)
+	}
+	checkTenantUsed(t, da, "a", 8*4096)
+	if _, err := da.ForTenant("b").Allocate(64 * 4096); err != nil {
+		t.Errorf("Allocate() for tenant without quota error = %v", err)
+	}
+
+	// 原地调整大小按差值更新用量，释放后扣除
+	if _, moved, err := da.Resize(first, 8*4096, 4*4096); err != nil || moved {
+		t.Fatalf("Resize() = %v, %v", moved, err)
+	}
+	checkTenantUsed(t, da, "a", 4*4096)
+	if err := da.Free(first, 4*4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	checkTenantUsed(t, da, "a", 0)
+
+	want := []TenantUsage{
+		{TenantQuota: TenantQuota{Tenant: "a", SoftLimit: 8 * 4096, HardLimit: 16 * 4096}},
+		{TenantQuota: TenantQuota{Tenant: "b"}, Used: 64 * 4096},
+	}
+	if got := da.GetTenantUsage(""); !slices.Equal(got, want) {
+		t.Errorf("GetTenantUsage() = %v, want %v", got, want)
+	}
+}
+
+func TestTenantUsagePersists(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+	if err := da.SetTenantQuota(TenantQuota{Tenant: "a", HardLimit: 1024 * 1024}); err != nil {
+		t.Fatalf("SetTenantQuota() error = %v", err)
+	}
+	kept, err := da.ForTenant("a").Allocate(64 * 4096)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	freed, err := da.ForTenant("a").Allocate(16 * 4096)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	// 增量检查点同样记录归属
+	if _, err := da.ForTenant("b").Allocate(8 * 4096); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	// 只在 WAL 中的释放重放后从用量中扣除，只在 WAL 中的分配重放后计入用量
+	if err := da.Free(freed, 16*4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	walOnly, err := da.ForTenant("b").Allocate(4 * 4096)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	crash(da)
+
+	da = loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	checkTenantUsed(t, da, "a", 64*4096)
+	checkTenantUsed(t, da, "b", 12*4096)
+	if err := da.Free(walOnly, 4*4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	checkTenantUsed(t, da, "b", 8*4096)
+	if got := da.GetTenantUsage("a")[0].HardLimit; got != 1024*1024 {
+		t.Errorf("hard limit after restart = %d, want %d", got, 1024*1024)
+	}
+	if err := da.Free(kept, 64*4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	checkTenantUsed(t, da, "a", 0)
+}
+
+func TestTenantReservation(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	da := loadSnapshotTestAllocator(t, cfg)
+
+	free := da.freeBytes()
+	reserved := free - 1024*1024
+	if err := da.SetTenantQuota(TenantQuota{Tenant: "a", Reserved: reserved}); err != nil {
+		t.Fatalf("SetTenantQuota() error = %v", err)
+	}
+	if err := da.SetTenantQuota(TenantQuota{Tenant: "b", Reserved: 2 * 1024 * 1024}); !errors.Is(err, ErrNoSpaceLeft) {
+		t.Errorf("SetTenantQuota() beyond free space error = %v, want %v", err, ErrNoSpaceLeft)
+	}
+	want := ReservationStats{Reserved: reserved, Outstanding: reserved, Unreserved: 1024 * 1024}
+	if got := da.GetReservationStats(); got != want {
+		t.Errorf("GetReservationStats() = %+v, want %+v", got, want)
+	}
+
+	// 一般分配只能使用未预留的空间
+	if _, err := da.Allocate(2 * 1024 * 1024); !errors.Is(err, ErrNoSpaceLeft) {
+		t.Errorf("Allocate() into reserved space error = %v, want %v", err, ErrNoSpaceLeft)
+	}
+	if _, err := da.ForTenant("b").Allocate(2 * 1024 * 1024); !errors.Is(err, ErrNoSpaceLeft) {
+		t.Errorf("Allocate() for another tenant error = %v, want %v", err, ErrNoSpaceLeft)
+	}
+	checkTenantUsed(t, da, "b", 0)
+	general, err := da.Allocate(512 * 1024)
+	if err != nil {
+		t.Fatalf("Allocate() in unreserved space error = %v", err)
+	}
+	results := da.BatchAllocate([]uint64{256 * 1024, 512 * 1024})
+	if results[0].Err != nil || !errors.Is(results[1].Err, ErrNoSpaceLeft) {
+		t.Fatalf("BatchAllocate() errors = %v, %v, want nil, %v", results[0].Err, results[1].Err, ErrNoSpaceLeft)
+	}
+	if err := da.Free(results[0].Address, 256*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+
+	// 租户自身的分配用掉预留
+	if _, err := da.ForTenant("a").Allocate(4 * 1024 * 1024); err != nil {
+		t.Fatalf("Allocate() within reservation error = %v", err)
+	}
+	want = ReservationStats{Reserved: reserved, Outstanding: reserved - 4*1024*1024, Unreserved: 512 * 1024}
+	if got := da.GetReservationStats(); got != want {
+		t.Errorf("GetReservationStats() after tenant allocation = %+v, want %+v", got, want)
+	}
+	if err := da.Free(general, 512*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 预留随状态文件保存
+	da = loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+	if got := da.GetTenantUsage("a")[0].Reserved; got != reserved {
+		t.Errorf("reserved after restart = %d, want %d", got, reserved)
+	}
+	if _, err := da.Allocate(2 * 1024 * 1024); !errors.Is(err, ErrNoSpaceLeft) {
+		t.Errorf("Allocate() into reserved space after restart error = %v, want %v", err, ErrNoSpaceLeft)
+	}
+	if err := da.SetTenantQuota(TenantQuota{Tenant: "a"}); err != nil {
+		t.Fatalf("SetTenantQuota() removing reservation error = %v", err)
+	}
+	if _, err := da.Allocate(2 * 1024 * 1024); err != nil {
+		t.Errorf("Allocate() after removing reservation error = %v", err)
+	}
+}
+
+// 租户计入用量与检查预留在同一次加锁中完成，并发的一般分配不能在两者之间占用租户的预留
+func TestTenantReservationUnderConcurrentAllocations(t *testing.T) {
+	cfg := newGrowTestConfig(t)
+	cfg.StatePersistencePath = ""
+	da := loadSnapshotTestAllocator(t, cfg)
+	defer da.Close()
+
+	const reserved = 256 * 4096
+	if err := da.SetTenantQuota(TenantQuota{Tenant: "a", Reserved: reserved}); err != nil {
+		t.Fatalf("SetTenantQuota() error = %v", err)
+	}
+	unreserved := da.freeBytes() - reserved
+	var filled uint64
+	for {
+		if _, err := da.Allocate(4096); err != nil {
+			break
+		}
+		filled += 4096
+	}
+	if filled != unreserved {
+		t.Fatalf("general allocations took %d bytes, want %d", filled, unreserved)
+	}
+
+	// 剩余空间都已预留，一般分配继续重试，直到租户的分配全部完成
+	var wg sync.WaitGroup
+	var general [4]uint64
+	done := make(chan struct{})
+	for i := range general {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := da.Allocate(4096); err == nil {
+					general[i] += 4096
+				}
+			}
+		}(i)
+	}
+	view := da.ForTenant("a")
+	for i := 0; i < reserved/4096; i++ {
+		if _, err := view.Allocate(4096); err != nil {
+			close(done)
+			wg.Wait()
+			t.Fatalf("Allocate() %d within reservation error = %v", i, err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	for i, n := range general {
+		if n != 0 {
+			t.Errorf("general allocator %d took %d reserved bytes", i, n)
+		}
+	}
+	checkTenantUsed(t, da, "a", reserved)
+}
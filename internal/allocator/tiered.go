@@ -0,0 +1,71 @@
+package allocator
+
+import (
+	"errors"
+)
+
+// Tier 是分层分配中的一层。HighWatermark 是该层的利用率水位线，分配后利用率超过水位线时溢出到下一层，
+// 不大于 0 或不小于 1 时表示不设水位线，直到空间不足才溢出
+type Tier struct {
+	Name          string
+	Allocator     DiskAllocator
+	HighWatermark float64
+}
+
+// TieredAllocator 按优先级在多个分配器之间分配空间，例如优先使用 NVMe，高于水位线后溢出到 HDD。
+// 各层的地址空间互相独立，分配结果需连同层名一起保存，释放时交给对应层的分配器
+type TieredAllocator struct {
+	tiers []Tier
+}
+
+// NewTieredAllocator 以 tiers 的顺序为优先级创建分层分配器，第一个优先级最高
+func NewTieredAllocator(tiers []Tier) *TieredAllocator {
+	return &TieredAllocator{tiers: tiers}
+}
+
+// Allocate 按优先级分配 size 字节，返回所在层的名称和该层中的地址
+func (ta *TieredAllocator) Allocate(size uint64) (tier string, address uint64, err error) {
+	return ta.AllocateWith(size, func(a DiskAllocator) (uint64, error) {
+		return a.Allocate(size)
+	})
+}
+
+// AllocateWith 与 Allocate 相同，在选中的层上调用 alloc 完成分配，用于指定放置策略、对齐或 TTL。
+// 先跳过分配后会超过水位线的层；所有层都超过水位线或空间不足时，忽略水位线按优先级再试一次被跳过的层。
+// 只有空间不足时才尝试下一层，其他错误直接返回
+func (ta *TieredAllocator) AllocateWith(size uint64, alloc func(DiskAllocator) (uint64, error)) (tier string, address uint64, err error) {
+	var skipped []Tier
+	for _, t := range ta.tiers {
+		if t.aboveWatermark(size) {
+			skipped = append(skipped, t)
+			continue
+		}
+		address, err := alloc(t.Allocator)
+		if err == nil {
+			return t.Name, address, nil
+		}
+		if !errors.Is(err, ErrNoSpaceLeft) {
+			return "", 0, err
+		}
+	}
+	for _, t := range skipped {
+		address, err := alloc(t.Allocator)
+		if err == nil {
+			return t.Name, address, nil
+		}
+		if !errors.Is(err, ErrNoSpaceLeft) {
+			return "", 0, err
+		}
+	}
+	return "", 0, ErrNoSpaceLeft
+}
+
+// aboveWatermark 判断分配 size 字节后该层的利用率是否会超过水位线。
+// 利用率读取位图和 B 树的空闲计数，不扫描位图，连续的分配都能看到之前分配的结果
+func (t Tier) aboveWatermark(size uint64) bool {
+	if t.HighWatermark <= 0 || t.HighWatermark >= 1 {
+		return false
+	}
+	total := t.Allocator.GetTotalSize()
+	return t.Allocator.GetDiskUtilization()+float64(size)/float64(total) > t.HighWatermark
+}
@@ -0,0 +1,78 @@
+package allocator
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTieredTestAllocator(t *testing.T, totalSize uint64) *diskAllocatorImpl {
+	cfg := newGrowTestConfig(t)
+	cfg.TotalSize = totalSize
+	cfg.SmallBlockLimit = 256
+	cfg.StatePersistencePath = ""
+	da := loadSnapshotTestAllocator(t, cfg)
+	t.Cleanup(func() { da.Close() })
+	return da
+}
+
+func TestTieredAllocatorSpillsAboveWatermark(t *testing.T) {
+	fast := newTieredTestAllocator(t, 16*1024*1024)
+	slow := newTieredTestAllocator(t, 8*1024*1024)
+	ta := NewTieredAllocator([]Tier{
+		{Name: "fast", Allocator: fast, HighWatermark: 0.5},
+		{Name: "slow", Allocator: slow},
+	})
+
+	const size = 1024 * 1024
+	for {
+		wantFast := fast.GetDiskUtilization()+float64(size)/float64(fast.GetTotalSize()) <= 0.5
+		tier, address, err := ta.Allocate(size)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if !wantFast {
+			if tier != "slow" {
+				t.Fatalf("Allocate() above watermark = %s, want slow", tier)
+			}
+			if err := slow.Free(address, size); err != nil {
+				t.Fatalf("Free() error = %v", err)
+			}
+			break
+		}
+		if tier != "fast" {
+			t.Fatalf("Allocate() below watermark = %s, want fast", tier)
+		}
+	}
+
+	// 下一层空间不足时回到超过水位线的层
+	if _, err := slow.Allocate(8*1024*1024 - slow.cfg.SmallBlockLimit*slow.cfg.UnitSize); err != nil {
+		t.Fatalf("Allocate() filling slow tier error = %v", err)
+	}
+	if tier, _, err := ta.Allocate(size); err != nil || tier != "fast" {
+		t.Errorf("Allocate() with slow tier full = %s, %v, want fast", tier, err)
+	}
+
+	// 所有层都放不下时返回空间不足
+	if _, _, err := ta.Allocate(32 * 1024 * 1024); !errors.Is(err, ErrNoSpaceLeft) {
+		t.Errorf("Allocate() larger than every tier error = %v, want %v", err, ErrNoSpaceLeft)
+	}
+}
+
+func TestTieredAllocatorReturnsOtherErrors(t *testing.T) {
+	fast := newTieredTestAllocator(t, 16*1024*1024)
+	slow := newTieredTestAllocator(t, 16*1024*1024)
+	ta := NewTieredAllocator([]Tier{
+		{Name: "fast", Allocator: fast},
+		{Name: "slow", Allocator: slow},
+	})
+
+	_, _, err := ta.AllocateWith(4096, func(a DiskAllocator) (uint64, error) {
+		return a.AllocateAligned(4096, 100)
+	})
+	if !errors.Is(err, ErrInvalidAlignment) {
+		t.Errorf("AllocateWith() error = %v, want %v", err, ErrInvalidAlignment)
+	}
+	if got := slow.GetAllocationCount(); got != 0 {
+		t.Errorf("slow tier allocations = %d, want 0", got)
+	}
+}
@@ -0,0 +1,205 @@
+package allocator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const ttlReapInterval = time.Second
+
+// TTLEntry 记录一段带过期时间的分配，Start 以单元为单位
+type TTLEntry struct {
+	Start     uint64
+	ExpiresAt time.Time
+}
+
+// TTLStats 是 TTL 回收的累计统计
+type TTLStats struct {
+	Tracked          uint64
+	ReclaimedExtents uint64
+	ReclaimedBytes   uint64
+}
+
+// ttlTable 记录带过期时间的分配，到期后由 reaper 释放
+type ttlTable struct {
+	mu      sync.Mutex
+	expires map[uint64]time.Time
+
+	reclaimedExtents uint64
+	reclaimedBytes   uint64
+}
+
+func newTTLTable() *ttlTable {
+	return &ttlTable{expires: make(map[uint64]time.Time)}
+}
+
+func (t *ttlTable) set(start uint64, expiresAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expires[start] = expiresAt
+}
+
+// extend 更新已有记录的过期时间，没有记录时返回 ErrNoTTL
+func (t *ttlTable) extend(start uint64, expiresAt time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.expires[start]; !ok {
+		return ErrNoTTL
+	}
+	t.expires[start] = expiresAt
+	return nil
+}
+
+func (t *ttlTable) forget(start uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.expires, start)
+}
+
+// claim 返回释放起点为 start 的分配时在分配表锁内删除其记录的函数
+func (t *ttlTable) claim(start uint64) func() bool {
+	return func() bool {
+		t.forget(start)
+		return true
+	}
+}
+
+// claimExpired 与 claim 相同，但只在记录仍然存在且已在 now 之前过期时删除并返回 true
+func (t *ttlTable) claimExpired(start uint64, now time.Time) func() bool {
+	return func() bool {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		expiresAt, ok := t.expires[start]
+		if !ok || !now.After(expiresAt) {
+			return false
+		}
+		delete(t.expires, start)
+		return true
+	}
+}
+
+func (t *ttlTable) expired(now time.Time) []uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var starts []uint64
+	for start, expiresAt := range t.expires {
+		if now.After(expiresAt) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+func (t *ttlTable) snapshot() []TTLEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := make([]TTLEntry, 0, len(t.expires))
+	for start, expiresAt := range t.expires {
+		entries = append(entries, TTLEntry{Start: start, ExpiresAt: expiresAt})
+	}
+	return entries
+}
+
+// load 用检查点中的过期时间替换当前内容
+func (t *ttlTable) load(entries []TTLEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.expires)
+	for _, e := range entries {
+		t.expires[e.Start] = e.ExpiresAt
+	}
+}
+
+func (da *diskAllocatorImpl) startTTLReaperRoutine() {
+	da.closeWg.Add(1)
+	go func() {
+		defer da.closeWg.Done()
+		ticker := time.NewTicker(ttlReapInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				da.reapExpired(time.Now())
+			case <-da.closeChan:
+				return
+			}
+		}
+	}()
+}
+
+// reapExpired 释放所有在 now 之前过期的分配。释放时在分配表锁内重新检查记录，
+// 期间被释放或续期的分配不会被回收，复用同一起点的新分配也不会被误释放
+func (da *diskAllocatorImpl) reapExpired(now time.Time) {
+	for _, start := range da.ttls.expired(now) {
+		claim := da.ttls.claimExpired(start, now)
+		entry, ok := da.allocations.lookup(start)
+		if !ok {
+			claim()
+			continue
+		}
+		if err := da.free(start, entry.Size, claim); err != nil {
+			continue
+		}
+		atomic.AddUint64(&da.ttls.reclaimedExtents, 1)
+		atomic.AddUint64(&da.ttls.reclaimedBytes, entry.Size*da.cfg.UnitSize)
+	}
+}
+
+// AllocateWithTTL 分配空间，ttl 到期后该空间会被自动释放
+func (da *diskAllocatorImpl) AllocateWithTTL(size uint64, ttl time.Duration) (uint64, error) {
+	return da.allocateWithTTL("", size, ttl)
+}
+
+func (da *diskAllocatorImpl) allocateWithTTL(tenant string, size uint64, ttl time.Duration) (uint64, error) {
+	if ttl <= 0 {
+		return 0, ErrInvalidTTL
+	}
+	address, err := da.allocateWithPolicy(tenant, size, PolicyDefault)
+	if err != nil {
+		return 0, err
+	}
+	if err := da.setTTL(address/da.cfg.UnitSize, time.Now().Add(ttl), false); err != nil {
+		da.Free(address, size)
+		return 0, err
+	}
+	return address, nil
+}
+
+// setTTL 设置起点为 start 的分配的过期时间并写 WAL，extend 为 true 时只更新已有的记录
+func (da *diskAllocatorImpl) setTTL(start uint64, expiresAt time.Time, extend bool) error {
+	da.walMu.RLock()
+	defer da.walMu.RUnlock()
+	if extend {
+		if err := da.ttls.extend(start, expiresAt); err != nil {
+			return err
+		}
+	} else {
+		da.ttls.set(start, expiresAt)
+	}
+	return da.wal.append(walRecord{Op: walOpTTL, Start: start, Units: uint64(expiresAt.UnixNano())})
+}
+
+// ExtendTTL 将带 TTL 的分配的过期时间重置为从现在起 ttl 之后
+func (da *diskAllocatorImpl) ExtendTTL(address uint64, ttl time.Duration) error {
+	if ttl <= 0 {
+		return ErrInvalidTTL
+	}
+	if err := da.setTTL(address/da.cfg.UnitSize, time.Now().Add(ttl), true); err != nil {
+		return err
+	}
+	da.incrementOperationCount()
+	return nil
+}
+
+func (da *diskAllocatorImpl) GetTTLStats() TTLStats {
+	da.ttls.mu.Lock()
+	tracked := uint64(len(da.ttls.expires))
+	da.ttls.mu.Unlock()
+	return TTLStats{
+		Tracked:          tracked,
+		ReclaimedExtents: atomic.LoadUint64(&da.ttls.reclaimedExtents),
+		ReclaimedBytes:   atomic.LoadUint64(&da.ttls.reclaimedBytes),
+	}
+}
@@ -0,0 +1,107 @@
+package allocator
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTTLReclaim(t *testing.T) {
+	da := newLeaseTestAllocator(t, "")
+	defer da.Close()
+
+	expiring, err := da.AllocateWithTTL(2*1024*1024, time.Second)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	extended, err := da.AllocateWithTTL(4096, time.Second)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	if err := da.ExtendTTL(extended, time.Hour); err != nil {
+		t.Fatalf("ExtendTTL() error = %v", err)
+	}
+
+	da.reapExpired(time.Now().Add(2 * time.Second))
+
+	if err := da.Free(expiring, 2*1024*1024); err != ErrNotAllocated {
+		t.Errorf("Expired allocation should be reclaimed, Free() error = %v", err)
+	}
+	stats := da.GetTTLStats()
+	if stats.ReclaimedExtents != 1 || stats.ReclaimedBytes != 2*1024*1024 || stats.Tracked != 1 {
+		t.Errorf("GetTTLStats() = %+v", stats)
+	}
+
+	if err := da.Free(extended, 4096); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if err := da.ExtendTTL(extended, time.Hour); err != ErrNoTTL {
+		t.Errorf("ExtendTTL() after free error = %v, want ErrNoTTL", err)
+	}
+}
+
+func TestTTLReapRechecksUnderLock(t *testing.T) {
+	da := newLeaseTestAllocator(t, "")
+	defer da.Close()
+
+	reused, err := da.AllocateWithTTL(8192, time.Second)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	extended, err := da.AllocateWithTTL(8192, time.Second)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	// reaper 取得过期列表之后，一段分配被释放并由普通分配复用，另一段被续期
+	later := time.Now().Add(2 * time.Second)
+	claims := map[uint64]func() bool{
+		reused:   da.ttls.claimExpired(reused/4096, later),
+		extended: da.ttls.claimExpired(extended/4096, later),
+	}
+	if err := da.Free(reused, 8192); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if err := da.Reserve(reused, 8192); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := da.ExtendTTL(extended, time.Hour); err != nil {
+		t.Fatalf("ExtendTTL() error = %v", err)
+	}
+
+	for address, claim := range claims {
+		if err := da.free(address/4096, 2, claim); err != ErrNotAllocated {
+			t.Errorf("free(%d) with stale expiry error = %v, want %v", address, err, ErrNotAllocated)
+		}
+		if err := da.Free(address, 8192); err != nil {
+			t.Errorf("Free(%d) error = %v", address, err)
+		}
+	}
+}
+
+func TestTTLPersistence(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test-ttl-state-*.gob")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+
+	da := newLeaseTestAllocator(t, tmpfile.Name())
+	addr, err := da.AllocateWithTTL(8192, time.Minute)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	if err := da.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reloaded := newLeaseTestAllocator(t, tmpfile.Name())
+	defer reloaded.Close()
+	if stats := reloaded.GetTTLStats(); stats.Tracked != 1 {
+		t.Fatalf("GetTTLStats().Tracked after reload = %d, want 1", stats.Tracked)
+	}
+	reloaded.reapExpired(time.Now().Add(2 * time.Minute))
+	if err := reloaded.Free(addr, 8192); err != ErrNotAllocated {
+		t.Errorf("Reloaded ttl allocation should be reclaimed, Free() error = %v", err)
+	}
+}
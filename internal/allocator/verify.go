@@ -0,0 +1,203 @@
+package allocator
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/btree"
+)
+
+// CheckIndexMismatch 表示 treeBySize 与 treeByStart 中的空闲块不一致，在线检查可以通过重建 treeBySize 修复
+const CheckIndexMismatch CheckKind = "index-mismatch"
+
+// maxVerifyProblems 限制一次在线检查报告的问题个数，避免索引整体损坏时产生大量日志
+const maxVerifyProblems = 100
+
+// VerifyReport 是一次在线一致性检查的结果，地址和大小均以单元为单位
+type VerifyReport struct {
+	Problems []CheckProblem
+
+	FreeBlocks      int
+	TreeFreeUnits   uint64  // 遍历 treeByStart 得到的空闲单元数
+	FencedUnits     uint64  // 收缩中被隔离的空闲单元数，不在索引中
+	BitmapFreeUnits uint64  // 位图中的空闲单元数
+	Utilization     float64 // 按遍历得到的空闲块计算的利用率，被隔离的空闲单元计为空闲
+	Repaired        bool    // 本次检查重建了 treeBySize 或重新统计了位图的空闲计数
+	Duration        time.Duration
+}
+
+// OK 判断是否没有发现问题
+func (r *VerifyReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *VerifyReport) add(kind CheckKind, format string, args ...any) {
+	if len(r.Problems) < maxVerifyProblems {
+		r.Problems = append(r.Problems, CheckProblem{Kind: kind, Detail: fmt.Sprintf(format, args...)})
+	}
+}
+
+func (r *VerifyReport) has(kind CheckKind) bool {
+	for _, p := range r.Problems {
+		if p.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyStats 是在线一致性检查的累计统计
+type VerifyStats struct {
+	Runs     uint64 // 检查次数
+	Failures uint64 // 发现问题的检查次数
+	Problems uint64 // 发现的问题总数
+	Repairs  uint64 // 修复次数：重建 treeBySize 或重新统计位图的空闲计数
+}
+
+type verifyCounters struct {
+	runs     uint64
+	failures uint64
+	problems uint64
+	repairs  uint64
+}
+
+// Verify 检查空闲树的两个索引是否一致、相邻空闲块是否已合并，freeSpace 是否等于空闲块之和，
+// 以及位图的空闲计数是否等于位图中的空闲位数（GetDiskUtilization 按这两个计数计算，不一致时利用率失真）。
+// repair 为 true 时，从 treeByStart 重建 treeBySize 并重新计算 freeSpace，或重新统计位图的空闲计数
+func (da *diskAllocatorImpl) Verify(repair bool) VerifyReport {
+	begin := time.Now()
+	report := VerifyReport{BitmapFreeUnits: da.bitmaps.countFree()}
+	da.tree.verify(&report)
+	treeBroken := report.has(CheckIndexMismatch) || report.has(CheckAccounting)
+	counted := da.bitmaps.GetAvailableSpace()
+	if counted != report.BitmapFreeUnits {
+		report.add(CheckAccounting, "bitmap free counter is %d, bitmap has %d free units", counted, report.BitmapFreeUnits)
+	}
+
+	if repair && treeBroken {
+		da.tree.rebuildSizeIndex()
+		report.Repaired = true
+		atomic.AddUint64(&da.verify.repairs, 1)
+		log.Printf("verify: rebuilt treeBySize from treeByStart (%d free blocks)", report.FreeBlocks)
+	}
+	if repair && counted != report.BitmapFreeUnits {
+		da.bitmaps.recount()
+		report.Repaired = true
+		atomic.AddUint64(&da.verify.repairs, 1)
+		log.Printf("verify: recounted bitmap free units (%d)", report.BitmapFreeUnits)
+	}
+
+	totalUnits := da.totalSize() / da.cfg.UnitSize
+	report.Utilization = float64(totalUnits-report.BitmapFreeUnits-report.TreeFreeUnits-report.FencedUnits) / float64(totalUnits)
+	report.Duration = time.Since(begin)
+
+	atomic.AddUint64(&da.verify.runs, 1)
+	if !report.OK() {
+		atomic.AddUint64(&da.verify.failures, 1)
+		atomic.AddUint64(&da.verify.problems, uint64(len(report.Problems)))
+		for _, p := range report.Problems {
+			log.Printf("verify: %s", p)
+		}
+	}
+	return report
+}
+
+// GetVerifyStats 返回在线一致性检查的累计统计
+func (da *diskAllocatorImpl) GetVerifyStats() VerifyStats {
+	return VerifyStats{
+		Runs:     atomic.LoadUint64(&da.verify.runs),
+		Failures: atomic.LoadUint64(&da.verify.failures),
+		Problems: atomic.LoadUint64(&da.verify.problems),
+		Repairs:  atomic.LoadUint64(&da.verify.repairs),
+	}
+}
+
+// startVerifyRoutine 按 VerifyIntervalSec 定期执行在线检查，VerifySelfHeal 决定是否自动修复
+func (da *diskAllocatorImpl) startVerifyRoutine() {
+	if da.cfg.VerifyIntervalSec <= 0 {
+		return
+	}
+	da.closeWg.Add(1)
+	go func() {
+		defer da.closeWg.Done()
+		ticker := time.NewTicker(time.Duration(da.cfg.VerifyIntervalSec) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				da.Verify(da.cfg.VerifySelfHeal)
+			case <-da.closeChan:
+				return
+			}
+		}
+	}()
+}
+
+// verify 在读锁下遍历两个索引，将发现的问题记录到 report
+func (dm *BTreeManager) verify(report *VerifyReport) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	var prevEnd uint64
+	first := true
+	dm.treeByStart.Ascend(func(item btree.Item) bool {
+		block := item.(BlockByStart).BTreeBlock
+		end := block.Start + block.Size
+		report.FreeBlocks++
+		report.TreeFreeUnits += block.Size
+
+		switch {
+		case block.Size == 0:
+			report.add(CheckEmptyBlock, "free block at %d has size 0", block.Start)
+		case end > dm.totalSpace:
+			report.add(CheckOutOfBounds, "free block [%d, %d) exceeds tree size %d", block.Start, end, dm.totalSpace)
+		}
+		if !first && block.Start < prevEnd {
+			report.add(CheckOverlap, "free block [%d, %d) overlaps previous block ending at %d", block.Start, end, prevEnd)
+		} else if !first && block.Start == prevEnd {
+			report.add(CheckUncoalesced, "free block [%d, %d) is adjacent to previous block", block.Start, end)
+		}
+		first = false
+		prevEnd = max(prevEnd, end)
+
+		if found := dm.treeBySize.Get(BlockBySize{block}); found == nil || *found.(BlockBySize).BTreeBlock != *block {
+			report.add(CheckIndexMismatch, "free block [%d, %d) is missing from treeBySize", block.Start, end)
+		}
+		return true
+	})
+
+	dm.treeBySize.Ascend(func(item btree.Item) bool {
+		block := item.(BlockBySize).BTreeBlock
+		if found := dm.treeByStart.Get(BlockByStart{block}); found == nil || *found.(BlockByStart).BTreeBlock != *block {
+			report.add(CheckIndexMismatch, "treeBySize has stale block [%d, %d)", block.Start, block.Start+block.Size)
+		}
+		return true
+	})
+	if n, m := dm.treeBySize.Len(), dm.treeByStart.Len(); n != m {
+		report.add(CheckIndexMismatch, "treeBySize has %d blocks, treeByStart has %d", n, m)
+	}
+	for _, block := range dm.fenced {
+		report.FencedUnits += block.Size
+	}
+	if dm.freeSpace != report.TreeFreeUnits {
+		report.add(CheckAccounting, "freeSpace is %d, free blocks sum to %d", dm.freeSpace, report.TreeFreeUnits)
+	}
+}
+
+// rebuildSizeIndex 以 treeByStart 为准重建 treeBySize 并重新计算 freeSpace
+func (dm *BTreeManager) rebuildSizeIndex() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	dm.treeBySize.Clear(false)
+	dm.freeSpace = 0
+	dm.treeByStart.Ascend(func(item btree.Item) bool {
+		block := item.(BlockByStart).BTreeBlock
+		dm.treeBySize.ReplaceOrInsert(BlockBySize{block})
+		dm.freeSpace += block.Size
+		return true
+	})
+}
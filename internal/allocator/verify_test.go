@@ -0,0 +1,140 @@
+package allocator
+
+import (
+	"testing"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newVerifyTestAllocator(t *testing.T) *diskAllocatorImpl {
+	cfg := &config.Config{
+		UnitSize:        4096,
+		TotalSize:       64 * 1024 * 1024,
+		SmallBlockLimit: 1024,
+		NumShards:       4,
+	}
+	loaded, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	da := loaded.(*diskAllocatorImpl)
+	var hole uint64
+	for _, size := range []uint64{4096, 64 * 4096, 1024 * 1024, 3 * 1024 * 1024, 1024 * 1024} {
+		address, err := da.Allocate(size)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if size == 3*1024*1024 {
+			hole = address
+		}
+	}
+	// 在空闲树中间留出一个空洞
+	if err := da.Free(hole, 3*1024*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	return da
+}
+
+func verifyKinds(report VerifyReport) map[CheckKind]bool {
+	kinds := make(map[CheckKind]bool)
+	for _, p := range report.Problems {
+		kinds[p.Kind] = true
+	}
+	return kinds
+}
+
+func TestVerifyClean(t *testing.T) {
+	da := newVerifyTestAllocator(t)
+	defer da.Close()
+
+	report := da.Verify(false)
+	if !report.OK() {
+		t.Errorf("Verify() problems = %v, want none", report.Problems)
+	}
+	if report.FreeBlocks != 2 || report.Utilization != da.GetDiskUtilization() {
+		t.Errorf("report = %+v, want 2 free blocks and utilization %v", report, da.GetDiskUtilization())
+	}
+	if stats := da.GetVerifyStats(); stats != (VerifyStats{Runs: 1}) {
+		t.Errorf("GetVerifyStats() = %+v, want one clean run", stats)
+	}
+}
+
+func TestVerifyLoadedState(t *testing.T) {
+	cfg, da := newFsckTestState(t)
+	crash(da)
+
+	// 从快照加载的空闲树中两个索引引用各自的块副本
+	recovered := loadSnapshotTestAllocator(t, cfg)
+	defer recovered.Close()
+	if report := recovered.Verify(false); !report.OK() {
+		t.Errorf("Verify() after LoadState problems = %v, want none", report.Problems)
+	}
+}
+
+func TestVerifyRepairsSizeIndex(t *testing.T) {
+	da := newVerifyTestAllocator(t)
+	defer da.Close()
+	utilization := da.GetDiskUtilization()
+
+	// 从 treeBySize 中删除一个块并使 freeSpace 偏移，模拟索引不同步
+	da.tree.mu.Lock()
+	first := da.tree.treeByStart.Min().(BlockByStart).BTreeBlock
+	da.tree.treeBySize.Delete(BlockBySize{first})
+	da.tree.freeSpace += 7
+	da.tree.mu.Unlock()
+
+	report := da.Verify(false)
+	kinds := verifyKinds(report)
+	if !kinds[CheckIndexMismatch] || !kinds[CheckAccounting] {
+		t.Errorf("Verify() problems = %v, want %s and %s", report.Problems, CheckIndexMismatch, CheckAccounting)
+	}
+	if report.Repaired || report.Utilization != utilization {
+		t.Errorf("report = %+v, want no repair and utilization %v", report, utilization)
+	}
+
+	if report := da.Verify(true); !report.Repaired {
+		t.Errorf("Verify(true) did not repair, problems = %v", report.Problems)
+	}
+	if report := da.Verify(false); !report.OK() {
+		t.Errorf("Verify() after repair problems = %v, want none", report.Problems)
+	}
+	checkTreeConsistent(t, da.tree)
+	if got := da.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after repair = %v, want %v", got, utilization)
+	}
+
+	want := VerifyStats{Runs: 3, Failures: 2, Repairs: 1, Problems: uint64(len(report.Problems)) * 2}
+	if stats := da.GetVerifyStats(); stats != want {
+		t.Errorf("GetVerifyStats() = %+v, want %+v", stats, want)
+	}
+	if _, err := da.Allocate(3 * 1024 * 1024); err != nil {
+		t.Errorf("Allocate() after repair error = %v", err)
+	}
+}
+
+func TestVerifyReportsUncoalesced(t *testing.T) {
+	da := newVerifyTestAllocator(t)
+	defer da.Close()
+
+	// 将最后一个空闲块拆成相邻的两块
+	da.tree.mu.Lock()
+	last := da.tree.treeByStart.Max().(BlockByStart).BTreeBlock
+	da.tree.treeByStart.Delete(BlockByStart{last})
+	da.tree.treeBySize.Delete(BlockBySize{last})
+	for _, block := range []*BTreeBlock{
+		{Start: last.Start, Size: 10},
+		{Start: last.Start + 10, Size: last.Size - 10},
+	} {
+		da.tree.treeByStart.ReplaceOrInsert(BlockByStart{block})
+		da.tree.treeBySize.ReplaceOrInsert(BlockBySize{block})
+	}
+	da.tree.mu.Unlock()
+
+	report := da.Verify(true)
+	if kinds := verifyKinds(report); !kinds[CheckUncoalesced] || len(kinds) != 1 {
+		t.Errorf("Verify() problems = %v, want only %s", report.Problems, CheckUncoalesced)
+	}
+	if report.Repaired {
+		t.Errorf("Verify(true) rebuilt treeBySize for consistent indexes")
+	}
+}
@@ -0,0 +1,478 @@
+package allocator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// WALSyncPolicy 决定 WAL 记录何时刷盘
+type WALSyncPolicy int
+
+const (
+	WALSyncOff      WALSyncPolicy = iota // 不写 WAL，崩溃后回到最近一次快照
+	WALSyncPerOp                         // 每次写入后立即 fsync
+	WALSyncGroup                         // 组提交：并发写入共享一次 fsync，返回前保证已落盘
+	WALSyncInterval                      // 按固定间隔 fsync，崩溃时可能丢失最后一个间隔内的记录
+)
+
+var walSyncPolicyNames = map[WALSyncPolicy]string{
+	WALSyncOff:      "off",
+	WALSyncPerOp:    "per-op",
+	WALSyncGroup:    "group",
+	WALSyncInterval: "interval",
+}
+
+func (p WALSyncPolicy) String() string {
+	if name, ok := walSyncPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("WALSyncPolicy(%d)", int(p))
+}
+
+// ParseWALSyncPolicy 解析配置中的刷盘策略名称，空字符串视为 off
+func ParseWALSyncPolicy(name string) (WALSyncPolicy, error) {
+	if name == "" {
+		return WALSyncOff, nil
+	}
+	for policy, n := range walSyncPolicyNames {
+		if n == name {
+			return policy, nil
+		}
+	}
+	return WALSyncOff, fmt.Errorf("unknown wal sync policy %q", name)
+}
+
+type walOp uint8
+
+const (
+	walOpAlloc walOp = iota + 1
+	walOpFree
+	walOpGrow         // 在线扩容：Start 为扩容前的总单元数，Units 为新增的单元数
+	walOpShrink       // 在线收缩：Start 为收缩后的总单元数，Units 为移除的单元数
+	walOpTTL          // 设置过期时间：Start 为分配的起点，Units 为过期时间的 Unix 纳秒数
+	walOpSessionOpen  // 打开会话：Start 为会话 ID，Units 为会话的 TTL（纳秒）
+	walOpSessionClose // 关闭会话：Start 为会话 ID，会话中未提交的分配另有释放记录
+	walOpLease        // 在会话中分配：Start 为分配的起点，Units 为会话 ID
+	walOpCommit       // 提交会话中的分配：Start 为分配的起点，Units 为会话 ID
+	walOpTenant       // 记录分配所属的租户：Start 为分配的起点，Units 为租户名的字节数
+	walOpResize       // 原地调整分配的大小：Start 为分配的起点，Units 为调整后的单元数
+)
+
+// walRecord 是 WAL 中的一条记录，Start 和 Units 的含义见 walOp，分配和释放以分配单元为单位
+type walRecord struct {
+	Op     walOp
+	Seq    uint64
+	Start  uint64
+	Units  uint64
+	Tenant string // 只用于 walOpTenant
+}
+
+// 记录格式：op(1) | seq(8) | start(8) | units(8) | crc32c(4)，
+// walOpTenant 之后紧跟租户名和它的 crc32c(4)
+const walRecordSize = 1 + 8 + 8 + 8 + 4
+
+var walCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// appendTo 将编码后的记录追加到 buf
+func (r walRecord) appendTo(buf []byte) []byte {
+	if r.Op == walOpTenant {
+		r.Units = uint64(len(r.Tenant))
+	}
+	head := len(buf)
+	buf = append(buf, byte(r.Op))
+	buf = binary.LittleEndian.AppendUint64(buf, r.Seq)
+	buf = binary.LittleEndian.AppendUint64(buf, r.Start)
+	buf = binary.LittleEndian.AppendUint64(buf, r.Units)
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(buf[head:], walCRCTable))
+	if r.Op == walOpTenant {
+		buf = append(buf, r.Tenant...)
+		buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum([]byte(r.Tenant), walCRCTable))
+	}
+	return buf
+}
+
+func decodeWALRecord(buf []byte) (walRecord, bool) {
+	if crc32.Checksum(buf[:25], walCRCTable) != binary.LittleEndian.Uint32(buf[25:]) {
+		return walRecord{}, false
+	}
+	r := walRecord{
+		Op:    walOp(buf[0]),
+		Seq:   binary.LittleEndian.Uint64(buf[1:]),
+		Start: binary.LittleEndian.Uint64(buf[9:]),
+		Units: binary.LittleEndian.Uint64(buf[17:]),
+	}
+	return r, r.Op >= walOpAlloc && r.Op <= walOpResize
+}
+
+// readWAL 读取 path 中的全部完整记录，返回记录和有效部分的长度。
+// 崩溃时写了一半的尾部记录（长度不足或校验失败）及其之后的内容会被忽略
+func readWAL(path string) ([]walRecord, int64, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open wal: %w", err)
+	}
+	defer file.Close()
+
+	var records []walRecord
+	var valid int64
+	reader := bufio.NewReader(file)
+	buf := make([]byte, walRecordSize)
+	for {
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return records, valid, nil
+			}
+			return nil, 0, fmt.Errorf("failed to read wal: %w", err)
+		}
+		record, ok := decodeWALRecord(buf)
+		if !ok {
+			return records, valid, nil
+		}
+		size := int64(walRecordSize)
+		if record.Op == walOpTenant {
+			// 记录头已通过校验，Units 即为租户名的长度
+			name := make([]byte, record.Units+4)
+			if _, err := io.ReadFull(reader, name); err != nil {
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					return records, valid, nil
+				}
+				return nil, 0, fmt.Errorf("failed to read wal: %w", err)
+			}
+			if crc32.Checksum(name[:record.Units], walCRCTable) != binary.LittleEndian.Uint32(name[record.Units:]) {
+				return records, valid, nil
+			}
+			record.Tenant = string(name[:record.Units])
+			size += int64(len(name))
+		}
+		records = append(records, record)
+		valid += size
+	}
+}
+
+// walLog 是只追加的分配、释放以及会话、TTL 和租户归属变更的日志，快照完成后截断已包含在快照中的记录
+type walLog struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	path    string
+	policy  WALSyncPolicy
+	file    *os.File
+	writer  *bufio.Writer
+	seq     uint64 // 最后一条已写入记录的序号
+	synced  uint64 // 已落盘的最大序号
+	syncing bool
+	err     error // 写入或刷盘失败后保留错误，之后的追加全部失败
+
+	closeChan chan struct{}
+	closeWg   sync.WaitGroup
+}
+
+// openWAL 打开 path 处的 WAL 并截掉损坏的尾部，seq 为已有记录和快照中的最大序号
+func openWAL(path string, policy WALSyncPolicy, interval time.Duration, validSize int64, seq uint64) (*walLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wal: %w", err)
+	}
+	if err := file.Truncate(validSize); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate wal: %w", err)
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek wal: %w", err)
+	}
+
+	w := &walLog{
+		path:      path,
+		policy:    policy,
+		file:      file,
+		writer:    bufio.NewWriter(file),
+		seq:       seq,
+		synced:    seq,
+		closeChan: make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	if policy == WALSyncInterval {
+		w.startSyncRoutine(interval)
+	}
+	return w, nil
+}
+
+func (w *walLog) startSyncRoutine(interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	w.closeWg.Add(1)
+	go func() {
+		defer w.closeWg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.mu.Lock()
+				if w.err == nil && w.synced < w.seq {
+					w.syncLocked()
+				}
+				w.mu.Unlock()
+			case <-w.closeChan:
+				return
+			}
+		}
+	}()
+}
+
+// append 写入一组记录并按刷盘策略等待落盘。walLog 为 nil（未启用 WAL）时直接返回
+func (w *walLog) append(records ...walRecord) error {
+	if w == nil || len(records) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+
+	buf := make([]byte, 0, walRecordSize)
+	for _, record := range records {
+		w.seq++
+		record.Seq = w.seq
+		buf = record.appendTo(buf[:0])
+		if _, err := w.writer.Write(buf); err != nil {
+			w.err = fmt.Errorf("failed to write wal: %w", err)
+			return w.err
+		}
+	}
+
+	switch w.policy {
+	case WALSyncPerOp:
+		return w.syncLocked()
+	case WALSyncGroup:
+		return w.waitSyncedLocked(w.seq)
+	}
+	return nil
+}
+
+// syncLocked 将缓冲区写入文件并 fsync，调用方需持有锁
+func (w *walLog) syncLocked() error {
+	if err := w.writer.Flush(); err != nil {
+		w.err = fmt.Errorf("failed to write wal: %w", err)
+		return w.err
+	}
+	if err := w.file.Sync(); err != nil {
+		w.err = fmt.Errorf("failed to sync wal: %w", err)
+		return w.err
+	}
+	w.synced = w.seq
+	return nil
+}
+
+// waitSyncedLocked 实现组提交：第一个等待者在释放锁后执行 fsync，
+// 其间到达的写入者等待下一轮，一次 fsync 覆盖之前写入的全部记录
+func (w *walLog) waitSyncedLocked(seq uint64) error {
+	for w.synced < seq && w.err == nil {
+		if w.syncing {
+			w.cond.Wait()
+			continue
+		}
+
+		w.syncing = true
+		if err := w.writer.Flush(); err != nil {
+			w.err = fmt.Errorf("failed to write wal: %w", err)
+		} else {
+			target, file := w.seq, w.file
+			w.mu.Unlock()
+			err := file.Sync()
+			w.mu.Lock()
+			if err != nil {
+				w.err = fmt.Errorf("failed to sync wal: %w", err)
+			} else if target > w.synced {
+				w.synced = target
+			}
+		}
+		w.syncing = false
+		w.cond.Broadcast()
+	}
+	return w.err
+}
+
+// lastSeq 返回最后一条已写入记录的序号
+func (w *walLog) lastSeq() uint64 {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.seq
+}
+
+// truncate 删除序号不大于 seq 的记录（这些记录已包含在快照中），保留快照之后写入的记录
+func (w *walLog) truncate(seq uint64) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.syncing {
+		w.cond.Wait()
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.writer.Flush(); err != nil {
+		w.err = fmt.Errorf("failed to write wal: %w", err)
+		return w.err
+	}
+
+	records, _, err := readWAL(w.path)
+	if err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(w.path), "temp_wal_*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+
+	writer := bufio.NewWriter(tempFile)
+	buf := make([]byte, 0, walRecordSize)
+	for _, record := range records {
+		if record.Seq <= seq {
+			continue
+		}
+		buf = record.appendTo(buf[:0])
+		if _, err := writer.Write(buf); err != nil {
+			tempFile.Close()
+			return fmt.Errorf("failed to write wal: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write wal: %w", err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to sync wal: %w", err)
+	}
+	if err := os.Rename(tempFilePath, w.path); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	w.file.Close()
+	w.file = tempFile
+	w.writer = bufio.NewWriter(tempFile)
+	w.synced = w.seq
+	return nil
+}
+
+// close 停止后台刷盘并将剩余记录落盘
+func (w *walLog) close() error {
+	if w == nil {
+		return nil
+	}
+	close(w.closeChan)
+	w.closeWg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.syncing {
+		w.cond.Wait()
+	}
+	var err error
+	if w.err == nil {
+		err = w.syncLocked()
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+// replay 将快照之后的 WAL 记录依次作用到内存状态。快照不是在全局锁下生成的，
+// 可能已经包含部分序号更大的修改，因此重放只做幂等的标记，不做占用检查
+func (da *diskAllocatorImpl) replay(records []walRecord) {
+	for _, record := range records {
+		switch record.Op {
+		case walOpAlloc:
+			da.allocations.insert(record.Start, record.Units)
+			da.markUnits(record.Start, record.Units, true)
+		case walOpFree:
+			da.allocations.remove(record.Start, record.Units, nil)
+			da.leases.forget(record.Start)
+			da.ttls.forget(record.Start)
+			da.tenants.forget(record.Start, record.Units*da.cfg.UnitSize)
+			da.markUnits(record.Start, record.Units, false)
+		case walOpGrow:
+			da.growUnits(record.Start + record.Units)
+		case walOpShrink:
+			da.shrinkUnits(record.Start)
+		case walOpTTL:
+			da.ttls.set(record.Start, time.Unix(0, int64(record.Units)))
+		case walOpSessionOpen:
+			da.leases.reopen(record.Start, time.Duration(record.Units))
+		case walOpSessionClose:
+			da.leases.close(record.Start)
+		case walOpLease:
+			if entry, ok := da.allocations.lookup(record.Start); ok {
+				da.leases.add(record.Units, record.Start, entry.Size)
+			}
+		case walOpCommit:
+			da.leases.forget(record.Start)
+		case walOpTenant:
+			da.tenants.assign(record.Start, record.Tenant)
+		case walOpResize:
+			da.replayResize(record.Start, record.Units)
+		}
+	}
+}
+
+// replayResize 将起点为 start 的分配调整为 units 个单元。分配的 TTL、租约和租户不变，
+// 缩小时归还的尾部标记为空闲，快照中已是调整后的大小时只重新标记占用
+func (da *diskAllocatorImpl) replayResize(start, units uint64) {
+	entry, ok := da.allocations.lookup(start)
+	if !ok {
+		da.allocations.insert(start, units)
+	} else if entry.Size != units {
+		da.allocations.resize(start, entry.Size, units)
+		if entry.Size > units {
+			da.markUnits(start+units, entry.Size-units, false)
+		}
+	}
+	da.leases.resize(start, units)
+	da.markUnits(start, units, true)
+}
+
+// markUnits 将单元范围 [start, start+units) 在位图区和 B 树区中幂等地标记为已占用或空闲，位图覆盖不到的单元不做标记
+func (da *diskAllocatorImpl) markUnits(start, units uint64, used bool) {
+	if start < da.cfg.SmallBlockLimit {
+		blocks := min(units, da.cfg.SmallBlockLimit-start)
+		if used {
+			da.bitmaps.markUsed(start, blocks)
+		} else {
+			da.bitmaps.Free(start, blocks)
+		}
+		start += blocks
+		units -= blocks
+	}
+	if units == 0 {
+		return
+	}
+	if used {
+		da.tree.markUsed(start-da.cfg.SmallBlockLimit, units)
+	} else {
+		da.tree.markFree(start-da.cfg.SmallBlockLimit, units)
+	}
+}
@@ -0,0 +1,347 @@
+package allocator
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+)
+
+func newWALTestAllocator(t *testing.T, dir string, policy string) *diskAllocatorImpl {
+	cfg := &config.Config{
+		UnitSize:             4096,
+		TotalSize:            64 * 1024 * 1024,
+		SmallBlockLimit:      1024,
+		NumShards:            4,
+		StatePersistencePath: filepath.Join(dir, "state"),
+		BackupIntervalSec:    3600,
+		WALSyncPolicy:        policy,
+	}
+	da, err := LoadState(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	return da.(*diskAllocatorImpl)
+}
+
+// crash 停止后台任务并关闭 WAL，但不写快照，模拟进程崩溃
+func crash(da *diskAllocatorImpl) {
+	close(da.closeChan)
+	da.closeWg.Wait()
+	da.wal.close()
+}
+
+func TestWALRecovery(t *testing.T) {
+	for _, policy := range []string{"per-op", "group", "interval"} {
+		t.Run(policy, func(t *testing.T) {
+			dir := t.TempDir()
+			da := newWALTestAllocator(t, dir, policy)
+
+			small, err := da.Allocate(8 * 4096)
+			if err != nil {
+				t.Fatalf("Allocate() error = %v", err)
+			}
+			large, err := da.Allocate(1024 * 1024)
+			if err != nil {
+				t.Fatalf("Allocate() error = %v", err)
+			}
+			freed, err := da.Allocate(2 * 1024 * 1024)
+			if err != nil {
+				t.Fatalf("Allocate() error = %v", err)
+			}
+			if err := da.Free(freed, 2*1024*1024); err != nil {
+				t.Fatalf("Free() error = %v", err)
+			}
+			if _, _, err := da.Resize(large, 1024*1024, 512*1024); err != nil {
+				t.Fatalf("Resize() error = %v", err)
+			}
+			utilization := da.GetDiskUtilization()
+			crash(da)
+
+			recovered := newWALTestAllocator(t, dir, policy)
+			defer recovered.Close()
+			if got := recovered.GetDiskUtilization(); got != utilization {
+				t.Errorf("utilization after recovery = %v, want %v", got, utilization)
+			}
+			if err := recovered.Free(freed, 2*1024*1024); err != ErrNotAllocated {
+				t.Errorf("Free(freed) error = %v, want %v", err, ErrNotAllocated)
+			}
+			if err := recovered.Free(large, 512*1024); err != nil {
+				t.Errorf("Free(large) error = %v", err)
+			}
+			if err := recovered.Free(small, 8*4096); err != nil {
+				t.Errorf("Free(small) error = %v", err)
+			}
+		})
+	}
+}
+
+func TestWALTruncatedBySnapshot(t *testing.T) {
+	dir := t.TempDir()
+	da := newWALTestAllocator(t, dir, "group")
+
+	for i := 0; i < 10; i++ {
+		if _, err := da.Allocate(1024 * 1024); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+	}
+	if err := da.SaveState(); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if info, err := os.Stat(walPath(da.cfg)); err != nil || info.Size() != 0 {
+		t.Fatalf("wal after snapshot: info = %v, err = %v, want empty file", info, err)
+	}
+
+	address, err := da.Allocate(1024 * 1024)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	records, _, err := readWAL(walPath(da.cfg))
+	if err != nil {
+		t.Fatalf("readWAL() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Seq != 11 || records[0].Start*4096 != address {
+		t.Errorf("records after snapshot = %+v, want single allocation with seq 11", records)
+	}
+	utilization := da.GetDiskUtilization()
+	crash(da)
+
+	recovered := newWALTestAllocator(t, dir, "group")
+	defer recovered.Close()
+	if got := recovered.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after recovery = %v, want %v", got, utilization)
+	}
+}
+
+func TestWALTornTail(t *testing.T) {
+	dir := t.TempDir()
+	da := newWALTestAllocator(t, dir, "per-op")
+	address, err := da.Allocate(1024 * 1024)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	crash(da)
+
+	// 模拟写了一半的记录
+	file, err := os.OpenFile(walPath(da.cfg), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open wal: %v", err)
+	}
+	file.Write([]byte{byte(walOpFree), 1, 2, 3})
+	file.Close()
+
+	recovered := newWALTestAllocator(t, dir, "per-op")
+	defer recovered.Close()
+	if err := recovered.Free(address, 1024*1024); err != nil {
+		t.Errorf("Free() after torn tail error = %v", err)
+	}
+	records, _, err := readWAL(walPath(da.cfg))
+	if err != nil {
+		t.Fatalf("readWAL() error = %v", err)
+	}
+	if len(records) != 2 || records[1].Op != walOpFree || records[1].Seq != 2 {
+		t.Errorf("records = %+v, want torn tail replaced by free record", records)
+	}
+}
+
+func TestWALReplayIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	da := newWALTestAllocator(t, dir, "group")
+	defer da.Close()
+
+	var addresses []uint64
+	for _, size := range []uint64{4096, 64 * 4096, 1024 * 1024, 3 * 1024 * 1024} {
+		address, err := da.Allocate(size)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := da.Free(addresses[2], 1024*1024); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	records, _, err := readWAL(walPath(da.cfg))
+	if err != nil {
+		t.Fatalf("readWAL() error = %v", err)
+	}
+
+	// 快照可能已经包含部分记录，重放已生效的记录不能改变状态
+	utilization := da.GetDiskUtilization()
+	freeBlocks := da.tree.treeByStart.Len()
+	da.replay(records)
+	if got := da.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after replay = %v, want %v", got, utilization)
+	}
+	if got := da.tree.treeByStart.Len(); got != freeBlocks {
+		t.Errorf("free blocks after replay = %d, want %d", got, freeBlocks)
+	}
+	if da.tree.treeBySize.Len() != da.tree.treeByStart.Len() {
+		t.Errorf("treeBySize has %d blocks, treeByStart has %d", da.tree.treeBySize.Len(), da.tree.treeByStart.Len())
+	}
+}
+
+func TestWALGroupCommitConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	da := newWALTestAllocator(t, dir, "group")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				address, err := da.Allocate(4096)
+				if err != nil {
+					t.Errorf("Allocate() error = %v", err)
+					return
+				}
+				if j%2 == 0 {
+					da.Free(address, 4096)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	utilization := da.GetDiskUtilization()
+	crash(da)
+
+	records, _, err := readWAL(walPath(da.cfg))
+	if err != nil {
+		t.Fatalf("readWAL() error = %v", err)
+	}
+	if len(records) != 8*50+8*25 {
+		t.Errorf("len(records) = %d, want %d", len(records), 8*50+8*25)
+	}
+	for i, record := range records {
+		if record.Seq != uint64(i+1) {
+			t.Fatalf("records[%d].Seq = %d, want %d", i, record.Seq, i+1)
+		}
+	}
+
+	recovered := newWALTestAllocator(t, dir, "group")
+	defer recovered.Close()
+	if got := recovered.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after recovery = %v, want %v", got, utilization)
+	}
+}
+
+func TestWALRecoversSessionsAndTTLs(t *testing.T) {
+	dir := t.TempDir()
+	da := newWALTestAllocator(t, dir, "per-op")
+
+	expiring, err := da.AllocateWithTTL(8192, time.Second)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	extended, err := da.AllocateWithTTL(8192, time.Second)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	if err := da.ExtendTTL(extended, time.Hour); err != nil {
+		t.Fatalf("ExtendTTL() error = %v", err)
+	}
+	id, err := da.OpenSession(time.Hour)
+	if err != nil {
+		t.Fatalf("OpenSession() error = %v", err)
+	}
+	leased, err := da.AllocateInSession(id, 8192)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	committed, err := da.AllocateInSession(id, 8192)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	if err := da.Commit(id, committed, 8192); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	closed, _ := da.OpenSession(time.Hour)
+	released, err := da.AllocateInSession(closed, 8192)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	if err := da.CloseSession(closed); err != nil {
+		t.Fatalf("CloseSession() error = %v", err)
+	}
+	crash(da)
+
+	// 没有快照，会话和 TTL 全部由 WAL 恢复
+	da = newWALTestAllocator(t, dir, "per-op")
+	defer da.Close()
+	da.reapExpired(time.Now().Add(2 * time.Second))
+	if err := da.Free(expiring, 8192); err != ErrNotAllocated {
+		t.Errorf("Free(expiring) after reap error = %v, want %v", err, ErrNotAllocated)
+	}
+	if stats := da.GetTTLStats(); stats.Tracked != 1 || stats.ReclaimedExtents != 1 {
+		t.Errorf("GetTTLStats() = %+v, want 1 tracked and 1 reclaimed", stats)
+	}
+	if err := da.Commit(id, committed, 8192); err != ErrNotLeased {
+		t.Errorf("Commit(committed) error = %v, want %v", err, ErrNotLeased)
+	}
+	if err := da.Commit(id, leased, 8192); err != nil {
+		t.Errorf("Commit(leased) error = %v", err)
+	}
+	if err := da.CloseSession(closed); err != ErrSessionNotFound {
+		t.Errorf("CloseSession(closed) error = %v, want %v", err, ErrSessionNotFound)
+	}
+	if err := da.Free(released, 8192); err != ErrNotAllocated {
+		t.Errorf("Free(released) error = %v, want %v", err, ErrNotAllocated)
+	}
+	if next, _ := da.OpenSession(time.Hour); next <= closed {
+		t.Errorf("OpenSession() after recovery = %d, want > %d", next, closed)
+	}
+}
+
+func TestWALRecoversResizedExtents(t *testing.T) {
+	dir := t.TempDir()
+	da := newWALTestAllocator(t, dir, "per-op")
+
+	withTTL, err := da.AllocateWithTTL(2*1024*1024, time.Hour)
+	if err != nil {
+		t.Fatalf("AllocateWithTTL() error = %v", err)
+	}
+	id, err := da.OpenSession(time.Hour)
+	if err != nil {
+		t.Fatalf("OpenSession() error = %v", err)
+	}
+	leased, err := da.AllocateInSession(id, 4*4096)
+	if err != nil {
+		t.Fatalf("AllocateInSession() error = %v", err)
+	}
+	owned, err := da.ForTenant("a").Allocate(2 * 1024 * 1024)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	for _, r := range []struct{ address, oldSize, newSize uint64 }{
+		{withTTL, 2 * 1024 * 1024, 1024 * 1024},
+		{leased, 4 * 4096, 2 * 4096},
+		{owned, 2 * 1024 * 1024, 1024 * 1024},
+	} {
+		if _, moved, err := da.Resize(r.address, r.oldSize, r.newSize); err != nil || moved {
+			t.Fatalf("Resize(%d) = moved %v, error %v, want in place", r.address, moved, err)
+		}
+	}
+	utilization := da.GetDiskUtilization()
+	crash(da)
+
+	// 原地调整大小不会丢掉分配的 TTL、租约和租户
+	da = newWALTestAllocator(t, dir, "per-op")
+	defer da.Close()
+	if got := da.GetDiskUtilization(); got != utilization {
+		t.Errorf("utilization after recovery = %v, want %v", got, utilization)
+	}
+	if stats := da.GetTTLStats(); stats.Tracked != 1 {
+		t.Errorf("GetTTLStats().Tracked = %d, want 1", stats.Tracked)
+	}
+	checkTenantUsed(t, da, "a", 1024*1024)
+	if err := da.Commit(id, leased, 2*4096); err != nil {
+		t.Errorf("Commit() of resized lease error = %v", err)
+	}
+	da.reapExpired(time.Now().Add(2 * time.Hour))
+	if err := da.Free(withTTL, 1024*1024); err != ErrNotAllocated {
+		t.Errorf("Free() of expired resized extent error = %v, want %v", err, ErrNotAllocated)
+	}
+}
@@ -20,12 +20,122 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PlacementPolicy int32
+
+const (
+	PlacementPolicy_POLICY_DEFAULT PlacementPolicy = 0
+	PlacementPolicy_BEST_FIT       PlacementPolicy = 1
+	PlacementPolicy_FIRST_FIT      PlacementPolicy = 2
+	PlacementPolicy_NEXT_FIT       PlacementPolicy = 3
+	PlacementPolicy_WORST_FIT      PlacementPolicy = 4
+)
+
+// Enum value maps for PlacementPolicy.
+var (
+	PlacementPolicy_name = map[int32]string{
+		0: "POLICY_DEFAULT",
+		1: "BEST_FIT",
+		2: "FIRST_FIT",
+		3: "NEXT_FIT",
+		4: "WORST_FIT",
+	}
+	PlacementPolicy_value = map[string]int32{
+		"POLICY_DEFAULT": 0,
+		"BEST_FIT":       1,
+		"FIRST_FIT":      2,
+		"NEXT_FIT":       3,
+		"WORST_FIT":      4,
+	}
+)
+
+func (x PlacementPolicy) Enum() *PlacementPolicy {
+	p := new(PlacementPolicy)
+	*p = x
+	return p
+}
+
+func (x PlacementPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PlacementPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_spaceweave_proto_enumTypes[0].Descriptor()
+}
+
+func (PlacementPolicy) Type() protoreflect.EnumType {
+	return &file_proto_spaceweave_proto_enumTypes[0]
+}
+
+func (x PlacementPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PlacementPolicy.Descriptor instead.
+func (PlacementPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{0}
+}
+
+// Region 是区间所在的区域
+type Region int32
+
+const (
+	Region_BITMAP Region = 0
+	Region_TREE   Region = 1
+)
+
+// Enum value maps for Region.
+var (
+	Region_name = map[int32]string{
+		0: "BITMAP",
+		1: "TREE",
+	}
+	Region_value = map[string]int32{
+		"BITMAP": 0,
+		"TREE":   1,
+	}
+)
+
+func (x Region) Enum() *Region {
+	p := new(Region)
+	*p = x
+	return p
+}
+
+func (x Region) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Region) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_spaceweave_proto_enumTypes[1].Descriptor()
+}
+
+func (Region) Type() protoreflect.EnumType {
+	return &file_proto_spaceweave_proto_enumTypes[1]
+}
+
+func (x Region) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Region.Descriptor instead.
+func (Region) EnumDescriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{1}
+}
+
+// AllocateRequest 中 policy、alignment、session_id 和 ttl_sec 至多指定一个，组合使用时返回 INVALID_ARGUMENT
 type AllocateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Size uint64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Size      uint64          `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Policy    PlacementPolicy `protobuf:"varint,2,opt,name=policy,proto3,enum=diskalloc.PlacementPolicy" json:"policy,omitempty"`
+	Alignment uint64          `protobuf:"varint,3,opt,name=alignment,proto3" json:"alignment,omitempty"`                  // 字节，需为 UnitSize 的整数倍，0 表示不额外对齐
+	SessionId uint64          `protobuf:"varint,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 非 0 时分配登记在该会话下，需 Commit 后才成为永久分配
+	TtlSec    uint32          `protobuf:"varint,5,opt,name=ttl_sec,json=ttlSec,proto3" json:"ttl_sec,omitempty"`          // 非 0 时分配在 ttl_sec 秒后自动释放
+	PoolId    string          `protobuf:"bytes,6,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`           // 存储池 ID，为空时使用默认存储池，其他请求中的 pool_id 含义相同
+	Tiered    bool            `protobuf:"varint,7,opt,name=tiered,proto3" json:"tiered,omitempty"`                        // 为 true 时按服务端 TIERS 配置分层分配，忽略 pool_id，不能与 session_id 同时使用
+	TenantId  string          `protobuf:"bytes,8,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`     // 租户 ID，为空时取 metadata 中的 x-tenant-id，都为空时不计配额；其他请求中的 tenant_id 含义相同
 }
 
 func (x *AllocateRequest) Reset() {
@@ -67,12 +177,63 @@ func (x *AllocateRequest) GetSize() uint64 {
 	return 0
 }
 
+func (x *AllocateRequest) GetPolicy() PlacementPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return PlacementPolicy_POLICY_DEFAULT
+}
+
+func (x *AllocateRequest) GetAlignment() uint64 {
+	if x != nil {
+		return x.Alignment
+	}
+	return 0
+}
+
+func (x *AllocateRequest) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *AllocateRequest) GetTtlSec() uint32 {
+	if x != nil {
+		return x.TtlSec
+	}
+	return 0
+}
+
+func (x *AllocateRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *AllocateRequest) GetTiered() bool {
+	if x != nil {
+		return x.Tiered
+	}
+	return false
+}
+
+func (x *AllocateRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
 type AllocateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Address uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Address       uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Tier          string `protobuf:"bytes,2,opt,name=tier,proto3" json:"tier,omitempty"`                                           // 分配所在的存储池 ID，释放时作为 pool_id
+	OverSoftQuota bool   `protobuf:"varint,3,opt,name=over_soft_quota,json=overSoftQuota,proto3" json:"over_soft_quota,omitempty"` // 分配后租户的用量超过软配额
 }
 
 func (x *AllocateResponse) Reset() {
@@ -114,6 +275,20 @@ func (x *AllocateResponse) GetAddress() uint64 {
 	return 0
 }
 
+func (x *AllocateResponse) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *AllocateResponse) GetOverSoftQuota() bool {
+	if x != nil {
+		return x.OverSoftQuota
+	}
+	return false
+}
+
 type FreeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -121,6 +296,7 @@ type FreeRequest struct {
 
 	Address uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
 	Size    uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	PoolId  string `protobuf:"bytes,3,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
 }
 
 func (x *FreeRequest) Reset() {
@@ -169,6 +345,13 @@ func (x *FreeRequest) GetSize() uint64 {
 	return 0
 }
 
+func (x *FreeRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
 type FreeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -207,14 +390,17 @@ func (*FreeResponse) Descriptor() ([]byte, []int) {
 	return file_proto_spaceweave_proto_rawDescGZIP(), []int{3}
 }
 
-type GetDiskUtilizationRequest struct {
+type Extent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Address uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Size    uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
 }
 
-func (x *GetDiskUtilizationRequest) Reset() {
-	*x = GetDiskUtilizationRequest{}
+func (x *Extent) Reset() {
+	*x = Extent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_spaceweave_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -222,13 +408,13 @@ func (x *GetDiskUtilizationRequest) Reset() {
 	}
 }
 
-func (x *GetDiskUtilizationRequest) String() string {
+func (x *Extent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDiskUtilizationRequest) ProtoMessage() {}
+func (*Extent) ProtoMessage() {}
 
-func (x *GetDiskUtilizationRequest) ProtoReflect() protoreflect.Message {
+func (x *Extent) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_spaceweave_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -240,21 +426,39 @@ func (x *GetDiskUtilizationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDiskUtilizationRequest.ProtoReflect.Descriptor instead.
-func (*GetDiskUtilizationRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Extent.ProtoReflect.Descriptor instead.
+func (*Extent) Descriptor() ([]byte, []int) {
 	return file_proto_spaceweave_proto_rawDescGZIP(), []int{4}
 }
 
-type GetDiskUtilizationResponse struct {
+func (x *Extent) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *Extent) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type AllocateExtentsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Utilization float32 `protobuf:"fixed32,1,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	Size          uint64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	MaxExtents    uint32 `protobuf:"varint,2,opt,name=max_extents,json=maxExtents,proto3" json:"max_extents,omitempty"`            // 0 表示不限制片段数
+	MinExtentSize uint64 `protobuf:"varint,3,opt,name=min_extent_size,json=minExtentSize,proto3" json:"min_extent_size,omitempty"` // 每个片段的最小字节数
+	PoolId        string `protobuf:"bytes,4,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	TenantId      string `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
 }
 
-func (x *GetDiskUtilizationResponse) Reset() {
-	*x = GetDiskUtilizationResponse{}
+func (x *AllocateExtentsRequest) Reset() {
+	*x = AllocateExtentsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_spaceweave_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -262,13 +466,13 @@ func (x *GetDiskUtilizationResponse) Reset() {
 	}
 }
 
-func (x *GetDiskUtilizationResponse) String() string {
+func (x *AllocateExtentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDiskUtilizationResponse) ProtoMessage() {}
+func (*AllocateExtentsRequest) ProtoMessage() {}
 
-func (x *GetDiskUtilizationResponse) ProtoReflect() protoreflect.Message {
+func (x *AllocateExtentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_spaceweave_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -280,151 +484,3759 @@ func (x *GetDiskUtilizationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDiskUtilizationResponse.ProtoReflect.Descriptor instead.
-func (*GetDiskUtilizationResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AllocateExtentsRequest.ProtoReflect.Descriptor instead.
+func (*AllocateExtentsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_spaceweave_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetDiskUtilizationResponse) GetUtilization() float32 {
+func (x *AllocateExtentsRequest) GetSize() uint64 {
 	if x != nil {
-		return x.Utilization
+		return x.Size
 	}
 	return 0
 }
 
-var File_proto_spaceweave_proto protoreflect.FileDescriptor
-
-var file_proto_spaceweave_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x77, 0x65, 0x61,
-	0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c,
-	0x6c, 0x6f, 0x63, 0x22, 0x25, 0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x2c, 0x0a, 0x10, 0x41, 0x6c,
-	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
-	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x3b, 0x0a, 0x0b, 0x46, 0x72, 0x65, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
-	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
-	0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x0e, 0x0a, 0x0c, 0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b,
-	0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0x3e, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x74, 0x69,
-	0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x20, 0x0a, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x32, 0xf6, 0x01, 0x0a, 0x0d, 0x44, 0x69, 0x73, 0x6b, 0x41, 0x6c, 0x6c, 0x6f, 0x63,
-	0x61, 0x74, 0x6f, 0x72, 0x12, 0x45, 0x0a, 0x08, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65,
-	0x12, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41, 0x6c, 0x6c,
-	0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x64,
-	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x04, 0x46,
-	0x72, 0x65, 0x65, 0x12, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e,
-	0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x69,
-	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x63, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73,
-	0x6b, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x2e, 0x64,
-	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b,
-	0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x47,
-	0x65, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x38, 0x5a, 0x36, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x31, 0x32, 0x31, 0x33,
-	0x39, 0x38, 0x37, 0x38, 0x34, 0x32, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x77, 0x65, 0x61, 0x76,
-	0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x77, 0x65, 0x61, 0x76, 0x65,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *AllocateExtentsRequest) GetMaxExtents() uint32 {
+	if x != nil {
+		return x.MaxExtents
+	}
+	return 0
 }
 
-var (
-	file_proto_spaceweave_proto_rawDescOnce sync.Once
-	file_proto_spaceweave_proto_rawDescData = file_proto_spaceweave_proto_rawDesc
-)
+func (x *AllocateExtentsRequest) GetMinExtentSize() uint64 {
+	if x != nil {
+		return x.MinExtentSize
+	}
+	return 0
+}
 
-func file_proto_spaceweave_proto_rawDescGZIP() []byte {
-	file_proto_spaceweave_proto_rawDescOnce.Do(func() {
-		file_proto_spaceweave_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_spaceweave_proto_rawDescData)
-	})
-	return file_proto_spaceweave_proto_rawDescData
+func (x *AllocateExtentsRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
 }
 
-var file_proto_spaceweave_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_proto_spaceweave_proto_goTypes = []interface{}{
-	(*AllocateRequest)(nil),            // 0: diskalloc.AllocateRequest
-	(*AllocateResponse)(nil),           // 1: diskalloc.AllocateResponse
-	(*FreeRequest)(nil),                // 2: diskalloc.FreeRequest
-	(*FreeResponse)(nil),               // 3: diskalloc.FreeResponse
-	(*GetDiskUtilizationRequest)(nil),  // 4: diskalloc.GetDiskUtilizationRequest
-	(*GetDiskUtilizationResponse)(nil), // 5: diskalloc.GetDiskUtilizationResponse
+func (x *AllocateExtentsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
 }
-var file_proto_spaceweave_proto_depIdxs = []int32{
-	0, // 0: diskalloc.DiskAllocator.Allocate:input_type -> diskalloc.AllocateRequest
-	2, // 1: diskalloc.DiskAllocator.Free:input_type -> diskalloc.FreeRequest
-	4, // 2: diskalloc.DiskAllocator.GetDiskUtilization:input_type -> diskalloc.GetDiskUtilizationRequest
-	1, // 3: diskalloc.DiskAllocator.Allocate:output_type -> diskalloc.AllocateResponse
-	3, // 4: diskalloc.DiskAllocator.Free:output_type -> diskalloc.FreeResponse
-	5, // 5: diskalloc.DiskAllocator.GetDiskUtilization:output_type -> diskalloc.GetDiskUtilizationResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+type AllocateExtentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Extents []*Extent `protobuf:"bytes,1,rep,name=extents,proto3" json:"extents,omitempty"`
 }
 
-func init() { file_proto_spaceweave_proto_init() }
-func file_proto_spaceweave_proto_init() {
-	if File_proto_spaceweave_proto != nil {
-		return
+func (x *AllocateExtentsResponse) Reset() {
+	*x = AllocateExtentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_spaceweave_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AllocateRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_spaceweave_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AllocateResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_proto_spaceweave_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FreeRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+}
+
+func (x *AllocateExtentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocateExtentsResponse) ProtoMessage() {}
+
+func (x *AllocateExtentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_proto_spaceweave_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FreeResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocateExtentsResponse.ProtoReflect.Descriptor instead.
+func (*AllocateExtentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AllocateExtentsResponse) GetExtents() []*Extent {
+	if x != nil {
+		return x.Extents
+	}
+	return nil
+}
+
+type FreeExtentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Extents []*Extent `protobuf:"bytes,1,rep,name=extents,proto3" json:"extents,omitempty"`
+	PoolId  string    `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *FreeExtentsRequest) Reset() {
+	*x = FreeExtentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FreeExtentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeExtentsRequest) ProtoMessage() {}
+
+func (x *FreeExtentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_proto_spaceweave_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeExtentsRequest.ProtoReflect.Descriptor instead.
+func (*FreeExtentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FreeExtentsRequest) GetExtents() []*Extent {
+	if x != nil {
+		return x.Extents
+	}
+	return nil
+}
+
+func (x *FreeExtentsRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type FreeExtentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FreeExtentsResponse) Reset() {
+	*x = FreeExtentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FreeExtentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeExtentsResponse) ProtoMessage() {}
+
+func (x *FreeExtentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeExtentsResponse.ProtoReflect.Descriptor instead.
+func (*FreeExtentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{8}
+}
+
+// ItemStatus 描述批量请求中单个条目的结果，code 为 gRPC 状态码，0 表示成功
+type ItemStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ItemStatus) Reset() {
+	*x = ItemStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ItemStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemStatus) ProtoMessage() {}
+
+func (x *ItemStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemStatus.ProtoReflect.Descriptor instead.
+func (*ItemStatus) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ItemStatus) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ItemStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchAllocateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sizes    []uint64 `protobuf:"varint,1,rep,packed,name=sizes,proto3" json:"sizes,omitempty"`
+	PoolId   string   `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	TenantId string   `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+}
+
+func (x *BatchAllocateRequest) Reset() {
+	*x = BatchAllocateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchAllocateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAllocateRequest) ProtoMessage() {}
+
+func (x *BatchAllocateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAllocateRequest.ProtoReflect.Descriptor instead.
+func (*BatchAllocateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchAllocateRequest) GetSizes() []uint64 {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+func (x *BatchAllocateRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *BatchAllocateRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type BatchAllocateResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address uint64      `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Status  *ItemStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *BatchAllocateResult) Reset() {
+	*x = BatchAllocateResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchAllocateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAllocateResult) ProtoMessage() {}
+
+func (x *BatchAllocateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAllocateResult.ProtoReflect.Descriptor instead.
+func (*BatchAllocateResult) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchAllocateResult) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *BatchAllocateResult) GetStatus() *ItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type BatchAllocateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*BatchAllocateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchAllocateResponse) Reset() {
+	*x = BatchAllocateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchAllocateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAllocateResponse) ProtoMessage() {}
+
+func (x *BatchAllocateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAllocateResponse.ProtoReflect.Descriptor instead.
+func (*BatchAllocateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchAllocateResponse) GetResults() []*BatchAllocateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchFreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Extents []*Extent `protobuf:"bytes,1,rep,name=extents,proto3" json:"extents,omitempty"`
+	PoolId  string    `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *BatchFreeRequest) Reset() {
+	*x = BatchFreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchFreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFreeRequest) ProtoMessage() {}
+
+func (x *BatchFreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFreeRequest.ProtoReflect.Descriptor instead.
+func (*BatchFreeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchFreeRequest) GetExtents() []*Extent {
+	if x != nil {
+		return x.Extents
+	}
+	return nil
+}
+
+func (x *BatchFreeRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type BatchFreeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*ItemStatus `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchFreeResponse) Reset() {
+	*x = BatchFreeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchFreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFreeResponse) ProtoMessage() {}
+
+func (x *BatchFreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFreeResponse.ProtoReflect.Descriptor instead.
+func (*BatchFreeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchFreeResponse) GetResults() []*ItemStatus {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// StreamRequest 是 AllocateStream 上的一条命令，id 由客户端生成，用于匹配响应
+type StreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are assignable to Command:
+	//	*StreamRequest_Allocate
+	//	*StreamRequest_Free
+	Command isStreamRequest_Command `protobuf_oneof:"command"`
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StreamRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (m *StreamRequest) GetCommand() isStreamRequest_Command {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetAllocate() *AllocateRequest {
+	if x, ok := x.GetCommand().(*StreamRequest_Allocate); ok {
+		return x.Allocate
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetFree() *FreeRequest {
+	if x, ok := x.GetCommand().(*StreamRequest_Free); ok {
+		return x.Free
+	}
+	return nil
+}
+
+type isStreamRequest_Command interface {
+	isStreamRequest_Command()
+}
+
+type StreamRequest_Allocate struct {
+	Allocate *AllocateRequest `protobuf:"bytes,2,opt,name=allocate,proto3,oneof"`
+}
+
+type StreamRequest_Free struct {
+	Free *FreeRequest `protobuf:"bytes,3,opt,name=free,proto3,oneof"`
+}
+
+func (*StreamRequest_Allocate) isStreamRequest_Command() {}
+
+func (*StreamRequest_Free) isStreamRequest_Command() {}
+
+type StreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      uint64      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status  *ItemStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Address uint64      `protobuf:"varint,3,opt,name=address,proto3" json:"address,omitempty"` // 仅 allocate 命令有效
+}
+
+func (x *StreamResponse) Reset() {
+	*x = StreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResponse) ProtoMessage() {}
+
+func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
+func (*StreamResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StreamResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StreamResponse) GetStatus() *ItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+type ReserveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Size    uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	PoolId  string `protobuf:"bytes,3,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *ReserveRequest) Reset() {
+	*x = ReserveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveRequest) ProtoMessage() {}
+
+func (x *ReserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveRequest.ProtoReflect.Descriptor instead.
+func (*ReserveRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReserveRequest) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *ReserveRequest) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ReserveRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type ReserveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReserveResponse) Reset() {
+	*x = ReserveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReserveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveResponse) ProtoMessage() {}
+
+func (x *ReserveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveResponse.ProtoReflect.Descriptor instead.
+func (*ReserveResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{18}
+}
+
+type ResizeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address  uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	OldSize  uint64 `protobuf:"varint,2,opt,name=old_size,json=oldSize,proto3" json:"old_size,omitempty"`
+	NewSize  uint64 `protobuf:"varint,3,opt,name=new_size,json=newSize,proto3" json:"new_size,omitempty"`
+	PoolId   string `protobuf:"bytes,4,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	TenantId string `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+}
+
+func (x *ResizeRequest) Reset() {
+	*x = ResizeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResizeRequest) ProtoMessage() {}
+
+func (x *ResizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResizeRequest.ProtoReflect.Descriptor instead.
+func (*ResizeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResizeRequest) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *ResizeRequest) GetOldSize() uint64 {
+	if x != nil {
+		return x.OldSize
+	}
+	return 0
+}
+
+func (x *ResizeRequest) GetNewSize() uint64 {
+	if x != nil {
+		return x.NewSize
+	}
+	return 0
+}
+
+func (x *ResizeRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *ResizeRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type ResizeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Moved   bool   `protobuf:"varint,2,opt,name=moved,proto3" json:"moved,omitempty"` // true 表示无法原地扩展，address 为新分配的空间，旧空间需由调用方释放
+}
+
+func (x *ResizeResponse) Reset() {
+	*x = ResizeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResizeResponse) ProtoMessage() {}
+
+func (x *ResizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResizeResponse.ProtoReflect.Descriptor instead.
+func (*ResizeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ResizeResponse) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *ResizeResponse) GetMoved() bool {
+	if x != nil {
+		return x.Moved
+	}
+	return false
+}
+
+// OpenSessionRequest 打开一个会话，会话在返回的流保持打开期间持续续期，
+// 流结束（客户端关闭或连接断开）时会话关闭并释放所有未提交的分配
+type OpenSessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TtlSec    uint32 `protobuf:"varint,1,opt,name=ttl_sec,json=ttlSec,proto3" json:"ttl_sec,omitempty"`          // 服务端收不到续期时会话保留的时间（例如服务重启后），0 使用服务端默认值
+	SessionId uint64 `protobuf:"varint,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // 非 0 时在服务重启后重新绑定已有会话
+	PoolId    string `protobuf:"bytes,3,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *OpenSessionRequest) Reset() {
+	*x = OpenSessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenSessionRequest) ProtoMessage() {}
+
+func (x *OpenSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenSessionRequest.ProtoReflect.Descriptor instead.
+func (*OpenSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *OpenSessionRequest) GetTtlSec() uint32 {
+	if x != nil {
+		return x.TtlSec
+	}
+	return 0
+}
+
+func (x *OpenSessionRequest) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *OpenSessionRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type SessionEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId uint64 `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SessionEvent) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+type CommitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId uint64 `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Address   uint64 `protobuf:"varint,2,opt,name=address,proto3" json:"address,omitempty"`
+	Size      uint64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	PoolId    string `protobuf:"bytes,4,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *CommitRequest) Reset() {
+	*x = CommitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRequest) ProtoMessage() {}
+
+func (x *CommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRequest.ProtoReflect.Descriptor instead.
+func (*CommitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CommitRequest) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *CommitRequest) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *CommitRequest) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *CommitRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type CommitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CommitResponse) Reset() {
+	*x = CommitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitResponse) ProtoMessage() {}
+
+func (x *CommitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitResponse.ProtoReflect.Descriptor instead.
+func (*CommitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{24}
+}
+
+type ExtendTTLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	TtlSec  uint32 `protobuf:"varint,2,opt,name=ttl_sec,json=ttlSec,proto3" json:"ttl_sec,omitempty"` // 新的过期时间为从现在起 ttl_sec 秒后
+	PoolId  string `protobuf:"bytes,3,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *ExtendTTLRequest) Reset() {
+	*x = ExtendTTLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendTTLRequest) ProtoMessage() {}
+
+func (x *ExtendTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendTTLRequest.ProtoReflect.Descriptor instead.
+func (*ExtendTTLRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExtendTTLRequest) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *ExtendTTLRequest) GetTtlSec() uint32 {
+	if x != nil {
+		return x.TtlSec
+	}
+	return 0
+}
+
+func (x *ExtendTTLRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type ExtendTTLResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ExtendTTLResponse) Reset() {
+	*x = ExtendTTLResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendTTLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendTTLResponse) ProtoMessage() {}
+
+func (x *ExtendTTLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendTTLResponse.ProtoReflect.Descriptor instead.
+func (*ExtendTTLResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{26}
+}
+
+type GetDiskUtilizationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId string `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *GetDiskUtilizationRequest) Reset() {
+	*x = GetDiskUtilizationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDiskUtilizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiskUtilizationRequest) ProtoMessage() {}
+
+func (x *GetDiskUtilizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiskUtilizationRequest.ProtoReflect.Descriptor instead.
+func (*GetDiskUtilizationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetDiskUtilizationRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type GetDiskUtilizationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Utilization              float32 `protobuf:"fixed32,1,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	TtlTracked               uint64  `protobuf:"varint,2,opt,name=ttl_tracked,json=ttlTracked,proto3" json:"ttl_tracked,omitempty"`
+	TtlReclaimedExtents      uint64  `protobuf:"varint,3,opt,name=ttl_reclaimed_extents,json=ttlReclaimedExtents,proto3" json:"ttl_reclaimed_extents,omitempty"`
+	TtlReclaimedBytes        uint64  `protobuf:"varint,4,opt,name=ttl_reclaimed_bytes,json=ttlReclaimedBytes,proto3" json:"ttl_reclaimed_bytes,omitempty"`
+	VerifyRuns               uint64  `protobuf:"varint,5,opt,name=verify_runs,json=verifyRuns,proto3" json:"verify_runs,omitempty"`                                              // 在线一致性检查的次数
+	VerifyFailures           uint64  `protobuf:"varint,6,opt,name=verify_failures,json=verifyFailures,proto3" json:"verify_failures,omitempty"`                                  // 发现问题的检查次数
+	VerifyRepairs            uint64  `protobuf:"varint,7,opt,name=verify_repairs,json=verifyRepairs,proto3" json:"verify_repairs,omitempty"`                                     // 重建 treeBySize 的次数
+	TotalSize                uint64  `protobuf:"varint,8,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`                                                 // 当前管理的字节数，包括在线扩容的部分
+	ReservedBytes            uint64  `protobuf:"varint,9,opt,name=reserved_bytes,json=reservedBytes,proto3" json:"reserved_bytes,omitempty"`                                     // 全部租户的预留之和
+	OutstandingReservedBytes uint64  `protobuf:"varint,10,opt,name=outstanding_reserved_bytes,json=outstandingReservedBytes,proto3" json:"outstanding_reserved_bytes,omitempty"` // 尚未被租户自身的分配用掉的预留，其他分配不能占用
+	UnreservedFreeBytes      uint64  `protobuf:"varint,11,opt,name=unreserved_free_bytes,json=unreservedFreeBytes,proto3" json:"unreserved_free_bytes,omitempty"`                // 扣除剩余预留后一般分配可用的空闲字节数
+}
+
+func (x *GetDiskUtilizationResponse) Reset() {
+	*x = GetDiskUtilizationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDiskUtilizationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiskUtilizationResponse) ProtoMessage() {}
+
+func (x *GetDiskUtilizationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiskUtilizationResponse.ProtoReflect.Descriptor instead.
+func (*GetDiskUtilizationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetDiskUtilizationResponse) GetUtilization() float32 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetTtlTracked() uint64 {
+	if x != nil {
+		return x.TtlTracked
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetTtlReclaimedExtents() uint64 {
+	if x != nil {
+		return x.TtlReclaimedExtents
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetTtlReclaimedBytes() uint64 {
+	if x != nil {
+		return x.TtlReclaimedBytes
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetVerifyRuns() uint64 {
+	if x != nil {
+		return x.VerifyRuns
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetVerifyFailures() uint64 {
+	if x != nil {
+		return x.VerifyFailures
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetVerifyRepairs() uint64 {
+	if x != nil {
+		return x.VerifyRepairs
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetReservedBytes() uint64 {
+	if x != nil {
+		return x.ReservedBytes
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetOutstandingReservedBytes() uint64 {
+	if x != nil {
+		return x.OutstandingReservedBytes
+	}
+	return 0
+}
+
+func (x *GetDiskUtilizationResponse) GetUnreservedFreeBytes() uint64 {
+	if x != nil {
+		return x.UnreservedFreeBytes
+	}
+	return 0
+}
+
+type VerifyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Repair bool   `protobuf:"varint,1,opt,name=repair,proto3" json:"repair,omitempty"` // 发现索引不一致时从 treeByStart 重建 treeBySize
+	PoolId string `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *VerifyRequest) GetRepair() bool {
+	if x != nil {
+		return x.Repair
+	}
+	return false
+}
+
+func (x *VerifyRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type VerifyProblem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind   string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Detail string `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"` // 地址和大小以单元为单位
+}
+
+func (x *VerifyProblem) Reset() {
+	*x = VerifyProblem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyProblem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyProblem) ProtoMessage() {}
+
+func (x *VerifyProblem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyProblem.ProtoReflect.Descriptor instead.
+func (*VerifyProblem) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *VerifyProblem) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *VerifyProblem) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Problems        []*VerifyProblem `protobuf:"bytes,1,rep,name=problems,proto3" json:"problems,omitempty"`
+	FreeBlocks      uint64           `protobuf:"varint,2,opt,name=free_blocks,json=freeBlocks,proto3" json:"free_blocks,omitempty"`
+	TreeFreeUnits   uint64           `protobuf:"varint,3,opt,name=tree_free_units,json=treeFreeUnits,proto3" json:"tree_free_units,omitempty"`
+	BitmapFreeUnits uint64           `protobuf:"varint,4,opt,name=bitmap_free_units,json=bitmapFreeUnits,proto3" json:"bitmap_free_units,omitempty"`
+	Utilization     float32          `protobuf:"fixed32,5,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	Repaired        bool             `protobuf:"varint,6,opt,name=repaired,proto3" json:"repaired,omitempty"`
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *VerifyResponse) GetProblems() []*VerifyProblem {
+	if x != nil {
+		return x.Problems
+	}
+	return nil
+}
+
+func (x *VerifyResponse) GetFreeBlocks() uint64 {
+	if x != nil {
+		return x.FreeBlocks
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetTreeFreeUnits() uint64 {
+	if x != nil {
+		return x.TreeFreeUnits
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetBitmapFreeUnits() uint64 {
+	if x != nil {
+		return x.BitmapFreeUnits
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetUtilization() float32 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetRepaired() bool {
+	if x != nil {
+		return x.Repaired
+	}
+	return false
+}
+
+// MapExtent 是分配图中的一段区间，地址和大小以字节为单位
+type MapExtent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address   uint64 `protobuf:"varint,1,opt,name=address,proto3" json:"address,omitempty"`
+	Size      uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Region    Region `protobuf:"varint,3,opt,name=region,proto3,enum=diskalloc.Region" json:"region,omitempty"`
+	Allocated bool   `protobuf:"varint,4,opt,name=allocated,proto3" json:"allocated,omitempty"`
+}
+
+func (x *MapExtent) Reset() {
+	*x = MapExtent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MapExtent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapExtent) ProtoMessage() {}
+
+func (x *MapExtent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapExtent.ProtoReflect.Descriptor instead.
+func (*MapExtent) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *MapExtent) GetAddress() uint64 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+func (x *MapExtent) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *MapExtent) GetRegion() Region {
+	if x != nil {
+		return x.Region
+	}
+	return Region_BITMAP
+}
+
+func (x *MapExtent) GetAllocated() bool {
+	if x != nil {
+		return x.Allocated
+	}
+	return false
+}
+
+// AllocationMap 按地址顺序列出全部已分配和空闲的区间
+type AllocationMap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnitSize  uint64       `protobuf:"varint,1,opt,name=unit_size,json=unitSize,proto3" json:"unit_size,omitempty"`    // 为 0 时导入不检查
+	TotalSize uint64       `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"` // 为 0 时导入不检查
+	Extents   []*MapExtent `protobuf:"bytes,3,rep,name=extents,proto3" json:"extents,omitempty"`
+}
+
+func (x *AllocationMap) Reset() {
+	*x = AllocationMap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllocationMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocationMap) ProtoMessage() {}
+
+func (x *AllocationMap) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocationMap.ProtoReflect.Descriptor instead.
+func (*AllocationMap) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AllocationMap) GetUnitSize() uint64 {
+	if x != nil {
+		return x.UnitSize
+	}
+	return 0
+}
+
+func (x *AllocationMap) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *AllocationMap) GetExtents() []*MapExtent {
+	if x != nil {
+		return x.Extents
+	}
+	return nil
+}
+
+type ExportMapRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId string `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *ExportMapRequest) Reset() {
+	*x = ExportMapRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportMapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMapRequest) ProtoMessage() {}
+
+func (x *ExportMapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMapRequest.ProtoReflect.Descriptor instead.
+func (*ExportMapRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ExportMapRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type ExportMapResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Map *AllocationMap `protobuf:"bytes,1,opt,name=map,proto3" json:"map,omitempty"`
+}
+
+func (x *ExportMapResponse) Reset() {
+	*x = ExportMapResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportMapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMapResponse) ProtoMessage() {}
+
+func (x *ExportMapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMapResponse.ProtoReflect.Descriptor instead.
+func (*ExportMapResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExportMapResponse) GetMap() *AllocationMap {
+	if x != nil {
+		return x.Map
+	}
+	return nil
+}
+
+type ImportMapRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Map    *AllocationMap `protobuf:"bytes,1,opt,name=map,proto3" json:"map,omitempty"`
+	PoolId string         `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *ImportMapRequest) Reset() {
+	*x = ImportMapRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportMapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportMapRequest) ProtoMessage() {}
+
+func (x *ImportMapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportMapRequest.ProtoReflect.Descriptor instead.
+func (*ImportMapRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ImportMapRequest) GetMap() *AllocationMap {
+	if x != nil {
+		return x.Map
+	}
+	return nil
+}
+
+func (x *ImportMapRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type ImportMapResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Allocations uint64 `protobuf:"varint,1,opt,name=allocations,proto3" json:"allocations,omitempty"` // 导入的已分配区间个数
+}
+
+func (x *ImportMapResponse) Reset() {
+	*x = ImportMapResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportMapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportMapResponse) ProtoMessage() {}
+
+func (x *ImportMapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportMapResponse.ProtoReflect.Descriptor instead.
+func (*ImportMapResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ImportMapResponse) GetAllocations() uint64 {
+	if x != nil {
+		return x.Allocations
+	}
+	return 0
+}
+
+type GrowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalSize uint64 `protobuf:"varint,1,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"` // 扩容后的总字节数，必须是 UnitSize 的整数倍且大于当前大小
+	PoolId    string `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *GrowRequest) Reset() {
+	*x = GrowRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrowRequest) ProtoMessage() {}
+
+func (x *GrowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrowRequest.ProtoReflect.Descriptor instead.
+func (*GrowRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GrowRequest) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GrowRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type GrowResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalSize uint64 `protobuf:"varint,1,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+}
+
+func (x *GrowResponse) Reset() {
+	*x = GrowResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GrowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrowResponse) ProtoMessage() {}
+
+func (x *GrowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrowResponse.ProtoReflect.Descriptor instead.
+func (*GrowResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GrowResponse) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type ShrinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalSize uint64 `protobuf:"varint,1,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"` // 收缩后的总字节数，必须是 UnitSize 的整数倍且不大于当前大小，等于当前大小时取消未完成的收缩
+	PoolId    string `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+}
+
+func (x *ShrinkRequest) Reset() {
+	*x = ShrinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShrinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShrinkRequest) ProtoMessage() {}
+
+func (x *ShrinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShrinkRequest.ProtoReflect.Descriptor instead.
+func (*ShrinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ShrinkRequest) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *ShrinkRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+type ShrinkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Done      bool      `protobuf:"varint,1,opt,name=done,proto3" json:"done,omitempty"`                            // 为 false 时末尾仍有存活的分配，迁移 relocate 中的区间后再次调用
+	Relocate  []*Extent `protobuf:"bytes,2,rep,name=relocate,proto3" json:"relocate,omitempty"`                     // 需要迁移的存活分配
+	TotalSize uint64    `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"` // 当前管理的字节数
+}
+
+func (x *ShrinkResponse) Reset() {
+	*x = ShrinkResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShrinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShrinkResponse) ProtoMessage() {}
+
+func (x *ShrinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShrinkResponse.ProtoReflect.Descriptor instead.
+func (*ShrinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ShrinkResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ShrinkResponse) GetRelocate() []*Extent {
+	if x != nil {
+		return x.Relocate
+	}
+	return nil
+}
+
+func (x *ShrinkResponse) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+// PoolInfo 描述一个存储池，每个存储池是独立的地址空间
+type PoolInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId               string  `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	UnitSize             uint64  `protobuf:"varint,2,opt,name=unit_size,json=unitSize,proto3" json:"unit_size,omitempty"`
+	TotalSize            uint64  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`                                   // 当前管理的字节数，包括在线扩容和收缩
+	StatePersistencePath string  `protobuf:"bytes,4,opt,name=state_persistence_path,json=statePersistencePath,proto3" json:"state_persistence_path,omitempty"` // 为空表示不持久化
+	Utilization          float32 `protobuf:"fixed32,5,opt,name=utilization,proto3" json:"utilization,omitempty"`
+}
+
+func (x *PoolInfo) Reset() {
+	*x = PoolInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PoolInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolInfo) ProtoMessage() {}
+
+func (x *PoolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolInfo.ProtoReflect.Descriptor instead.
+func (*PoolInfo) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PoolInfo) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetUnitSize() uint64 {
+	if x != nil {
+		return x.UnitSize
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetStatePersistencePath() string {
+	if x != nil {
+		return x.StatePersistencePath
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetUtilization() float32 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+type CreatePoolRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId               string `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`        // 由字母、数字、'.'、'_'、'-' 组成，最长 64 个字符
+	UnitSize             uint64 `protobuf:"varint,2,opt,name=unit_size,json=unitSize,proto3" json:"unit_size,omitempty"` // 0 使用服务端配置的 UNIT_SIZE
+	TotalSize            uint64 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	StatePersistencePath string `protobuf:"bytes,4,opt,name=state_persistence_path,json=statePersistencePath,proto3" json:"state_persistence_path,omitempty"` // 为空时使用 STATE_PERSISTENCE_PATH + "." + pool_id
+}
+
+func (x *CreatePoolRequest) Reset() {
+	*x = CreatePoolRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePoolRequest) ProtoMessage() {}
+
+func (x *CreatePoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePoolRequest.ProtoReflect.Descriptor instead.
+func (*CreatePoolRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CreatePoolRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *CreatePoolRequest) GetUnitSize() uint64 {
+	if x != nil {
+		return x.UnitSize
+	}
+	return 0
+}
+
+func (x *CreatePoolRequest) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *CreatePoolRequest) GetStatePersistencePath() string {
+	if x != nil {
+		return x.StatePersistencePath
+	}
+	return ""
+}
+
+type CreatePoolResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool *PoolInfo `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+}
+
+func (x *CreatePoolResponse) Reset() {
+	*x = CreatePoolResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePoolResponse) ProtoMessage() {}
+
+func (x *CreatePoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePoolResponse.ProtoReflect.Descriptor instead.
+func (*CreatePoolResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CreatePoolResponse) GetPool() *PoolInfo {
+	if x != nil {
+		return x.Pool
+	}
+	return nil
+}
+
+type DeletePoolRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId string `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	Force  bool   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"` // 存储池中仍有已分配的空间时也删除
+}
+
+func (x *DeletePoolRequest) Reset() {
+	*x = DeletePoolRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePoolRequest) ProtoMessage() {}
+
+func (x *DeletePoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePoolRequest.ProtoReflect.Descriptor instead.
+func (*DeletePoolRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DeletePoolRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *DeletePoolRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type DeletePoolResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeletePoolResponse) Reset() {
+	*x = DeletePoolResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePoolResponse) ProtoMessage() {}
+
+func (x *DeletePoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePoolResponse.ProtoReflect.Descriptor instead.
+func (*DeletePoolResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{46}
+}
+
+type ListPoolsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListPoolsRequest) Reset() {
+	*x = ListPoolsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPoolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolsRequest) ProtoMessage() {}
+
+func (x *ListPoolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolsRequest.ProtoReflect.Descriptor instead.
+func (*ListPoolsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{47}
+}
+
+type ListPoolsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pools []*PoolInfo `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
+}
+
+func (x *ListPoolsResponse) Reset() {
+	*x = ListPoolsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPoolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolsResponse) ProtoMessage() {}
+
+func (x *ListPoolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolsResponse.ProtoReflect.Descriptor instead.
+func (*ListPoolsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListPoolsResponse) GetPools() []*PoolInfo {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+// TenantUsage 是租户在一个存储池中的配额和用量，单位为字节，配额为 0 表示不限
+type TenantUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TenantId  string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Used      uint64 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	SoftLimit uint64 `protobuf:"varint,3,opt,name=soft_limit,json=softLimit,proto3" json:"soft_limit,omitempty"` // 超过后分配仍然成功，AllocateResponse.over_soft_quota 为 true
+	HardLimit uint64 `protobuf:"varint,4,opt,name=hard_limit,json=hardLimit,proto3" json:"hard_limit,omitempty"` // 超过后分配返回 RESOURCE_EXHAUSTED
+	Reserved  uint64 `protobuf:"varint,5,opt,name=reserved,proto3" json:"reserved,omitempty"`                    // 为租户预留的空间，不指定地址，其他租户的分配不能占用尚未用掉的部分
+}
+
+func (x *TenantUsage) Reset() {
+	*x = TenantUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TenantUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantUsage) ProtoMessage() {}
+
+func (x *TenantUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantUsage.ProtoReflect.Descriptor instead.
+func (*TenantUsage) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TenantUsage) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *TenantUsage) GetUsed() uint64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *TenantUsage) GetSoftLimit() uint64 {
+	if x != nil {
+		return x.SoftLimit
+	}
+	return 0
+}
+
+func (x *TenantUsage) GetHardLimit() uint64 {
+	if x != nil {
+		return x.HardLimit
+	}
+	return 0
+}
+
+func (x *TenantUsage) GetReserved() uint64 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+type SetTenantQuotaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId    string `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	TenantId  string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	SoftLimit uint64 `protobuf:"varint,3,opt,name=soft_limit,json=softLimit,proto3" json:"soft_limit,omitempty"` // 软硬配额和预留都为 0 时删除配额
+	HardLimit uint64 `protobuf:"varint,4,opt,name=hard_limit,json=hardLimit,proto3" json:"hard_limit,omitempty"`
+	Reserved  uint64 `protobuf:"varint,5,opt,name=reserved,proto3" json:"reserved,omitempty"` // 增加预留时空闲空间不足以保证全部预留返回 RESOURCE_EXHAUSTED
+}
+
+func (x *SetTenantQuotaRequest) Reset() {
+	*x = SetTenantQuotaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTenantQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTenantQuotaRequest) ProtoMessage() {}
+
+func (x *SetTenantQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTenantQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetTenantQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SetTenantQuotaRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *SetTenantQuotaRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetTenantQuotaRequest) GetSoftLimit() uint64 {
+	if x != nil {
+		return x.SoftLimit
+	}
+	return 0
+}
+
+func (x *SetTenantQuotaRequest) GetHardLimit() uint64 {
+	if x != nil {
+		return x.HardLimit
+	}
+	return 0
+}
+
+func (x *SetTenantQuotaRequest) GetReserved() uint64 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+type SetTenantQuotaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tenant *TenantUsage `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+}
+
+func (x *SetTenantQuotaResponse) Reset() {
+	*x = SetTenantQuotaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTenantQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTenantQuotaResponse) ProtoMessage() {}
+
+func (x *SetTenantQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTenantQuotaResponse.ProtoReflect.Descriptor instead.
+func (*SetTenantQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SetTenantQuotaResponse) GetTenant() *TenantUsage {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+type GetTenantUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId   string `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"` // 为空时返回全部有配额或有用量的租户
+}
+
+func (x *GetTenantUsageRequest) Reset() {
+	*x = GetTenantUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTenantUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantUsageRequest) ProtoMessage() {}
+
+func (x *GetTenantUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantUsageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetTenantUsageRequest) GetPoolId() string {
+	if x != nil {
+		return x.PoolId
+	}
+	return ""
+}
+
+func (x *GetTenantUsageRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type GetTenantUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tenants []*TenantUsage `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+}
+
+func (x *GetTenantUsageResponse) Reset() {
+	*x = GetTenantUsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_spaceweave_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTenantUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantUsageResponse) ProtoMessage() {}
+
+func (x *GetTenantUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_spaceweave_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetTenantUsageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_spaceweave_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetTenantUsageResponse) GetTenants() []*TenantUsage {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+var File_proto_spaceweave_proto protoreflect.FileDescriptor
+
+var file_proto_spaceweave_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x77, 0x65, 0x61,
+	0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x22, 0xfd, 0x01, 0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x1c, 0x0a, 0x09, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07,
+	0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74,
+	0x74, 0x6c, 0x53, 0x65, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x69, 0x65, 0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x74, 0x69, 0x65, 0x72, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x74, 0x49, 0x64, 0x22, 0x68, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x69, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0f, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x73, 0x6f,
+	0x66, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d,
+	0x6f, 0x76, 0x65, 0x72, 0x53, 0x6f, 0x66, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x22, 0x54, 0x0a,
+	0x0b, 0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f,
+	0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f,
+	0x6c, 0x49, 0x64, 0x22, 0x0e, 0x0a, 0x0c, 0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x36, 0x0a, 0x06, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0xab, 0x01, 0x0a, 0x16,
+	0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61,
+	0x78, 0x5f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x6d, 0x61, 0x78, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6d,
+	0x69, 0x6e, 0x5f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x6d, 0x69, 0x6e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x46, 0x0a, 0x17, 0x41, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74,
+	0x73, 0x22, 0x5a, 0x0a, 0x12, 0x46, 0x72, 0x65, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x65, 0x78, 0x74,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0x15, 0x0a,
+	0x13, 0x46, 0x72, 0x65, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3a, 0x0a, 0x0a, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x62, 0x0a, 0x14, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x7a, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x05, 0x73, 0x69, 0x7a, 0x65, 0x73, 0x12, 0x17,
+	0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6e, 0x61,
+	0x6e, 0x74, 0x49, 0x64, 0x22, 0x5e, 0x0a, 0x13, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x51, 0x0a, 0x15, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x58, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x07, 0x65,
+	0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x52,
+	0x07, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49,
+	0x64, 0x22, 0x44, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x72, 0x65, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x92, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x38, 0x0a, 0x08, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x66, 0x72, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x46, 0x72,
+	0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x04, 0x66, 0x72, 0x65,
+	0x65, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x69, 0x0a, 0x0e,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2d,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x57, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64,
+	0x22, 0x11, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x95, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x19, 0x0a, 0x08, 0x6f, 0x6c, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x6f, 0x6c, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65,
+	0x77, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6e, 0x65,
+	0x77, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x40, 0x0a, 0x0e, 0x52,
+	0x65, 0x73, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0x65, 0x0a,
+	0x12, 0x4f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x74, 0x6c, 0x53, 0x65, 0x63, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x70,
+	0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f,
+	0x6f, 0x6c, 0x49, 0x64, 0x22, 0x2d, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x22, 0x75, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0x10, 0x0a, 0x0e, 0x43, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x5e, 0x0a, 0x10,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x74,
+	0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x74, 0x6c,
+	0x53, 0x65, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0x13, 0x0a, 0x11,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x34, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x74, 0x69, 0x6c,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0xec, 0x03, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x44,
+	0x69, 0x73, 0x6b, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x75, 0x74, 0x69,
+	0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x74, 0x6c, 0x5f,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x74,
+	0x74, 0x6c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x15, 0x74, 0x74, 0x6c,
+	0x5f, 0x72, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x5f, 0x65, 0x78, 0x74, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x74, 0x74, 0x6c, 0x52, 0x65, 0x63,
+	0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e, 0x0a,
+	0x13, 0x74, 0x74, 0x6c, 0x5f, 0x72, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x74, 0x74, 0x6c, 0x52,
+	0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x75, 0x6e, 0x73, 0x12, 0x27,
+	0x0a, 0x0f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x46,
+	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x76, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x5f, 0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0d, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x3c, 0x0a, 0x1a, 0x6f, 0x75, 0x74, 0x73, 0x74, 0x61, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x18, 0x6f, 0x75, 0x74, 0x73, 0x74, 0x61,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x75, 0x6e, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64,
+	0x5f, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x13, 0x75, 0x6e, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x46, 0x72, 0x65,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x40, 0x0a, 0x0d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x61, 0x69,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x12,
+	0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0x3b, 0x0a, 0x0d, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x22, 0xf9, 0x01, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x62,
+	0x6c, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f,
+	0x62, 0x6c, 0x65, 0x6d, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12,
+	0x26, 0x0a, 0x0f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x75, 0x6e, 0x69,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x74, 0x72, 0x65, 0x65, 0x46, 0x72,
+	0x65, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x69, 0x74, 0x6d, 0x61,
+	0x70, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0f, 0x62, 0x69, 0x74, 0x6d, 0x61, 0x70, 0x46, 0x72, 0x65, 0x65, 0x55, 0x6e,
+	0x69, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65,
+	0x64, 0x22, 0x82, 0x01, 0x0a, 0x09, 0x4d, 0x61, 0x70, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x29, 0x0a,
+	0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x22, 0x7b, 0x0a, 0x0d, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x6e, 0x69, 0x74, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x74,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+	0x2e, 0x4d, 0x61, 0x70, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x65, 0x78, 0x74, 0x65,
+	0x6e, 0x74, 0x73, 0x22, 0x2b, 0x0a, 0x10, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64,
+	0x22, 0x3f, 0x0a, 0x11, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41,
+	0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x70, 0x52, 0x03, 0x6d, 0x61,
+	0x70, 0x22, 0x57, 0x0a, 0x10, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41,
+	0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x70, 0x52, 0x03, 0x6d, 0x61,
+	0x70, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0x35, 0x0a, 0x11, 0x49, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x45, 0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x22, 0x2d, 0x0a, 0x0c, 0x47, 0x72, 0x6f, 0x77,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x47, 0x0a, 0x0d, 0x53, 0x68, 0x72, 0x69, 0x6e,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64,
+	0x22, 0x72, 0x0a, 0x0e, 0x53, 0x68, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x72, 0x65, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x53, 0x69, 0x7a, 0x65, 0x22, 0xb7, 0x01, 0x0a, 0x08, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x6e,
+	0x69, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x75,
+	0x6e, 0x69, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x73, 0x74, 0x61, 0x74, 0x65, 0x50, 0x65, 0x72,
+	0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x20, 0x0a, 0x0b,
+	0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9e,
+	0x01, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x75, 0x6e, 0x69, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x50, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22,
+	0x3d, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e,
+	0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x22, 0x42,
+	0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72,
+	0x63, 0x65, 0x22, 0x14, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3e, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x29, 0x0a, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x50, 0x6f, 0x6f,
+	0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x22, 0x98, 0x01, 0x0a,
+	0x0b, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x6f, 0x66, 0x74, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x73, 0x6f, 0x66, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x68, 0x61, 0x72, 0x64, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x68, 0x61, 0x72, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x22, 0xa7, 0x01, 0x0a, 0x15, 0x53, 0x65, 0x74, 0x54,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x65,
+	0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x6f, 0x66, 0x74, 0x5f,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x6f, 0x66,
+	0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x68, 0x61, 0x72, 0x64,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x64, 0x22, 0x48, 0x0a, 0x16, 0x53, 0x65, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x51, 0x75,
+	0x6f, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x74,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0x4d, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x4a, 0x0a, 0x16, 0x47, 0x65,
+	0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x2e, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x74,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x2a, 0x5f, 0x0a, 0x0f, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x4f, 0x4c,
+	0x49, 0x43, 0x59, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x0c, 0x0a,
+	0x08, 0x42, 0x45, 0x53, 0x54, 0x5f, 0x46, 0x49, 0x54, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x46,
+	0x49, 0x52, 0x53, 0x54, 0x5f, 0x46, 0x49, 0x54, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x45,
+	0x58, 0x54, 0x5f, 0x46, 0x49, 0x54, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x57, 0x4f, 0x52, 0x53,
+	0x54, 0x5f, 0x46, 0x49, 0x54, 0x10, 0x04, 0x2a, 0x1e, 0x0a, 0x06, 0x52, 0x65, 0x67, 0x69, 0x6f,
+	0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x42, 0x49, 0x54, 0x4d, 0x41, 0x50, 0x10, 0x00, 0x12, 0x08, 0x0a,
+	0x04, 0x54, 0x52, 0x45, 0x45, 0x10, 0x01, 0x32, 0xd1, 0x0d, 0x0a, 0x0d, 0x44, 0x69, 0x73, 0x6b,
+	0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x45, 0x0a, 0x08, 0x41, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x39, 0x0a, 0x04, 0x46, 0x72, 0x65, 0x65, 0x12, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x17, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x46, 0x72, 0x65,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x0d, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41,
+	0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x48, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x72, 0x65, 0x65, 0x12, 0x1b,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x46, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x72, 0x65,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0e, 0x41,
+	0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x12, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0b,
+	0x4f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3f, 0x0a, 0x06, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x12, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x43, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x09, 0x45, 0x78, 0x74, 0x65,
+	0x6e, 0x64, 0x54, 0x54, 0x4c, 0x12, 0x1b, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x64, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x3f, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x69, 0x73, 0x6b,
+	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x45, 0x78,
+	0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4e, 0x0a, 0x0b, 0x46, 0x72, 0x65, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1d,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x45, 0x78,
+	0x74, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x63, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x69, 0x73, 0x6b, 0x55,
+	0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x18,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x09, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d,
+	0x61, 0x70, 0x12, 0x1b, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x48, 0x0a, 0x09, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x12, 0x1b, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d,
+	0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b,
+	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x04, 0x47, 0x72, 0x6f,
+	0x77, 0x12, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x47, 0x72,
+	0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x69, 0x73, 0x6b,
+	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x47, 0x72, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x06, 0x53, 0x68, 0x72, 0x69, 0x6e, 0x6b, 0x12, 0x18,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x68, 0x72, 0x69, 0x6e,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x68, 0x72, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50,
+	0x6f, 0x6f, 0x6c, 0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c,
+	0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x48, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x1b, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f,
+	0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b,
+	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x0e, 0x53, 0x65, 0x74,
+	0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x20, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e,
+	0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x54, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x57, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+	0x2e, 0x47, 0x65, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x38, 0x5a, 0x36, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x31, 0x32, 0x31, 0x33,
+	0x39, 0x38, 0x37, 0x38, 0x34, 0x32, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x77, 0x65, 0x61, 0x76,
+	0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x70, 0x61, 0x63, 0x65, 0x77, 0x65, 0x61, 0x76, 0x65,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_spaceweave_proto_rawDescOnce sync.Once
+	file_proto_spaceweave_proto_rawDescData = file_proto_spaceweave_proto_rawDesc
+)
+
+func file_proto_spaceweave_proto_rawDescGZIP() []byte {
+	file_proto_spaceweave_proto_rawDescOnce.Do(func() {
+		file_proto_spaceweave_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_spaceweave_proto_rawDescData)
+	})
+	return file_proto_spaceweave_proto_rawDescData
+}
+
+var file_proto_spaceweave_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_spaceweave_proto_msgTypes = make([]protoimpl.MessageInfo, 54)
+var file_proto_spaceweave_proto_goTypes = []interface{}{
+	(PlacementPolicy)(0),               // 0: diskalloc.PlacementPolicy
+	(Region)(0),                        // 1: diskalloc.Region
+	(*AllocateRequest)(nil),            // 2: diskalloc.AllocateRequest
+	(*AllocateResponse)(nil),           // 3: diskalloc.AllocateResponse
+	(*FreeRequest)(nil),                // 4: diskalloc.FreeRequest
+	(*FreeResponse)(nil),               // 5: diskalloc.FreeResponse
+	(*Extent)(nil),                     // 6: diskalloc.Extent
+	(*AllocateExtentsRequest)(nil),     // 7: diskalloc.AllocateExtentsRequest
+	(*AllocateExtentsResponse)(nil),    // 8: diskalloc.AllocateExtentsResponse
+	(*FreeExtentsRequest)(nil),         // 9: diskalloc.FreeExtentsRequest
+	(*FreeExtentsResponse)(nil),        // 10: diskalloc.FreeExtentsResponse
+	(*ItemStatus)(nil),                 // 11: diskalloc.ItemStatus
+	(*BatchAllocateRequest)(nil),       // 12: diskalloc.BatchAllocateRequest
+	(*BatchAllocateResult)(nil),        // 13: diskalloc.BatchAllocateResult
+	(*BatchAllocateResponse)(nil),      // 14: diskalloc.BatchAllocateResponse
+	(*BatchFreeRequest)(nil),           // 15: diskalloc.BatchFreeRequest
+	(*BatchFreeResponse)(nil),          // 16: diskalloc.BatchFreeResponse
+	(*StreamRequest)(nil),              // 17: diskalloc.StreamRequest
+	(*StreamResponse)(nil),             // 18: diskalloc.StreamResponse
+	(*ReserveRequest)(nil),             // 19: diskalloc.ReserveRequest
+	(*ReserveResponse)(nil),            // 20: diskalloc.ReserveResponse
+	(*ResizeRequest)(nil),              // 21: diskalloc.ResizeRequest
+	(*ResizeResponse)(nil),             // 22: diskalloc.ResizeResponse
+	(*OpenSessionRequest)(nil),         // 23: diskalloc.OpenSessionRequest
+	(*SessionEvent)(nil),               // 24: diskalloc.SessionEvent
+	(*CommitRequest)(nil),              // 25: diskalloc.CommitRequest
+	(*CommitResponse)(nil),             // 26: diskalloc.CommitResponse
+	(*ExtendTTLRequest)(nil),           // 27: diskalloc.ExtendTTLRequest
+	(*ExtendTTLResponse)(nil),          // 28: diskalloc.ExtendTTLResponse
+	(*GetDiskUtilizationRequest)(nil),  // 29: diskalloc.GetDiskUtilizationRequest
+	(*GetDiskUtilizationResponse)(nil), // 30: diskalloc.GetDiskUtilizationResponse
+	(*VerifyRequest)(nil),              // 31: diskalloc.VerifyRequest
+	(*VerifyProblem)(nil),              // 32: diskalloc.VerifyProblem
+	(*VerifyResponse)(nil),             // 33: diskalloc.VerifyResponse
+	(*MapExtent)(nil),                  // 34: diskalloc.MapExtent
+	(*AllocationMap)(nil),              // 35: diskalloc.AllocationMap
+	(*ExportMapRequest)(nil),           // 36: diskalloc.ExportMapRequest
+	(*ExportMapResponse)(nil),          // 37: diskalloc.ExportMapResponse
+	(*ImportMapRequest)(nil),           // 38: diskalloc.ImportMapRequest
+	(*ImportMapResponse)(nil),          // 39: diskalloc.ImportMapResponse
+	(*GrowRequest)(nil),                // 40: diskalloc.GrowRequest
+	(*GrowResponse)(nil),               // 41: diskalloc.GrowResponse
+	(*ShrinkRequest)(nil),              // 42: diskalloc.ShrinkRequest
+	(*ShrinkResponse)(nil),             // 43: diskalloc.ShrinkResponse
+	(*PoolInfo)(nil),                   // 44: diskalloc.PoolInfo
+	(*CreatePoolRequest)(nil),          // 45: diskalloc.CreatePoolRequest
+	(*CreatePoolResponse)(nil),         // 46: diskalloc.CreatePoolResponse
+	(*DeletePoolRequest)(nil),          // 47: diskalloc.DeletePoolRequest
+	(*DeletePoolResponse)(nil),         // 48: diskalloc.DeletePoolResponse
+	(*ListPoolsRequest)(nil),           // 49: diskalloc.ListPoolsRequest
+	(*ListPoolsResponse)(nil),          // 50: diskalloc.ListPoolsResponse
+	(*TenantUsage)(nil),                // 51: diskalloc.TenantUsage
+	(*SetTenantQuotaRequest)(nil),      // 52: diskalloc.SetTenantQuotaRequest
+	(*SetTenantQuotaResponse)(nil),     // 53: diskalloc.SetTenantQuotaResponse
+	(*GetTenantUsageRequest)(nil),      // 54: diskalloc.GetTenantUsageRequest
+	(*GetTenantUsageResponse)(nil),     // 55: diskalloc.GetTenantUsageResponse
+}
+var file_proto_spaceweave_proto_depIdxs = []int32{
+	0,  // 0: diskalloc.AllocateRequest.policy:type_name -> diskalloc.PlacementPolicy
+	6,  // 1: diskalloc.AllocateExtentsResponse.extents:type_name -> diskalloc.Extent
+	6,  // 2: diskalloc.FreeExtentsRequest.extents:type_name -> diskalloc.Extent
+	11, // 3: diskalloc.BatchAllocateResult.status:type_name -> diskalloc.ItemStatus
+	13, // 4: diskalloc.BatchAllocateResponse.results:type_name -> diskalloc.BatchAllocateResult
+	6,  // 5: diskalloc.BatchFreeRequest.extents:type_name -> diskalloc.Extent
+	11, // 6: diskalloc.BatchFreeResponse.results:type_name -> diskalloc.ItemStatus
+	2,  // 7: diskalloc.StreamRequest.allocate:type_name -> diskalloc.AllocateRequest
+	4,  // 8: diskalloc.StreamRequest.free:type_name -> diskalloc.FreeRequest
+	11, // 9: diskalloc.StreamResponse.status:type_name -> diskalloc.ItemStatus
+	32, // 10: diskalloc.VerifyResponse.problems:type_name -> diskalloc.VerifyProblem
+	1,  // 11: diskalloc.MapExtent.region:type_name -> diskalloc.Region
+	34, // 12: diskalloc.AllocationMap.extents:type_name -> diskalloc.MapExtent
+	35, // 13: diskalloc.ExportMapResponse.map:type_name -> diskalloc.AllocationMap
+	35, // 14: diskalloc.ImportMapRequest.map:type_name -> diskalloc.AllocationMap
+	6,  // 15: diskalloc.ShrinkResponse.relocate:type_name -> diskalloc.Extent
+	44, // 16: diskalloc.CreatePoolResponse.pool:type_name -> diskalloc.PoolInfo
+	44, // 17: diskalloc.ListPoolsResponse.pools:type_name -> diskalloc.PoolInfo
+	51, // 18: diskalloc.SetTenantQuotaResponse.tenant:type_name -> diskalloc.TenantUsage
+	51, // 19: diskalloc.GetTenantUsageResponse.tenants:type_name -> diskalloc.TenantUsage
+	2,  // 20: diskalloc.DiskAllocator.Allocate:input_type -> diskalloc.AllocateRequest
+	4,  // 21: diskalloc.DiskAllocator.Free:input_type -> diskalloc.FreeRequest
+	12, // 22: diskalloc.DiskAllocator.BatchAllocate:input_type -> diskalloc.BatchAllocateRequest
+	15, // 23: diskalloc.DiskAllocator.BatchFree:input_type -> diskalloc.BatchFreeRequest
+	17, // 24: diskalloc.DiskAllocator.AllocateStream:input_type -> diskalloc.StreamRequest
+	19, // 25: diskalloc.DiskAllocator.Reserve:input_type -> diskalloc.ReserveRequest
+	23, // 26: diskalloc.DiskAllocator.OpenSession:input_type -> diskalloc.OpenSessionRequest
+	25, // 27: diskalloc.DiskAllocator.Commit:input_type -> diskalloc.CommitRequest
+	27, // 28: diskalloc.DiskAllocator.ExtendTTL:input_type -> diskalloc.ExtendTTLRequest
+	21, // 29: diskalloc.DiskAllocator.Resize:input_type -> diskalloc.ResizeRequest
+	7,  // 30: diskalloc.DiskAllocator.AllocateExtents:input_type -> diskalloc.AllocateExtentsRequest
+	9,  // 31: diskalloc.DiskAllocator.FreeExtents:input_type -> diskalloc.FreeExtentsRequest
+	29, // 32: diskalloc.DiskAllocator.GetDiskUtilization:input_type -> diskalloc.GetDiskUtilizationRequest
+	31, // 33: diskalloc.DiskAllocator.Verify:input_type -> diskalloc.VerifyRequest
+	36, // 34: diskalloc.DiskAllocator.ExportMap:input_type -> diskalloc.ExportMapRequest
+	38, // 35: diskalloc.DiskAllocator.ImportMap:input_type -> diskalloc.ImportMapRequest
+	40, // 36: diskalloc.DiskAllocator.Grow:input_type -> diskalloc.GrowRequest
+	42, // 37: diskalloc.DiskAllocator.Shrink:input_type -> diskalloc.ShrinkRequest
+	45, // 38: diskalloc.DiskAllocator.CreatePool:input_type -> diskalloc.CreatePoolRequest
+	47, // 39: diskalloc.DiskAllocator.DeletePool:input_type -> diskalloc.DeletePoolRequest
+	49, // 40: diskalloc.DiskAllocator.ListPools:input_type -> diskalloc.ListPoolsRequest
+	52, // 41: diskalloc.DiskAllocator.SetTenantQuota:input_type -> diskalloc.SetTenantQuotaRequest
+	54, // 42: diskalloc.DiskAllocator.GetTenantUsage:input_type -> diskalloc.GetTenantUsageRequest
+	3,  // 43: diskalloc.DiskAllocator.Allocate:output_type -> diskalloc.AllocateResponse
+	5,  // 44: diskalloc.DiskAllocator.Free:output_type -> diskalloc.FreeResponse
+	14, // 45: diskalloc.DiskAllocator.BatchAllocate:output_type -> diskalloc.BatchAllocateResponse
+	16, // 46: diskalloc.DiskAllocator.BatchFree:output_type -> diskalloc.BatchFreeResponse
+	18, // 47: diskalloc.DiskAllocator.AllocateStream:output_type -> diskalloc.StreamResponse
+	20, // 48: diskalloc.DiskAllocator.Reserve:output_type -> diskalloc.ReserveResponse
+	24, // 49: diskalloc.DiskAllocator.OpenSession:output_type -> diskalloc.SessionEvent
+	26, // 50: diskalloc.DiskAllocator.Commit:output_type -> diskalloc.CommitResponse
+	28, // 51: diskalloc.DiskAllocator.ExtendTTL:output_type -> diskalloc.ExtendTTLResponse
+	22, // 52: diskalloc.DiskAllocator.Resize:output_type -> diskalloc.ResizeResponse
+	8,  // 53: diskalloc.DiskAllocator.AllocateExtents:output_type -> diskalloc.AllocateExtentsResponse
+	10, // 54: diskalloc.DiskAllocator.FreeExtents:output_type -> diskalloc.FreeExtentsResponse
+	30, // 55: diskalloc.DiskAllocator.GetDiskUtilization:output_type -> diskalloc.GetDiskUtilizationResponse
+	33, // 56: diskalloc.DiskAllocator.Verify:output_type -> diskalloc.VerifyResponse
+	37, // 57: diskalloc.DiskAllocator.ExportMap:output_type -> diskalloc.ExportMapResponse
+	39, // 58: diskalloc.DiskAllocator.ImportMap:output_type -> diskalloc.ImportMapResponse
+	41, // 59: diskalloc.DiskAllocator.Grow:output_type -> diskalloc.GrowResponse
+	43, // 60: diskalloc.DiskAllocator.Shrink:output_type -> diskalloc.ShrinkResponse
+	46, // 61: diskalloc.DiskAllocator.CreatePool:output_type -> diskalloc.CreatePoolResponse
+	48, // 62: diskalloc.DiskAllocator.DeletePool:output_type -> diskalloc.DeletePoolResponse
+	50, // 63: diskalloc.DiskAllocator.ListPools:output_type -> diskalloc.ListPoolsResponse
+	53, // 64: diskalloc.DiskAllocator.SetTenantQuota:output_type -> diskalloc.SetTenantQuotaResponse
+	55, // 65: diskalloc.DiskAllocator.GetTenantUsage:output_type -> diskalloc.GetTenantUsageResponse
+	43, // [43:66] is the sub-list for method output_type
+	20, // [20:43] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_proto_spaceweave_proto_init() }
+func file_proto_spaceweave_proto_init() {
+	if File_proto_spaceweave_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_spaceweave_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Extent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocateExtentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocateExtentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FreeExtentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FreeExtentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ItemStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchAllocateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchAllocateResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchAllocateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchFreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchFreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReserveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReserveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResizeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResizeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendTTLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendTTLResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetDiskUtilizationRequest); i {
 			case 0:
 				return &v.state
@@ -436,7 +4248,7 @@ func file_proto_spaceweave_proto_init() {
 				return nil
 			}
 		}
-		file_proto_spaceweave_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_spaceweave_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetDiskUtilizationResponse); i {
 			case 0:
 				return &v.state
@@ -448,19 +4260,324 @@ func file_proto_spaceweave_proto_init() {
 				return nil
 			}
 		}
+		file_proto_spaceweave_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyProblem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapExtent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocationMap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportMapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportMapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportMapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportMapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrowRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrowResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShrinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShrinkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PoolInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreatePoolRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreatePoolResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletePoolRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletePoolResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPoolsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPoolsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TenantUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetTenantQuotaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetTenantQuotaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTenantUsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_spaceweave_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTenantUsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_spaceweave_proto_msgTypes[15].OneofWrappers = []interface{}{
+		(*StreamRequest_Allocate)(nil),
+		(*StreamRequest_Free)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_spaceweave_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   54,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_spaceweave_proto_goTypes,
 		DependencyIndexes: file_proto_spaceweave_proto_depIdxs,
+		EnumInfos:         file_proto_spaceweave_proto_enumTypes,
 		MessageInfos:      file_proto_spaceweave_proto_msgTypes,
 	}.Build()
 	File_proto_spaceweave_proto = out.File
@@ -21,7 +21,27 @@ const _ = grpc.SupportPackageIsVersion7
 const (
 	DiskAllocator_Allocate_FullMethodName           = "/diskalloc.DiskAllocator/Allocate"
 	DiskAllocator_Free_FullMethodName               = "/diskalloc.DiskAllocator/Free"
+	DiskAllocator_BatchAllocate_FullMethodName      = "/diskalloc.DiskAllocator/BatchAllocate"
+	DiskAllocator_BatchFree_FullMethodName          = "/diskalloc.DiskAllocator/BatchFree"
+	DiskAllocator_AllocateStream_FullMethodName     = "/diskalloc.DiskAllocator/AllocateStream"
+	DiskAllocator_Reserve_FullMethodName            = "/diskalloc.DiskAllocator/Reserve"
+	DiskAllocator_OpenSession_FullMethodName        = "/diskalloc.DiskAllocator/OpenSession"
+	DiskAllocator_Commit_FullMethodName             = "/diskalloc.DiskAllocator/Commit"
+	DiskAllocator_ExtendTTL_FullMethodName          = "/diskalloc.DiskAllocator/ExtendTTL"
+	DiskAllocator_Resize_FullMethodName             = "/diskalloc.DiskAllocator/Resize"
+	DiskAllocator_AllocateExtents_FullMethodName    = "/diskalloc.DiskAllocator/AllocateExtents"
+	DiskAllocator_FreeExtents_FullMethodName        = "/diskalloc.DiskAllocator/FreeExtents"
 	DiskAllocator_GetDiskUtilization_FullMethodName = "/diskalloc.DiskAllocator/GetDiskUtilization"
+	DiskAllocator_Verify_FullMethodName             = "/diskalloc.DiskAllocator/Verify"
+	DiskAllocator_ExportMap_FullMethodName          = "/diskalloc.DiskAllocator/ExportMap"
+	DiskAllocator_ImportMap_FullMethodName          = "/diskalloc.DiskAllocator/ImportMap"
+	DiskAllocator_Grow_FullMethodName               = "/diskalloc.DiskAllocator/Grow"
+	DiskAllocator_Shrink_FullMethodName             = "/diskalloc.DiskAllocator/Shrink"
+	DiskAllocator_CreatePool_FullMethodName         = "/diskalloc.DiskAllocator/CreatePool"
+	DiskAllocator_DeletePool_FullMethodName         = "/diskalloc.DiskAllocator/DeletePool"
+	DiskAllocator_ListPools_FullMethodName          = "/diskalloc.DiskAllocator/ListPools"
+	DiskAllocator_SetTenantQuota_FullMethodName     = "/diskalloc.DiskAllocator/SetTenantQuota"
+	DiskAllocator_GetTenantUsage_FullMethodName     = "/diskalloc.DiskAllocator/GetTenantUsage"
 )
 
 // DiskAllocatorClient is the client API for DiskAllocator service.
@@ -30,7 +50,27 @@ const (
 type DiskAllocatorClient interface {
 	Allocate(ctx context.Context, in *AllocateRequest, opts ...grpc.CallOption) (*AllocateResponse, error)
 	Free(ctx context.Context, in *FreeRequest, opts ...grpc.CallOption) (*FreeResponse, error)
+	BatchAllocate(ctx context.Context, in *BatchAllocateRequest, opts ...grpc.CallOption) (*BatchAllocateResponse, error)
+	BatchFree(ctx context.Context, in *BatchFreeRequest, opts ...grpc.CallOption) (*BatchFreeResponse, error)
+	AllocateStream(ctx context.Context, opts ...grpc.CallOption) (DiskAllocator_AllocateStreamClient, error)
+	Reserve(ctx context.Context, in *ReserveRequest, opts ...grpc.CallOption) (*ReserveResponse, error)
+	OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (DiskAllocator_OpenSessionClient, error)
+	Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error)
+	ExtendTTL(ctx context.Context, in *ExtendTTLRequest, opts ...grpc.CallOption) (*ExtendTTLResponse, error)
+	Resize(ctx context.Context, in *ResizeRequest, opts ...grpc.CallOption) (*ResizeResponse, error)
+	AllocateExtents(ctx context.Context, in *AllocateExtentsRequest, opts ...grpc.CallOption) (*AllocateExtentsResponse, error)
+	FreeExtents(ctx context.Context, in *FreeExtentsRequest, opts ...grpc.CallOption) (*FreeExtentsResponse, error)
 	GetDiskUtilization(ctx context.Context, in *GetDiskUtilizationRequest, opts ...grpc.CallOption) (*GetDiskUtilizationResponse, error)
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	ExportMap(ctx context.Context, in *ExportMapRequest, opts ...grpc.CallOption) (*ExportMapResponse, error)
+	ImportMap(ctx context.Context, in *ImportMapRequest, opts ...grpc.CallOption) (*ImportMapResponse, error)
+	Grow(ctx context.Context, in *GrowRequest, opts ...grpc.CallOption) (*GrowResponse, error)
+	Shrink(ctx context.Context, in *ShrinkRequest, opts ...grpc.CallOption) (*ShrinkResponse, error)
+	CreatePool(ctx context.Context, in *CreatePoolRequest, opts ...grpc.CallOption) (*CreatePoolResponse, error)
+	DeletePool(ctx context.Context, in *DeletePoolRequest, opts ...grpc.CallOption) (*DeletePoolResponse, error)
+	ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error)
+	SetTenantQuota(ctx context.Context, in *SetTenantQuotaRequest, opts ...grpc.CallOption) (*SetTenantQuotaResponse, error)
+	GetTenantUsage(ctx context.Context, in *GetTenantUsageRequest, opts ...grpc.CallOption) (*GetTenantUsageResponse, error)
 }
 
 type diskAllocatorClient struct {
@@ -59,6 +99,141 @@ func (c *diskAllocatorClient) Free(ctx context.Context, in *FreeRequest, opts ..
 	return out, nil
 }
 
+func (c *diskAllocatorClient) BatchAllocate(ctx context.Context, in *BatchAllocateRequest, opts ...grpc.CallOption) (*BatchAllocateResponse, error) {
+	out := new(BatchAllocateResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_BatchAllocate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) BatchFree(ctx context.Context, in *BatchFreeRequest, opts ...grpc.CallOption) (*BatchFreeResponse, error) {
+	out := new(BatchFreeResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_BatchFree_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) AllocateStream(ctx context.Context, opts ...grpc.CallOption) (DiskAllocator_AllocateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DiskAllocator_ServiceDesc.Streams[0], DiskAllocator_AllocateStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &diskAllocatorAllocateStreamClient{stream}
+	return x, nil
+}
+
+type DiskAllocator_AllocateStreamClient interface {
+	Send(*StreamRequest) error
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type diskAllocatorAllocateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *diskAllocatorAllocateStreamClient) Send(m *StreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *diskAllocatorAllocateStreamClient) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *diskAllocatorClient) Reserve(ctx context.Context, in *ReserveRequest, opts ...grpc.CallOption) (*ReserveResponse, error) {
+	out := new(ReserveResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_Reserve_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) OpenSession(ctx context.Context, in *OpenSessionRequest, opts ...grpc.CallOption) (DiskAllocator_OpenSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DiskAllocator_ServiceDesc.Streams[1], DiskAllocator_OpenSession_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &diskAllocatorOpenSessionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DiskAllocator_OpenSessionClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type diskAllocatorOpenSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *diskAllocatorOpenSessionClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *diskAllocatorClient) Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error) {
+	out := new(CommitResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_Commit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) ExtendTTL(ctx context.Context, in *ExtendTTLRequest, opts ...grpc.CallOption) (*ExtendTTLResponse, error) {
+	out := new(ExtendTTLResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_ExtendTTL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) Resize(ctx context.Context, in *ResizeRequest, opts ...grpc.CallOption) (*ResizeResponse, error) {
+	out := new(ResizeResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_Resize_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) AllocateExtents(ctx context.Context, in *AllocateExtentsRequest, opts ...grpc.CallOption) (*AllocateExtentsResponse, error) {
+	out := new(AllocateExtentsResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_AllocateExtents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) FreeExtents(ctx context.Context, in *FreeExtentsRequest, opts ...grpc.CallOption) (*FreeExtentsResponse, error) {
+	out := new(FreeExtentsResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_FreeExtents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *diskAllocatorClient) GetDiskUtilization(ctx context.Context, in *GetDiskUtilizationRequest, opts ...grpc.CallOption) (*GetDiskUtilizationResponse, error) {
 	out := new(GetDiskUtilizationResponse)
 	err := c.cc.Invoke(ctx, DiskAllocator_GetDiskUtilization_FullMethodName, in, out, opts...)
@@ -68,13 +243,123 @@ func (c *diskAllocatorClient) GetDiskUtilization(ctx context.Context, in *GetDis
 	return out, nil
 }
 
+func (c *diskAllocatorClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_Verify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) ExportMap(ctx context.Context, in *ExportMapRequest, opts ...grpc.CallOption) (*ExportMapResponse, error) {
+	out := new(ExportMapResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_ExportMap_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) ImportMap(ctx context.Context, in *ImportMapRequest, opts ...grpc.CallOption) (*ImportMapResponse, error) {
+	out := new(ImportMapResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_ImportMap_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) Grow(ctx context.Context, in *GrowRequest, opts ...grpc.CallOption) (*GrowResponse, error) {
+	out := new(GrowResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_Grow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) Shrink(ctx context.Context, in *ShrinkRequest, opts ...grpc.CallOption) (*ShrinkResponse, error) {
+	out := new(ShrinkResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_Shrink_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) CreatePool(ctx context.Context, in *CreatePoolRequest, opts ...grpc.CallOption) (*CreatePoolResponse, error) {
+	out := new(CreatePoolResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_CreatePool_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) DeletePool(ctx context.Context, in *DeletePoolRequest, opts ...grpc.CallOption) (*DeletePoolResponse, error) {
+	out := new(DeletePoolResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_DeletePool_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error) {
+	out := new(ListPoolsResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_ListPools_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) SetTenantQuota(ctx context.Context, in *SetTenantQuotaRequest, opts ...grpc.CallOption) (*SetTenantQuotaResponse, error) {
+	out := new(SetTenantQuotaResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_SetTenantQuota_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diskAllocatorClient) GetTenantUsage(ctx context.Context, in *GetTenantUsageRequest, opts ...grpc.CallOption) (*GetTenantUsageResponse, error) {
+	out := new(GetTenantUsageResponse)
+	err := c.cc.Invoke(ctx, DiskAllocator_GetTenantUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DiskAllocatorServer is the server API for DiskAllocator service.
 // All implementations should embed UnimplementedDiskAllocatorServer
 // for forward compatibility
 type DiskAllocatorServer interface {
 	Allocate(context.Context, *AllocateRequest) (*AllocateResponse, error)
 	Free(context.Context, *FreeRequest) (*FreeResponse, error)
+	BatchAllocate(context.Context, *BatchAllocateRequest) (*BatchAllocateResponse, error)
+	BatchFree(context.Context, *BatchFreeRequest) (*BatchFreeResponse, error)
+	AllocateStream(DiskAllocator_AllocateStreamServer) error
+	Reserve(context.Context, *ReserveRequest) (*ReserveResponse, error)
+	OpenSession(*OpenSessionRequest, DiskAllocator_OpenSessionServer) error
+	Commit(context.Context, *CommitRequest) (*CommitResponse, error)
+	ExtendTTL(context.Context, *ExtendTTLRequest) (*ExtendTTLResponse, error)
+	Resize(context.Context, *ResizeRequest) (*ResizeResponse, error)
+	AllocateExtents(context.Context, *AllocateExtentsRequest) (*AllocateExtentsResponse, error)
+	FreeExtents(context.Context, *FreeExtentsRequest) (*FreeExtentsResponse, error)
 	GetDiskUtilization(context.Context, *GetDiskUtilizationRequest) (*GetDiskUtilizationResponse, error)
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	ExportMap(context.Context, *ExportMapRequest) (*ExportMapResponse, error)
+	ImportMap(context.Context, *ImportMapRequest) (*ImportMapResponse, error)
+	Grow(context.Context, *GrowRequest) (*GrowResponse, error)
+	Shrink(context.Context, *ShrinkRequest) (*ShrinkResponse, error)
+	CreatePool(context.Context, *CreatePoolRequest) (*CreatePoolResponse, error)
+	DeletePool(context.Context, *DeletePoolRequest) (*DeletePoolResponse, error)
+	ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error)
+	SetTenantQuota(context.Context, *SetTenantQuotaRequest) (*SetTenantQuotaResponse, error)
+	GetTenantUsage(context.Context, *GetTenantUsageRequest) (*GetTenantUsageResponse, error)
 }
 
 // UnimplementedDiskAllocatorServer should be embedded to have forward compatible implementations.
@@ -87,9 +372,69 @@ func (UnimplementedDiskAllocatorServer) Allocate(context.Context, *AllocateReque
 func (UnimplementedDiskAllocatorServer) Free(context.Context, *FreeRequest) (*FreeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Free not implemented")
 }
+func (UnimplementedDiskAllocatorServer) BatchAllocate(context.Context, *BatchAllocateRequest) (*BatchAllocateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchAllocate not implemented")
+}
+func (UnimplementedDiskAllocatorServer) BatchFree(context.Context, *BatchFreeRequest) (*BatchFreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchFree not implemented")
+}
+func (UnimplementedDiskAllocatorServer) AllocateStream(DiskAllocator_AllocateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method AllocateStream not implemented")
+}
+func (UnimplementedDiskAllocatorServer) Reserve(context.Context, *ReserveRequest) (*ReserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reserve not implemented")
+}
+func (UnimplementedDiskAllocatorServer) OpenSession(*OpenSessionRequest, DiskAllocator_OpenSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method OpenSession not implemented")
+}
+func (UnimplementedDiskAllocatorServer) Commit(context.Context, *CommitRequest) (*CommitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
+func (UnimplementedDiskAllocatorServer) ExtendTTL(context.Context, *ExtendTTLRequest) (*ExtendTTLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendTTL not implemented")
+}
+func (UnimplementedDiskAllocatorServer) Resize(context.Context, *ResizeRequest) (*ResizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resize not implemented")
+}
+func (UnimplementedDiskAllocatorServer) AllocateExtents(context.Context, *AllocateExtentsRequest) (*AllocateExtentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllocateExtents not implemented")
+}
+func (UnimplementedDiskAllocatorServer) FreeExtents(context.Context, *FreeExtentsRequest) (*FreeExtentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreeExtents not implemented")
+}
 func (UnimplementedDiskAllocatorServer) GetDiskUtilization(context.Context, *GetDiskUtilizationRequest) (*GetDiskUtilizationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDiskUtilization not implemented")
 }
+func (UnimplementedDiskAllocatorServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedDiskAllocatorServer) ExportMap(context.Context, *ExportMapRequest) (*ExportMapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMap not implemented")
+}
+func (UnimplementedDiskAllocatorServer) ImportMap(context.Context, *ImportMapRequest) (*ImportMapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMap not implemented")
+}
+func (UnimplementedDiskAllocatorServer) Grow(context.Context, *GrowRequest) (*GrowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Grow not implemented")
+}
+func (UnimplementedDiskAllocatorServer) Shrink(context.Context, *ShrinkRequest) (*ShrinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shrink not implemented")
+}
+func (UnimplementedDiskAllocatorServer) CreatePool(context.Context, *CreatePoolRequest) (*CreatePoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePool not implemented")
+}
+func (UnimplementedDiskAllocatorServer) DeletePool(context.Context, *DeletePoolRequest) (*DeletePoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePool not implemented")
+}
+func (UnimplementedDiskAllocatorServer) ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPools not implemented")
+}
+func (UnimplementedDiskAllocatorServer) SetTenantQuota(context.Context, *SetTenantQuotaRequest) (*SetTenantQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTenantQuota not implemented")
+}
+func (UnimplementedDiskAllocatorServer) GetTenantUsage(context.Context, *GetTenantUsageRequest) (*GetTenantUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenantUsage not implemented")
+}
 
 // UnsafeDiskAllocatorServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to DiskAllocatorServer will
@@ -138,6 +483,197 @@ func _DiskAllocator_Free_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DiskAllocator_BatchAllocate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAllocateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).BatchAllocate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_BatchAllocate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).BatchAllocate(ctx, req.(*BatchAllocateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_BatchFree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchFreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).BatchFree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_BatchFree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).BatchFree(ctx, req.(*BatchFreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_AllocateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DiskAllocatorServer).AllocateStream(&diskAllocatorAllocateStreamServer{stream})
+}
+
+type DiskAllocator_AllocateStreamServer interface {
+	Send(*StreamResponse) error
+	Recv() (*StreamRequest, error)
+	grpc.ServerStream
+}
+
+type diskAllocatorAllocateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *diskAllocatorAllocateStreamServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *diskAllocatorAllocateStreamServer) Recv() (*StreamRequest, error) {
+	m := new(StreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DiskAllocator_Reserve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).Reserve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_Reserve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).Reserve(ctx, req.(*ReserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_OpenSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DiskAllocatorServer).OpenSession(m, &diskAllocatorOpenSessionServer{stream})
+}
+
+type DiskAllocator_OpenSessionServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type diskAllocatorOpenSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *diskAllocatorOpenSessionServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DiskAllocator_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_Commit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).Commit(ctx, req.(*CommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_ExtendTTL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendTTLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).ExtendTTL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_ExtendTTL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).ExtendTTL(ctx, req.(*ExtendTTLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_Resize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).Resize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_Resize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).Resize(ctx, req.(*ResizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_AllocateExtents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateExtentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).AllocateExtents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_AllocateExtents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).AllocateExtents(ctx, req.(*AllocateExtentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_FreeExtents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeExtentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).FreeExtents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_FreeExtents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).FreeExtents(ctx, req.(*FreeExtentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DiskAllocator_GetDiskUtilization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetDiskUtilizationRequest)
 	if err := dec(in); err != nil {
@@ -156,6 +692,186 @@ func _DiskAllocator_GetDiskUtilization_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DiskAllocator_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_ExportMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).ExportMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_ExportMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).ExportMap(ctx, req.(*ExportMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_ImportMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).ImportMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_ImportMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).ImportMap(ctx, req.(*ImportMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_Grow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).Grow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_Grow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).Grow(ctx, req.(*GrowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_Shrink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShrinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).Shrink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_Shrink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).Shrink(ctx, req.(*ShrinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_CreatePool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).CreatePool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_CreatePool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).CreatePool(ctx, req.(*CreatePoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_DeletePool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).DeletePool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_DeletePool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).DeletePool(ctx, req.(*DeletePoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_ListPools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).ListPools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_ListPools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).ListPools(ctx, req.(*ListPoolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_SetTenantQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTenantQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).SetTenantQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_SetTenantQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).SetTenantQuota(ctx, req.(*SetTenantQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiskAllocator_GetTenantUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenantUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiskAllocatorServer).GetTenantUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiskAllocator_GetTenantUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiskAllocatorServer).GetTenantUsage(ctx, req.(*GetTenantUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DiskAllocator_ServiceDesc is the grpc.ServiceDesc for DiskAllocator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -171,11 +887,95 @@ var DiskAllocator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Free",
 			Handler:    _DiskAllocator_Free_Handler,
 		},
+		{
+			MethodName: "BatchAllocate",
+			Handler:    _DiskAllocator_BatchAllocate_Handler,
+		},
+		{
+			MethodName: "BatchFree",
+			Handler:    _DiskAllocator_BatchFree_Handler,
+		},
+		{
+			MethodName: "Reserve",
+			Handler:    _DiskAllocator_Reserve_Handler,
+		},
+		{
+			MethodName: "Commit",
+			Handler:    _DiskAllocator_Commit_Handler,
+		},
+		{
+			MethodName: "ExtendTTL",
+			Handler:    _DiskAllocator_ExtendTTL_Handler,
+		},
+		{
+			MethodName: "Resize",
+			Handler:    _DiskAllocator_Resize_Handler,
+		},
+		{
+			MethodName: "AllocateExtents",
+			Handler:    _DiskAllocator_AllocateExtents_Handler,
+		},
+		{
+			MethodName: "FreeExtents",
+			Handler:    _DiskAllocator_FreeExtents_Handler,
+		},
 		{
 			MethodName: "GetDiskUtilization",
 			Handler:    _DiskAllocator_GetDiskUtilization_Handler,
 		},
+		{
+			MethodName: "Verify",
+			Handler:    _DiskAllocator_Verify_Handler,
+		},
+		{
+			MethodName: "ExportMap",
+			Handler:    _DiskAllocator_ExportMap_Handler,
+		},
+		{
+			MethodName: "ImportMap",
+			Handler:    _DiskAllocator_ImportMap_Handler,
+		},
+		{
+			MethodName: "Grow",
+			Handler:    _DiskAllocator_Grow_Handler,
+		},
+		{
+			MethodName: "Shrink",
+			Handler:    _DiskAllocator_Shrink_Handler,
+		},
+		{
+			MethodName: "CreatePool",
+			Handler:    _DiskAllocator_CreatePool_Handler,
+		},
+		{
+			MethodName: "DeletePool",
+			Handler:    _DiskAllocator_DeletePool_Handler,
+		},
+		{
+			MethodName: "ListPools",
+			Handler:    _DiskAllocator_ListPools_Handler,
+		},
+		{
+			MethodName: "SetTenantQuota",
+			Handler:    _DiskAllocator_SetTenantQuota_Handler,
+		},
+		{
+			MethodName: "GetTenantUsage",
+			Handler:    _DiskAllocator_GetTenantUsage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AllocateStream",
+			Handler:       _DiskAllocator_AllocateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "OpenSession",
+			Handler:       _DiskAllocator_OpenSession_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/spaceweave.proto",
 }
@@ -1,11 +1,89 @@
 package service
 
 import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/li1213987842/spaceweave/config"
 	"github.com/li1213987842/spaceweave/internal/allocator"
+	pb "github.com/li1213987842/spaceweave/proto"
 )
 
 var (
-	ServConfig     *config.Config
-	AllocatorStore allocator.DiskAllocator
+	ServConfig *config.Config
+	Pools      *PoolRegistry
 )
+
+// poolStore 返回请求指定的存储池的分配器，请求处理结束后调用 release
+func poolStore(poolID string) (store allocator.DiskAllocator, release func(), err error) {
+	store, release, err = Pools.acquire(poolID)
+	return store, release, toStatusError(err)
+}
+
+func toPlacementPolicy(policy pb.PlacementPolicy) (allocator.PlacementPolicy, error) {
+	switch policy {
+	case pb.PlacementPolicy_POLICY_DEFAULT:
+		return allocator.PolicyDefault, nil
+	case pb.PlacementPolicy_BEST_FIT:
+		return allocator.PolicyBestFit, nil
+	case pb.PlacementPolicy_FIRST_FIT:
+		return allocator.PolicyFirstFit, nil
+	case pb.PlacementPolicy_NEXT_FIT:
+		return allocator.PolicyNextFit, nil
+	case pb.PlacementPolicy_WORST_FIT:
+		return allocator.PolicyWorstFit, nil
+	}
+	return allocator.PolicyDefault, status.Errorf(codes.InvalidArgument, "Invalid Argument: policy %v", policy)
+}
+
+func toPBExtents(extents []allocator.Extent) []*pb.Extent {
+	res := make([]*pb.Extent, 0, len(extents))
+	for _, e := range extents {
+		res = append(res, &pb.Extent{Address: e.Address, Size: e.Size})
+	}
+	return res
+}
+
+func fromPBExtents(extents []*pb.Extent) []allocator.Extent {
+	res := make([]allocator.Extent, 0, len(extents))
+	for _, e := range extents {
+		res = append(res, allocator.Extent{Address: e.Address, Size: e.Size})
+	}
+	return res
+}
+
+// toStatusError 将分配器返回的错误转换为对应的 gRPC 状态码
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := codes.Internal
+	switch {
+	case errors.Is(err, allocator.ErrNoSpaceLeft), errors.Is(err, allocator.ErrQuotaExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, allocator.ErrNotAllocated), errors.Is(err, allocator.ErrSessionNotFound),
+		errors.Is(err, ErrPoolNotFound):
+		code = codes.NotFound
+	case errors.Is(err, allocator.ErrNotLeased), errors.Is(err, allocator.ErrNoTTL), errors.Is(err, allocator.ErrNotEmpty),
+		errors.Is(err, ErrDefaultPool), errors.Is(err, ErrTierPool), errors.Is(err, ErrNoTiers):
+		code = codes.FailedPrecondition
+	case errors.Is(err, allocator.ErrSizeMismatch), errors.Is(err, allocator.ErrInvalidAlignment),
+		errors.Is(err, allocator.ErrInvalidTTL), errors.Is(err, allocator.ErrInvalidMap),
+		errors.Is(err, allocator.ErrInvalidTotalSize), errors.Is(err, ErrInvalidPool):
+		code = codes.InvalidArgument
+	case errors.Is(err, allocator.ErrRangeInUse), errors.Is(err, ErrPoolExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, allocator.ErrOutOfRange):
+		code = codes.OutOfRange
+	case errors.Is(err, ErrPoolsClosed):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
+
+func toItemStatus(err error) *pb.ItemStatus {
+	st := status.Convert(err)
+	return &pb.ItemStatus{Code: uint32(st.Code()), Message: st.Message()}
+}
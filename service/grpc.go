@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/li1213987842/spaceweave/internal/allocator"
 	pb "github.com/li1213987842/spaceweave/proto"
 )
 
@@ -14,20 +17,332 @@ type _GRPCService struct {
 
 func (s *_GRPCService) Allocate(ctx context.Context, req *pb.AllocateRequest) (resp *pb.AllocateResponse, err error) {
 	if req.Size <= 0 {
-		return nil, errors.New(fmt.Sprintf("Invalid Argument: size %d", req.Size))
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: size %d", req.Size)
+	}
+	// 会话、TTL、对齐和放置策略各自对应一种分配方式，不能组合使用
+	options := 0
+	for _, set := range []bool{req.SessionId != 0, req.TtlSec > 0, req.Alignment > 0, req.Policy != pb.PlacementPolicy_POLICY_DEFAULT} {
+		if set {
+			options++
+		}
+	}
+	if options > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: session_id, ttl_sec, alignment and policy cannot be combined")
+	}
+	var alloc func(store allocator.DiskAllocator) (uint64, error)
+	if req.SessionId != 0 {
+		alloc = func(store allocator.DiskAllocator) (uint64, error) {
+			return store.AllocateInSession(req.SessionId, req.Size)
+		}
+	} else if req.TtlSec > 0 {
+		alloc = func(store allocator.DiskAllocator) (uint64, error) {
+			return store.AllocateWithTTL(req.Size, time.Duration(req.TtlSec)*time.Second)
+		}
+	} else if req.Alignment > 0 {
+		alloc = func(store allocator.DiskAllocator) (uint64, error) {
+			return store.AllocateAligned(req.Size, req.Alignment)
+		}
+	} else {
+		policy, perr := toPlacementPolicy(req.Policy)
+		if perr != nil {
+			return nil, perr
+		}
+		alloc = func(store allocator.DiskAllocator) (uint64, error) {
+			return store.AllocateWithPolicy(req.Size, policy)
+		}
 	}
-	addr, err := AllocatorStore.Allocate(req.Size)
+
+	var overSoft bool
+	alloc = withTenant(tenantID(ctx, req.TenantId), &overSoft, alloc)
+
+	if req.Tiered {
+		// 会话只属于一个存储池，不能跨层分配
+		if req.SessionId != 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: session_id cannot be used with tiered allocation")
+		}
+		tiers, release, err := Pools.acquireTiers()
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		defer release()
+		tier, addr, err := tiers.AllocateWith(req.Size, alloc)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		return &pb.AllocateResponse{Address: addr, Tier: tier, OverSoftQuota: overSoft}, nil
+	}
+
+	store, release, err := poolStore(req.PoolId)
 	if err != nil {
 		return nil, err
 	}
-	return &pb.AllocateResponse{Address: addr}, nil
+	defer release()
+	addr, err := alloc(store)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.AllocateResponse{Address: addr, Tier: poolID(req.PoolId), OverSoftQuota: overSoft}, nil
 }
 
 func (s *_GRPCService) Free(ctx context.Context, req *pb.FreeRequest) (resp *pb.FreeResponse, err error) {
-	return &pb.FreeResponse{}, AllocatorStore.Free(req.Address, req.Size)
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.FreeResponse{}, toStatusError(store.Free(req.Address, req.Size))
+}
+
+func (s *_GRPCService) AllocateExtents(ctx context.Context, req *pb.AllocateExtentsRequest) (resp *pb.AllocateExtentsResponse, err error) {
+	if req.Size <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: size %d", req.Size)
+	}
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	extents, err := forTenant(store, tenantID(ctx, req.TenantId)).AllocateExtents(req.Size, int(req.MaxExtents), req.MinExtentSize)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.AllocateExtentsResponse{Extents: toPBExtents(extents)}, nil
+}
+
+func (s *_GRPCService) FreeExtents(ctx context.Context, req *pb.FreeExtentsRequest) (resp *pb.FreeExtentsResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.FreeExtentsResponse{}, toStatusError(store.FreeExtents(fromPBExtents(req.Extents)))
+}
+
+func (s *_GRPCService) BatchAllocate(ctx context.Context, req *pb.BatchAllocateRequest) (resp *pb.BatchAllocateResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	results := make([]*pb.BatchAllocateResult, len(req.Sizes))
+	sizes := make([]uint64, 0, len(req.Sizes))
+	indexes := make([]int, 0, len(req.Sizes))
+	for i, size := range req.Sizes {
+		if size <= 0 {
+			results[i] = &pb.BatchAllocateResult{Status: toItemStatus(status.Errorf(codes.InvalidArgument, "Invalid Argument: size %d", size))}
+			continue
+		}
+		sizes = append(sizes, size)
+		indexes = append(indexes, i)
+	}
+
+	// 分配器按顺序检查配额和预留，超过硬配额的项不参与分配
+	for j, r := range forTenant(store, tenantID(ctx, req.TenantId)).BatchAllocate(sizes) {
+		results[indexes[j]] = &pb.BatchAllocateResult{Address: r.Address, Status: toItemStatus(toStatusError(r.Err))}
+	}
+	return &pb.BatchAllocateResponse{Results: results}, nil
+}
+
+func (s *_GRPCService) BatchFree(ctx context.Context, req *pb.BatchFreeRequest) (resp *pb.BatchFreeResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	errs := store.BatchFree(fromPBExtents(req.Extents))
+	results := make([]*pb.ItemStatus, len(errs))
+	for i, e := range errs {
+		results[i] = toItemStatus(toStatusError(e))
+	}
+	return &pb.BatchFreeResponse{Results: results}, nil
+}
+
+func (s *_GRPCService) Reserve(ctx context.Context, req *pb.ReserveRequest) (resp *pb.ReserveResponse, err error) {
+	if req.Size <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: size %d", req.Size)
+	}
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.ReserveResponse{}, toStatusError(store.Reserve(req.Address, req.Size))
+}
+
+func (s *_GRPCService) Resize(ctx context.Context, req *pb.ResizeRequest) (resp *pb.ResizeResponse, err error) {
+	if req.NewSize <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: new size %d", req.NewSize)
+	}
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	// 扩大时按增加的单元检查租户的配额；迁移到新地址时新空间整段计入用量，
+	// 旧空间在调用方释放前仍记在原租户名下，释放时其用量随之扣除
+	addr, moved, err := forTenant(store, tenantID(ctx, req.TenantId)).Resize(req.Address, req.OldSize, req.NewSize)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ResizeResponse{Address: addr, Moved: moved}, nil
 }
 
 func (s *_GRPCService) GetDiskUtilization(ctx context.Context, req *pb.GetDiskUtilizationRequest) (resp *pb.GetDiskUtilizationResponse, err error) {
-	utilization := AllocatorStore.GetDiskUtilization()
-	return &pb.GetDiskUtilizationResponse{Utilization: float32(utilization)}, nil
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	utilization := store.GetDiskUtilization()
+	ttlStats := store.GetTTLStats()
+	verifyStats := store.GetVerifyStats()
+	reservations := store.GetReservationStats()
+	return &pb.GetDiskUtilizationResponse{
+		Utilization:              float32(utilization),
+		TtlTracked:               ttlStats.Tracked,
+		TtlReclaimedExtents:      ttlStats.ReclaimedExtents,
+		TtlReclaimedBytes:        ttlStats.ReclaimedBytes,
+		VerifyRuns:               verifyStats.Runs,
+		VerifyFailures:           verifyStats.Failures,
+		VerifyRepairs:            verifyStats.Repairs,
+		TotalSize:                store.GetTotalSize(),
+		ReservedBytes:            reservations.Reserved,
+		OutstandingReservedBytes: reservations.Outstanding,
+		UnreservedFreeBytes:      reservations.Unreserved,
+	}, nil
+}
+
+func (s *_GRPCService) Verify(ctx context.Context, req *pb.VerifyRequest) (resp *pb.VerifyResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	report := store.Verify(req.Repair)
+	resp = &pb.VerifyResponse{
+		FreeBlocks:      uint64(report.FreeBlocks),
+		TreeFreeUnits:   report.TreeFreeUnits,
+		BitmapFreeUnits: report.BitmapFreeUnits,
+		Utilization:     float32(report.Utilization),
+		Repaired:        report.Repaired,
+	}
+	for _, p := range report.Problems {
+		resp.Problems = append(resp.Problems, &pb.VerifyProblem{Kind: string(p.Kind), Detail: p.Detail})
+	}
+	return resp, nil
+}
+
+func (s *_GRPCService) ExtendTTL(ctx context.Context, req *pb.ExtendTTLRequest) (resp *pb.ExtendTTLResponse, err error) {
+	if req.TtlSec <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: ttl %d", req.TtlSec)
+	}
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.ExtendTTLResponse{}, toStatusError(store.ExtendTTL(req.Address, time.Duration(req.TtlSec)*time.Second))
+}
+
+func (s *_GRPCService) ExportMap(ctx context.Context, req *pb.ExportMapRequest) (resp *pb.ExportMapResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.ExportMapResponse{Map: store.ExportMap()}, nil
+}
+
+func (s *_GRPCService) ImportMap(ctx context.Context, req *pb.ImportMapRequest) (resp *pb.ImportMapResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	n, err := store.ImportMap(req.Map)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.ImportMapResponse{Allocations: uint64(n)}, nil
+}
+
+func (s *_GRPCService) Grow(ctx context.Context, req *pb.GrowRequest) (resp *pb.GrowResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := store.Grow(req.TotalSize); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.GrowResponse{TotalSize: store.GetTotalSize()}, nil
+}
+
+func (s *_GRPCService) Shrink(ctx context.Context, req *pb.ShrinkRequest) (resp *pb.ShrinkResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	relocate, done, err := store.Shrink(req.TotalSize)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	resp = &pb.ShrinkResponse{Done: done, TotalSize: store.GetTotalSize()}
+	for _, extent := range relocate {
+		resp.Relocate = append(resp.Relocate, &pb.Extent{Address: extent.Address, Size: extent.Size})
+	}
+	return resp, nil
+}
+
+func (s *_GRPCService) CreatePool(ctx context.Context, req *pb.CreatePoolRequest) (resp *pb.CreatePoolResponse, err error) {
+	spec, err := Pools.Create(PoolSpec{
+		ID:                   req.PoolId,
+		UnitSize:             req.UnitSize,
+		TotalSize:            req.TotalSize,
+		StatePersistencePath: req.StatePersistencePath,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	info, err := poolInfo(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreatePoolResponse{Pool: info}, nil
+}
+
+func (s *_GRPCService) DeletePool(ctx context.Context, req *pb.DeletePoolRequest) (resp *pb.DeletePoolResponse, err error) {
+	return &pb.DeletePoolResponse{}, toStatusError(Pools.Delete(req.PoolId, req.Force))
+}
+
+func (s *_GRPCService) ListPools(ctx context.Context, req *pb.ListPoolsRequest) (resp *pb.ListPoolsResponse, err error) {
+	resp = &pb.ListPoolsResponse{}
+	for _, spec := range Pools.List() {
+		info, err := poolInfo(spec)
+		if status.Code(err) == codes.NotFound {
+			continue // 列出之后被删除
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Pools = append(resp.Pools, info)
+	}
+	return resp, nil
+}
+
+// poolInfo 返回存储池的配置和当前的大小、利用率
+func poolInfo(spec PoolSpec) (*pb.PoolInfo, error) {
+	store, release, err := poolStore(spec.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.PoolInfo{
+		PoolId:               spec.ID,
+		UnitSize:             spec.UnitSize,
+		TotalSize:            store.GetTotalSize(),
+		StatePersistencePath: spec.StatePersistencePath,
+		Utilization:          float32(store.GetDiskUtilization()),
+	}, nil
 }
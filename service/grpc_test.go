@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/li1213987842/spaceweave/config"
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+// newTestConfig 返回不持久化的 64MB 默认存储池配置，modify 不为 nil 时在创建登记表前调整配置
+func newTestConfig(modify func(cfg *config.Config)) *config.Config {
+	cfg := &config.Config{
+		UnitSize:          4096,
+		TotalSize:         64 * 1024 * 1024,
+		SmallBlockRatio:   0.1,
+		NumShards:         4,
+		BackupIntervalSec: 3600,
+	}
+	cfg.SmallBlockLimit = uint64(float64(cfg.TotalSize) * cfg.SmallBlockRatio / float64(cfg.UnitSize))
+	if modify != nil {
+		modify(cfg)
+	}
+	return cfg
+}
+
+// newTestService 按 cfg 创建存储池登记表并设为 Pools，测试结束时关闭
+func newTestService(t *testing.T, cfg *config.Config) *_GRPCService {
+	pools, err := NewPoolRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewPoolRegistry() error = %v", err)
+	}
+	Pools = pools
+	t.Cleanup(func() {
+		pools.Close()
+		Pools = nil
+	})
+	return &_GRPCService{}
+}
+
+func TestAllocateRejectsCombinedOptions(t *testing.T) {
+	s := newTestService(t, newTestConfig(nil))
+	ctx := context.Background()
+
+	for _, req := range []*pb.AllocateRequest{
+		{Size: 8192, TtlSec: 60, Alignment: 8192},
+		{Size: 8192, TtlSec: 60, Policy: pb.PlacementPolicy_FIRST_FIT},
+		{Size: 8192, SessionId: 1, Alignment: 8192},
+	} {
+		if _, err := s.Allocate(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Allocate(%v) error = %v, want %v", req, err, codes.InvalidArgument)
+		}
+	}
+	if got := Pools.pools[DefaultPoolID].store.GetAllocationCount(); got != 0 {
+		t.Errorf("allocations after rejected requests = %d, want 0", got)
+	}
+
+	if _, err := s.Allocate(ctx, &pb.AllocateRequest{Size: 8192, TtlSec: 60}); err != nil {
+		t.Errorf("Allocate() with ttl_sec error = %v", err)
+	}
+	if _, err := s.Allocate(ctx, &pb.AllocateRequest{Size: 8192, Alignment: 8192}); err != nil {
+		t.Errorf("Allocate() with alignment error = %v", err)
+	}
+}
+
+func TestTieredAllocateSpillsAboveWatermark(t *testing.T) {
+	s := newTestService(t, newTestConfig(func(cfg *config.Config) { cfg.Tiers = "fast:0.5,slow" }))
+	ctx := context.Background()
+	for _, id := range []string{"fast", "slow"} {
+		if _, err := Pools.Create(PoolSpec{ID: id, TotalSize: 16 * 1024 * 1024}); err != nil {
+			t.Fatalf("Create(%s) error = %v", id, err)
+		}
+	}
+
+	// 连续的分配之间不等待，水位线判断也要看到之前的分配
+	const size = 1024 * 1024
+	for i := 0; ; i++ {
+		resp, err := s.Allocate(ctx, &pb.AllocateRequest{Size: size, Tiered: true})
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if resp.Tier == "slow" {
+			break
+		}
+		if resp.Tier != "fast" || i >= 16 {
+			t.Fatalf("Allocate() %d tier = %s, want fast until the watermark", i, resp.Tier)
+		}
+	}
+	util, err := s.GetDiskUtilization(ctx, &pb.GetDiskUtilizationRequest{PoolId: "fast"})
+	if err != nil {
+		t.Fatalf("GetDiskUtilization() error = %v", err)
+	}
+	if util.Utilization > 0.5 {
+		t.Errorf("fast tier utilization = %v, want at most the 0.5 watermark", util.Utilization)
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/li1213987842/spaceweave/config"
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+// DefaultPoolID 是启动配置对应的存储池，pool_id 为空的请求使用该存储池
+const DefaultPoolID = "default"
+
+var (
+	ErrPoolNotFound  = errors.New("pool not found")
+	ErrPoolExists    = errors.New("pool already exists")
+	ErrInvalidPool   = errors.New("invalid pool")
+	ErrDefaultPool   = errors.New("default pool cannot be deleted")
+	ErrPoolsClosed   = errors.New("pool registry is closed")
+	validPoolPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+)
+
+// PoolSpec 描述一个存储池，运行时创建的存储池保存在登记文件中，重启后重新加载
+type PoolSpec struct {
+	ID                   string `json:"id"`
+	UnitSize             uint64 `json:"unit_size"`
+	TotalSize            uint64 `json:"total_size"` // 创建时的大小，在线调整后的大小记录在状态文件中
+	StatePersistencePath string `json:"state_persistence_path"`
+}
+
+type pool struct {
+	spec  PoolSpec
+	store allocator.DiskAllocator
+	// active 统计正在使用 store 的请求，删除存储池时等待它们结束后再关闭
+	active sync.WaitGroup
+}
+
+// PoolRegistry 管理同一进程中的多个存储池，每个存储池有独立的配置、地址空间和状态文件
+type PoolRegistry struct {
+	mu    sync.RWMutex
+	base  *config.Config
+	path  string // 登记文件，为空时运行时创建的存储池不持久化
+	pools map[string]*pool
+	tiers []tierSpec // 分层分配的各层，按优先级排列
+	// busy 记录正在打开、尚未加入 pools 或已移出 pools、正在关闭的存储池，打开和关闭期间不持锁，
+	// 相同 ID 或持久化路径的创建据此拒绝，避免同一个状态文件被两个分配器同时使用
+	busy   map[string]PoolSpec
+	closed bool
+}
+
+// NewPoolRegistry 按 base 打开默认存储池，再打开登记文件中记录的全部存储池
+func NewPoolRegistry(base *config.Config) (*PoolRegistry, error) {
+	r := &PoolRegistry{
+		base:  base,
+		path:  base.PoolRegistryPath,
+		pools: make(map[string]*pool),
+		busy:  make(map[string]PoolSpec),
+	}
+	if r.path == "" && base.StatePersistencePath != "" {
+		r.path = base.StatePersistencePath + ".pools"
+	}
+	tiers, err := parseTiers(base.Tiers)
+	if err != nil {
+		return nil, err
+	}
+	r.tiers = tiers
+
+	store, err := allocator.LoadState(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pool %s: %w", DefaultPoolID, err)
+	}
+	r.pools[DefaultPoolID] = &pool{
+		spec:  PoolSpec{ID: DefaultPoolID, UnitSize: base.UnitSize, TotalSize: base.TotalSize, StatePersistencePath: base.StatePersistencePath},
+		store: store,
+	}
+
+	specs, err := r.load()
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	for _, spec := range specs {
+		p, err := r.open(spec)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to load pool %s: %w", spec.ID, err)
+		}
+		r.pools[spec.ID] = p
+	}
+	return r, nil
+}
+
+// Create 创建并打开一个存储池，持久化路径下已有状态时按状态文件恢复
+func (r *PoolRegistry) Create(spec PoolSpec) (PoolSpec, error) {
+	if !validPoolPattern.MatchString(spec.ID) || spec.ID == DefaultPoolID {
+		return PoolSpec{}, fmt.Errorf("%w: id %q", ErrInvalidPool, spec.ID)
+	}
+	if spec.UnitSize == 0 {
+		spec.UnitSize = r.base.UnitSize
+	}
+	if spec.StatePersistencePath == "" && r.base.StatePersistencePath != "" {
+		spec.StatePersistencePath = r.base.StatePersistencePath + "." + spec.ID
+	}
+
+	// 加载状态文件可能较慢，先登记再在锁外打开，期间不阻塞其他存储池上的请求
+	if err := r.reserve(spec); err != nil {
+		return PoolSpec{}, err
+	}
+	p, err := r.open(spec)
+
+	r.mu.Lock()
+	delete(r.busy, spec.ID)
+	if err != nil {
+		r.mu.Unlock()
+		return PoolSpec{}, err
+	}
+	if r.closed {
+		r.mu.Unlock()
+		p.store.Close()
+		return PoolSpec{}, ErrPoolsClosed
+	}
+	r.pools[spec.ID] = p
+	if err := r.save(); err != nil {
+		delete(r.pools, spec.ID)
+		r.mu.Unlock()
+		p.store.Close()
+		return PoolSpec{}, err
+	}
+	r.mu.Unlock()
+	log.Printf("created pool %s: unit size %d, total size %d, state %q", spec.ID, spec.UnitSize, spec.TotalSize, spec.StatePersistencePath)
+	return spec, nil
+}
+
+// reserve 检查 spec 的 ID 和持久化路径未被已有、正在创建或正在删除的存储池占用，并将其记入 busy
+func (r *PoolRegistry) reserve(spec PoolSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrPoolsClosed
+	}
+	if _, ok := r.pools[spec.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrPoolExists, spec.ID)
+	}
+	if _, ok := r.busy[spec.ID]; ok {
+		return fmt.Errorf("%w: %s is being created or deleted", ErrPoolExists, spec.ID)
+	}
+	if spec.StatePersistencePath != "" {
+		for _, p := range r.pools {
+			if p.spec.StatePersistencePath == spec.StatePersistencePath {
+				return fmt.Errorf("%w: state path %s is used by pool %s", ErrInvalidPool, spec.StatePersistencePath, p.spec.ID)
+			}
+		}
+		for id, other := range r.busy {
+			if other.StatePersistencePath == spec.StatePersistencePath {
+				return fmt.Errorf("%w: state path %s is used by pool %s", ErrInvalidPool, spec.StatePersistencePath, id)
+			}
+		}
+	}
+	r.busy[spec.ID] = spec
+	return nil
+}
+
+// open 按 spec 加载存储池，不加入登记表
+func (r *PoolRegistry) open(spec PoolSpec) (*pool, error) {
+	cfg, err := r.base.ForPool(spec.UnitSize, spec.TotalSize, spec.StatePersistencePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPool, err)
+	}
+	store, err := allocator.LoadState(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &pool{spec: spec, store: store}, nil
+}
+
+// Delete 关闭并移除存储池，状态文件保留在磁盘上，以相同的路径重新创建即可恢复。
+// 存储池中仍有已分配的空间时，除非 force 为 true，否则拒绝删除；默认存储池和作为分层分配中一层的存储池不能删除
+func (r *PoolRegistry) Delete(id string, force bool) error {
+	if id == DefaultPoolID {
+		return ErrDefaultPool
+	}
+	if r.isTier(id) {
+		return fmt.Errorf("%w: %s", ErrTierPool, id)
+	}
+	r.mu.Lock()
+	p, ok := r.pools[id]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrPoolNotFound, id)
+	}
+	if !force && p.store.GetAllocationCount() > 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("pool %s: %w", id, allocator.ErrNotEmpty)
+	}
+	delete(r.pools, id)
+	if err := r.save(); err != nil {
+		r.pools[id] = p
+		r.mu.Unlock()
+		return err
+	}
+	r.busy[id] = p.spec
+	r.mu.Unlock()
+
+	// 已不在登记表中，不会再有新的请求使用该存储池；关闭完成前不能以相同的 ID 或路径重新创建
+	p.active.Wait()
+	err := p.store.Close()
+	r.mu.Lock()
+	delete(r.busy, id)
+	r.mu.Unlock()
+	log.Printf("deleted pool %s", id)
+	return err
+}
+
+// List 返回全部存储池，按 ID 排序
+func (r *PoolRegistry) List() []PoolSpec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	specs := make([]PoolSpec, 0, len(r.pools))
+	for _, p := range r.pools {
+		specs = append(specs, p.spec)
+	}
+	slices.SortFunc(specs, func(a, b PoolSpec) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return specs
+}
+
+// poolID 返回请求中的 pool_id 实际指向的存储池，为空时为默认存储池
+func poolID(id string) string {
+	if id == "" {
+		return DefaultPoolID
+	}
+	return id
+}
+
+// acquire 返回 id 对应的分配器，id 为空时使用默认存储池。请求结束后调用 release，期间存储池不会被关闭
+func (r *PoolRegistry) acquire(id string) (store allocator.DiskAllocator, release func(), err error) {
+	id = poolID(id)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.pools[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrPoolNotFound, id)
+	}
+	p.active.Add(1)
+	return p.store, p.active.Done, nil
+}
+
+// with 在 id 对应的分配器上执行 fn
+func (r *PoolRegistry) with(id string, fn func(store allocator.DiskAllocator) error) error {
+	store, release, err := r.acquire(id)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(store)
+}
+
+// Close 关闭全部存储池。与 Delete 相同，先在锁内移出全部存储池，再在锁外等待正在进行的请求，
+// 分层分配的请求可能持有一层的计数并等待锁以获取下一层
+func (r *PoolRegistry) Close() error {
+	r.mu.Lock()
+	pools := r.pools
+	r.pools = make(map[string]*pool)
+	r.closed = true
+	r.mu.Unlock()
+
+	var errs []error
+	for id, p := range pools {
+		p.active.Wait()
+		if err := p.store.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("pool %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load 读取登记文件，文件不存在时返回空列表
+func (r *PoolRegistry) load() ([]PoolSpec, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var specs []PoolSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse pool registry %s: %w", r.path, err)
+	}
+	return specs, nil
+}
+
+// save 以写临时文件再重命名的方式原子地更新登记文件，默认存储池不写入，调用方需持有写锁
+func (r *PoolRegistry) save() error {
+	if r.path == "" {
+		return nil
+	}
+	specs := make([]PoolSpec, 0, len(r.pools))
+	for _, p := range r.pools {
+		if p.spec.ID != DefaultPoolID {
+			specs = append(specs, p.spec)
+		}
+	}
+	slices.SortFunc(specs, func(a, b PoolSpec) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	data, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(r.path), "temp_pools_*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write pool registry: %w", err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to sync pool registry: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFilePath, r.path); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+// persistent 让默认存储池及运行时创建的存储池的状态保存在 dir 中
+func persistent(dir string) func(cfg *config.Config) {
+	return func(cfg *config.Config) { cfg.StatePersistencePath = filepath.Join(dir, "state") }
+}
+
+func TestCreateRacesDelete(t *testing.T) {
+	newTestService(t, newTestConfig(persistent(t.TempDir())))
+	spec := PoolSpec{ID: "p", TotalSize: 16 * 1024 * 1024}
+	if _, err := Pools.Create(spec); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	allocated := 0
+	for i := 0; i < 20; i++ {
+		if err := Pools.with("p", func(store allocator.DiskAllocator) error {
+			_, err := store.Allocate(4096)
+			return err
+		}); err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		allocated++
+
+		var deleteErr, createErr error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			deleteErr = Pools.Delete("p", true)
+		}()
+		go func() {
+			defer wg.Done()
+			_, createErr = Pools.Create(spec)
+		}()
+		wg.Wait()
+		if deleteErr != nil {
+			t.Fatalf("Delete() error = %v", deleteErr)
+		}
+		if createErr != nil && !errors.Is(createErr, ErrPoolExists) {
+			t.Fatalf("Create() racing Delete() error = %v, want nil or %v", createErr, ErrPoolExists)
+		}
+		// 创建在删除之前完成时被拒绝，此时重新创建
+		if createErr != nil {
+			if _, err := Pools.Create(spec); err != nil {
+				t.Fatalf("Create() after Delete() error = %v", err)
+			}
+		}
+
+		// 重新创建的存储池加载的是删除时关闭写入的状态
+		if err := Pools.with("p", func(store allocator.DiskAllocator) error {
+			if got := store.GetAllocationCount(); got != allocated {
+				t.Fatalf("allocations after recreate = %d, want %d", got, allocated)
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("with() error = %v", err)
+		}
+	}
+}
+
+func TestCloseDoesNotWaitUnderLock(t *testing.T) {
+	r, err := NewPoolRegistry(newTestConfig(persistent(t.TempDir())))
+	if err != nil {
+		t.Fatalf("NewPoolRegistry() error = %v", err)
+	}
+	if _, err := r.Create(PoolSpec{ID: "b", TotalSize: 16 * 1024 * 1024}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// 模拟分层分配的请求：持有第一层的计数，再获取下一层
+	_, release, err := r.acquire(DefaultPoolID)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	closed := make(chan error, 1)
+	go func() { closed <- r.Close() }()
+	time.Sleep(50 * time.Millisecond)
+
+	acquired := make(chan error, 1)
+	go func() {
+		_, done, err := r.acquire("b")
+		if err == nil {
+			done()
+		}
+		acquired <- err
+	}()
+	select {
+	case err := <-acquired:
+		if !errors.Is(err, ErrPoolNotFound) {
+			t.Errorf("acquire() during Close() error = %v, want %v", err, ErrPoolNotFound)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire() blocked by Close()")
+	}
+	release()
+	if err := <-closed; err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := r.Create(PoolSpec{ID: "c", TotalSize: 16 * 1024 * 1024}); !errors.Is(err, ErrPoolsClosed) {
+		t.Errorf("Create() after Close() error = %v, want %v", err, ErrPoolsClosed)
+	}
+}
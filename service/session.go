@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/li1213987842/spaceweave/internal/allocator"
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+const defaultSessionTTL = 30 * time.Second
+
+// OpenSession 创建（或重新绑定）一个会话并保持流打开，期间定期续期；
+// 流结束即视为客户端断开，关闭会话并释放其未提交的分配。
+// 会话属于请求指定的存储池，流打开期间只在每次操作时使用存储池，不阻止存储池被删除
+func (s *_GRPCService) OpenSession(req *pb.OpenSessionRequest, stream pb.DiskAllocator_OpenSessionServer) error {
+	ttl := time.Duration(req.TtlSec) * time.Second
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+
+	id := req.SessionId
+	err := Pools.with(req.PoolId, func(store allocator.DiskAllocator) (err error) {
+		if id == 0 {
+			id, err = store.OpenSession(ttl)
+		} else {
+			err = store.KeepAliveSession(id)
+		}
+		return err
+	})
+	if err != nil {
+		return toStatusError(err)
+	}
+	closeSession := func() {
+		Pools.with(req.PoolId, func(store allocator.DiskAllocator) error {
+			return store.CloseSession(id)
+		})
+	}
+	if err := stream.Send(&pb.SessionEvent{SessionId: id}); err != nil {
+		closeSession()
+		return err
+	}
+
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := Pools.with(req.PoolId, func(store allocator.DiskAllocator) error {
+				return store.KeepAliveSession(id)
+			}); err != nil {
+				return toStatusError(err)
+			}
+		case <-stream.Context().Done():
+			closeSession()
+			return nil
+		}
+	}
+}
+
+func (s *_GRPCService) Commit(ctx context.Context, req *pb.CommitRequest) (resp *pb.CommitResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return &pb.CommitResponse{}, toStatusError(store.Commit(req.SessionId, req.Address, req.Size))
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+const (
+	streamWorkers   = 8   // 每个流并发处理命令的 goroutine 数
+	streamQueueSize = 256 // 每个流最多积压的命令数，队列满时停止读取以反压客户端
+)
+
+// AllocateStream 在同一个双向流上接收 allocate/free 命令并返回结果。
+// 命令由多个 worker 并发处理，响应顺序不保证与请求一致，客户端通过 id 匹配
+func (s *_GRPCService) AllocateStream(stream pb.DiskAllocator_AllocateStreamServer) error {
+	ctx := stream.Context()
+	reqs := make(chan *pb.StreamRequest, streamQueueSize)
+	resps := make(chan *pb.StreamResponse, streamQueueSize)
+
+	var wg sync.WaitGroup
+	for i := 0; i < streamWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range reqs {
+				resps <- s.handleStreamRequest(ctx, req)
+			}
+		}()
+	}
+
+	sendErr := make(chan error, 1)
+	go func() {
+		var err error
+		for resp := range resps {
+			if err != nil {
+				continue // 发送失败后继续消费，避免 worker 阻塞
+			}
+			err = stream.Send(resp)
+		}
+		sendErr <- err
+	}()
+
+	recvErr := s.recvStreamRequests(ctx, stream, reqs)
+	close(reqs)
+	wg.Wait()
+	close(resps)
+
+	if err := <-sendErr; err != nil {
+		return err
+	}
+	return recvErr
+}
+
+func (s *_GRPCService) recvStreamRequests(ctx context.Context, stream pb.DiskAllocator_AllocateStreamServer, reqs chan<- *pb.StreamRequest) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case reqs <- req:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *_GRPCService) handleStreamRequest(ctx context.Context, req *pb.StreamRequest) *pb.StreamResponse {
+	resp := &pb.StreamResponse{Id: req.Id}
+	switch cmd := req.Command.(type) {
+	case *pb.StreamRequest_Allocate:
+		r, err := s.Allocate(ctx, cmd.Allocate)
+		resp.Status = toItemStatus(err)
+		if err == nil {
+			resp.Address = r.Address
+		}
+	case *pb.StreamRequest_Free:
+		_, err := s.Free(ctx, cmd.Free)
+		resp.Status = toItemStatus(err)
+	default:
+		resp.Status = toItemStatus(status.Errorf(codes.InvalidArgument, "Invalid Argument: empty command"))
+	}
+	return resp
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/li1213987842/spaceweave/internal/allocator"
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+// TenantMetadataKey 是携带租户 ID 的 metadata 键，请求中的 tenant_id 优先
+const TenantMetadataKey = "x-tenant-id"
+
+// tenantID 返回请求所属的租户，为空时不计配额
+func tenantID(ctx context.Context, field string) string {
+	if field != "" {
+		return field
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, TenantMetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// withTenant 包装 alloc：经由分配器的租户视图分配，检查硬配额、计入用量和检查预留在同一次加锁中完成，
+// 成功后记录归属，失败时归还用量。超过硬配额时返回 ErrQuotaExceeded；分配后超过软配额时将 overSoft 置为 true
+func withTenant(tenant string, overSoft *bool, alloc func(store allocator.DiskAllocator) (uint64, error)) func(store allocator.DiskAllocator) (uint64, error) {
+	if tenant == "" {
+		return alloc
+	}
+	return func(store allocator.DiskAllocator) (uint64, error) {
+		view := store.ForTenant(tenant)
+		addr, err := alloc(view)
+		if err == nil && view.OverSoftQuota() {
+			*overSoft = true
+			log.Printf("tenant %s is over its soft quota", tenant)
+		}
+		return addr, err
+	}
+}
+
+// forTenant 返回以 tenant 的名义分配的存储池，tenant 为空时返回 store 本身
+func forTenant(store allocator.DiskAllocator, tenant string) allocator.DiskAllocator {
+	if tenant == "" {
+		return store
+	}
+	return store.ForTenant(tenant)
+}
+
+func toPBTenantUsage(u allocator.TenantUsage) *pb.TenantUsage {
+	return &pb.TenantUsage{TenantId: u.Tenant, Used: u.Used, SoftLimit: u.SoftLimit, HardLimit: u.HardLimit, Reserved: u.Reserved}
+}
+
+func (s *_GRPCService) SetTenantQuota(ctx context.Context, req *pb.SetTenantQuotaRequest) (resp *pb.SetTenantQuotaResponse, err error) {
+	if req.TenantId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: tenant_id is empty")
+	}
+	if req.SoftLimit > 0 && req.HardLimit > 0 && req.SoftLimit > req.HardLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: soft limit %d exceeds hard limit %d", req.SoftLimit, req.HardLimit)
+	}
+	if req.HardLimit > 0 && req.Reserved > req.HardLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid Argument: reserved %d exceeds hard limit %d", req.Reserved, req.HardLimit)
+	}
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := store.SetTenantQuota(allocator.TenantQuota{Tenant: req.TenantId, SoftLimit: req.SoftLimit, HardLimit: req.HardLimit, Reserved: req.Reserved}); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.SetTenantQuotaResponse{Tenant: toPBTenantUsage(store.GetTenantUsage(req.TenantId)[0])}, nil
+}
+
+func (s *_GRPCService) GetTenantUsage(ctx context.Context, req *pb.GetTenantUsageRequest) (resp *pb.GetTenantUsageResponse, err error) {
+	store, release, err := poolStore(req.PoolId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	usage := store.GetTenantUsage(req.TenantId)
+	tenants := make([]*pb.TenantUsage, 0, len(usage))
+	for _, u := range usage {
+		tenants = append(tenants, toPBTenantUsage(u))
+	}
+	return &pb.GetTenantUsageResponse{Tenants: tenants}, nil
+}
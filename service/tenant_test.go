@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/li1213987842/spaceweave/proto"
+)
+
+func tenantUsed(t *testing.T, s *_GRPCService, tenant string) uint64 {
+	t.Helper()
+	resp, err := s.GetTenantUsage(context.Background(), &pb.GetTenantUsageRequest{TenantId: tenant})
+	if err != nil {
+		t.Fatalf("GetTenantUsage() error = %v", err)
+	}
+	return resp.Tenants[0].Used
+}
+
+func TestQuotaRefusalUncharges(t *testing.T) {
+	s := newTestService(t, newTestConfig(nil))
+	ctx := context.Background()
+	if _, err := s.SetTenantQuota(ctx, &pb.SetTenantQuotaRequest{TenantId: "a", HardLimit: 16 * 4096}); err != nil {
+		t.Fatalf("SetTenantQuota() error = %v", err)
+	}
+	if _, err := s.Allocate(ctx, &pb.AllocateRequest{Size: 8 * 4096, TenantId: "a"}); err != nil {
+		t.Fatalf("Allocate() within quota error = %v", err)
+	}
+
+	// 被拒绝的请求不能留下用量
+	if _, err := s.Allocate(ctx, &pb.AllocateRequest{Size: 9 * 4096, TenantId: "a"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Allocate() above hard limit error = %v, want %v", err, codes.ResourceExhausted)
+	}
+	if _, err := s.AllocateExtents(ctx, &pb.AllocateExtentsRequest{Size: 9 * 4096, TenantId: "a"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("AllocateExtents() above hard limit error = %v, want %v", err, codes.ResourceExhausted)
+	}
+	resp, err := s.BatchAllocate(ctx, &pb.BatchAllocateRequest{Sizes: []uint64{4 * 4096, 8 * 4096}, TenantId: "a"})
+	if err != nil {
+		t.Fatalf("BatchAllocate() error = %v", err)
+	}
+	if code := codes.Code(resp.Results[1].Status.GetCode()); code != codes.ResourceExhausted {
+		t.Errorf("BatchAllocate() item above hard limit code = %v, want %v", code, codes.ResourceExhausted)
+	}
+	if got := tenantUsed(t, s, "a"); got != 12*4096 {
+		t.Errorf("usage after refusals = %d, want %d", got, 12*4096)
+	}
+	if _, err := s.Allocate(ctx, &pb.AllocateRequest{Size: 4 * 4096, TenantId: "a"}); err != nil {
+		t.Errorf("Allocate() up to hard limit error = %v", err)
+	}
+	if got := tenantUsed(t, s, "a"); got != 16*4096 {
+		t.Errorf("usage at hard limit = %d, want %d", got, 16*4096)
+	}
+}
+
+func TestResizeMovesWithTenant(t *testing.T) {
+	s := newTestService(t, newTestConfig(nil))
+	ctx := context.Background()
+	alloc, err := s.Allocate(ctx, &pb.AllocateRequest{Size: 4 * 4096, TenantId: "a"})
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	// 占住紧随其后的单元，扩大只能迁移到新地址
+	if _, err := s.Reserve(ctx, &pb.ReserveRequest{Address: alloc.Address + 4*4096, Size: 4096}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	resp, err := s.Resize(ctx, &pb.ResizeRequest{Address: alloc.Address, OldSize: 4 * 4096, NewSize: 8*4096 - 100, TenantId: "a"})
+	if err != nil {
+		t.Fatalf("Resize() error = %v", err)
+	}
+	if !resp.Moved || resp.Address == alloc.Address {
+		t.Fatalf("Resize() = %+v, want moved to a new address", resp)
+	}
+	// 调用方释放旧空间之前新旧两段都计入用量
+	if got := tenantUsed(t, s, "a"); got != 12*4096 {
+		t.Errorf("usage before freeing the old extent = %d, want %d", got, 12*4096)
+	}
+	if _, err := s.Free(ctx, &pb.FreeRequest{Address: alloc.Address, Size: 4 * 4096}); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if got := tenantUsed(t, s, "a"); got != 8*4096 {
+		t.Errorf("usage after freeing the old extent = %d, want %d", got, 8*4096)
+	}
+	if _, err := s.Free(ctx, &pb.FreeRequest{Address: resp.Address, Size: 8*4096 - 100}); err != nil {
+		t.Fatalf("Free() error = %v", err)
+	}
+	if got := tenantUsed(t, s, "a"); got != 0 {
+		t.Errorf("usage after freeing both extents = %d, want 0", got)
+	}
+}
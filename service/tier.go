@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+var (
+	ErrNoTiers  = errors.New("tiered allocation is not configured")
+	ErrTierPool = errors.New("pool is used as a storage tier")
+)
+
+// tierSpec 是 TIERS 配置中的一项，watermark 为 0 时不设水位线
+type tierSpec struct {
+	pool      string
+	watermark float64
+}
+
+// parseTiers 解析 TIERS 配置，例如 "nvme:0.85,hdd"，列出的顺序即优先级
+func parseTiers(s string) ([]tierSpec, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var tiers []tierSpec
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		pool, mark, hasMark := strings.Cut(strings.TrimSpace(item), ":")
+		if !validPoolPattern.MatchString(pool) {
+			return nil, fmt.Errorf("TIERS: invalid pool %q", pool)
+		}
+		if seen[pool] {
+			return nil, fmt.Errorf("TIERS: pool %s listed more than once", pool)
+		}
+		seen[pool] = true
+		tier := tierSpec{pool: pool}
+		if hasMark {
+			watermark, err := strconv.ParseFloat(mark, 64)
+			if err != nil || watermark <= 0 || watermark > 1 {
+				return nil, fmt.Errorf("TIERS: watermark of pool %s must be in (0, 1], got %q", pool, mark)
+			}
+			tier.watermark = watermark
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// isTier 判断存储池是否为分层分配中的一层
+func (r *PoolRegistry) isTier(id string) bool {
+	for _, t := range r.tiers {
+		if t.pool == id {
+			return true
+		}
+	}
+	return false
+}
+
+// acquireTiers 按优先级返回各层的分配器，请求结束后调用 release。
+// 作为层的存储池不能删除，但可能尚未创建，此时返回 ErrPoolNotFound
+func (r *PoolRegistry) acquireTiers() (*allocator.TieredAllocator, func(), error) {
+	if len(r.tiers) == 0 {
+		return nil, nil, ErrNoTiers
+	}
+	tiers := make([]allocator.Tier, 0, len(r.tiers))
+	releases := make([]func(), 0, len(r.tiers))
+	release := func() {
+		for _, fn := range releases {
+			fn()
+		}
+	}
+	for _, t := range r.tiers {
+		store, done, err := r.acquire(t.pool)
+		if err != nil {
+			release()
+			return nil, nil, fmt.Errorf("tier %s: %w", t.pool, err)
+		}
+		releases = append(releases, done)
+		tiers = append(tiers, allocator.Tier{Name: t.pool, Allocator: store, HighWatermark: t.watermark})
+	}
+	return allocator.NewTieredAllocator(tiers), release, nil
+}
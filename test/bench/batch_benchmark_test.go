@@ -0,0 +1,153 @@
+package bench
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/li1213987842/spaceweave/client"
+	"github.com/li1213987842/spaceweave/config"
+	pb "github.com/li1213987842/spaceweave/proto"
+	"github.com/li1213987842/spaceweave/service"
+)
+
+const batchRequestSize = 4 * 1024 // 4 KiB，对应大量小块分配的场景
+
+// startBenchServer 在随机端口上启动 spaceweave gRPC 服务并返回连接好的客户端
+func startBenchServer(b *testing.B) (client.DiskAllocatorClient, func()) {
+	totalSize := uint64(64 * 1024 * 1024 * 1024) // 64 GiB
+	cfg := &config.Config{
+		TotalSize:       totalSize,
+		UnitSize:        4 * 1024,
+		NumShards:       256,
+		SmallBlockLimit: uint64(float64(totalSize)*0.1) / (4 * 1024),
+	}
+	pools, err := service.NewPoolRegistry(cfg)
+	if err != nil {
+		b.Fatalf("load pools: %v", err)
+	}
+	service.Pools = pools
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatalf("listen: %v", err)
+	}
+	gs := grpc.NewServer()
+	svc := &service.Service{}
+	if err := svc.Initialize(context.Background(), gs); err != nil {
+		b.Fatalf("initialize service: %v", err)
+	}
+	go gs.Serve(lis)
+
+	c, err := client.NewDiskAllocatorClient(context.Background(), lis.Addr().String())
+	if err != nil {
+		b.Fatalf("dial: %v", err)
+	}
+	return c, func() {
+		c.Close()
+		gs.Stop()
+		service.Pools.Close()
+	}
+}
+
+func BenchmarkUnaryAllocateFree(b *testing.B) {
+	c, stop := startBenchServer(b)
+	defer stop()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		addr, err := c.Allocate(ctx, batchRequestSize)
+		if err != nil {
+			b.Fatalf("Allocate: %v", err)
+		}
+		if err := c.Free(ctx, addr, batchRequestSize); err != nil {
+			b.Fatalf("Free: %v", err)
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "allocs/s")
+}
+
+func BenchmarkBatchAllocateFree(b *testing.B) {
+	for _, batch := range []int{16, 128, 1024} {
+		b.Run("batch_"+strconv.Itoa(batch), func(b *testing.B) {
+			c, stop := startBenchServer(b)
+			defer stop()
+			ctx := context.Background()
+
+			sizes := make([]uint64, batch)
+			for i := range sizes {
+				sizes[i] = batchRequestSize
+			}
+			extents := make([]*pb.Extent, batch)
+
+			b.ResetTimer()
+			for n := 0; n < b.N; n += batch {
+				addrs, errs, err := c.BatchAllocate(ctx, sizes)
+				if err != nil {
+					b.Fatalf("BatchAllocate: %v", err)
+				}
+				for i := range addrs {
+					if errs[i] != nil {
+						b.Fatalf("BatchAllocate item %d: %v", i, errs[i])
+					}
+					extents[i] = &pb.Extent{Address: addrs[i], Size: batchRequestSize}
+				}
+				if _, err := c.BatchFree(ctx, extents); err != nil {
+					b.Fatalf("BatchFree: %v", err)
+				}
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "allocs/s")
+		})
+	}
+}
+
+func BenchmarkStreamAllocateFree(b *testing.B) {
+	c, stop := startBenchServer(b)
+	defer stop()
+	ctx := context.Background()
+
+	stream, err := c.OpenAllocateStream(ctx)
+	if err != nil {
+		b.Fatalf("OpenAllocateStream: %v", err)
+	}
+
+	const window = 256 // 同时在途的命令数
+	futures := make([]*client.Future, 0, window)
+	b.ResetTimer()
+	for n := 0; n < b.N; n += window {
+		futures = futures[:0]
+		for i := 0; i < window; i++ {
+			f, err := stream.Allocate(batchRequestSize)
+			if err != nil {
+				b.Fatalf("Allocate: %v", err)
+			}
+			futures = append(futures, f)
+		}
+		frees := make([]*client.Future, 0, window)
+		for _, f := range futures {
+			addr, err := f.Wait(ctx)
+			if err != nil {
+				b.Fatalf("Allocate result: %v", err)
+			}
+			free, err := stream.Free(addr, batchRequestSize)
+			if err != nil {
+				b.Fatalf("Free: %v", err)
+			}
+			frees = append(frees, free)
+		}
+		for _, f := range frees {
+			if _, err := f.Wait(ctx); err != nil {
+				b.Fatalf("Free result: %v", err)
+			}
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "allocs/s")
+
+	if err := stream.Close(); err != nil {
+		b.Fatalf("Close: %v", err)
+	}
+}
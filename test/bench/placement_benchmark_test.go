@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+const (
+	placementTreeUnits   = 1 << 24 // 64 GiB（4KiB 单元）
+	placementMinUnits    = 64      // 256 KiB
+	placementMaxUnits    = 1024    // 4 MiB
+	placementLiveTarget  = 0.8     // 稳态时的空间利用率
+	placementChurnRounds = 200000
+)
+
+// runPlacementWorkload 先填充到目标利用率，再随机释放/分配以制造碎片
+func runPlacementWorkload(b *testing.B, policy allocator.PlacementPolicy) {
+	r := rand.New(rand.NewSource(42))
+	dm := allocator.NewBTreeManager(placementTreeUnits)
+	dm.SetPolicy(policy)
+
+	type extent struct{ start, size uint64 }
+	live := make([]extent, 0, 1<<16)
+	var used uint64
+	randomUnits := func() uint64 {
+		return placementMinUnits + uint64(r.Int63n(placementMaxUnits-placementMinUnits+1))
+	}
+
+	for float64(used) < placementLiveTarget*placementTreeUnits {
+		size := randomUnits()
+		start, err := dm.Allocate(size)
+		if err != nil {
+			break
+		}
+		live = append(live, extent{start, size})
+		used += size
+	}
+
+	var ops, failures int64
+	begin := time.Now()
+	for i := 0; i < placementChurnRounds; i++ {
+		if len(live) > 0 && (float64(used) >= placementLiveTarget*placementTreeUnits || r.Intn(2) == 0) {
+			idx := r.Intn(len(live))
+			e := live[idx]
+			live[idx] = live[len(live)-1]
+			live = live[:len(live)-1]
+			dm.Free(e.start, e.size)
+			used -= e.size
+		} else {
+			size := randomUnits()
+			start, err := dm.Allocate(size)
+			if err != nil {
+				failures++
+				continue
+			}
+			live = append(live, extent{start, size})
+			used += size
+		}
+		ops++
+	}
+	elapsed := time.Since(begin)
+
+	b.ReportMetric(float64(ops)/elapsed.Seconds(), "ops/s")
+	b.ReportMetric(dm.GetFragmentation()*100, "fragmentation_%")
+	b.ReportMetric(float64(failures), "alloc_failures")
+	b.ReportMetric(float64(used)/placementTreeUnits*100, "utilization_%")
+}
+
+func BenchmarkPlacementPolicies(b *testing.B) {
+	policies := []allocator.PlacementPolicy{
+		allocator.PolicyBestFit,
+		allocator.PolicyFirstFit,
+		allocator.PolicyNextFit,
+		allocator.PolicyWorstFit,
+	}
+
+	for _, p := range policies {
+		b.Run(p.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				runPlacementWorkload(b, p)
+			}
+		})
+	}
+}
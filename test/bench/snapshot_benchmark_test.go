@@ -0,0 +1,123 @@
+package bench
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/li1213987842/spaceweave/config"
+	"github.com/li1213987842/spaceweave/internal/allocator"
+)
+
+const (
+	snapshotTotalSize   = 64 * 1024 * 1024 * 1024 // 64 GiB
+	snapshotLiveExtents = 200000                  // 预先分配的区间数，使空闲树和位图都有一定规模
+	snapshotWorkers     = 8
+	snapshotOpsPerRound = 20000
+)
+
+// runAllocateLatency 并发执行分配/释放并统计单次分配的延迟，backup 为 true 时另有 goroutine 持续调用 SaveState
+func runAllocateLatency(b *testing.B, backup bool) {
+	cfg := &config.Config{
+		TotalSize:            snapshotTotalSize,
+		UnitSize:             4 * 1024,
+		NumShards:            256,
+		SmallBlockLimit:      snapshotTotalSize / 10 / (4 * 1024),
+		StatePersistencePath: b.TempDir() + "/state",
+		BackupIntervalSec:    3600,
+	}
+	da, err := allocator.LoadState(cfg)
+	if err != nil {
+		b.Fatalf("load state: %v", err)
+	}
+	defer da.Close()
+
+	// 交替分配大小块并释放一半，制造碎片
+	for i := 0; i < snapshotLiveExtents; i++ {
+		size := uint64(4096)
+		if i%2 == 1 {
+			size = 512 * 1024
+		}
+		addr, err := da.Allocate(size)
+		if err != nil {
+			b.Fatalf("prefill: %v", err)
+		}
+		if i%4 < 2 {
+			da.Free(addr, size)
+		}
+	}
+
+	var saves int64
+	stop := make(chan struct{})
+	var saver sync.WaitGroup
+	if backup {
+		saver.Add(1)
+		go func() {
+			defer saver.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if err := da.SaveState(); err != nil {
+					b.Errorf("SaveState: %v", err)
+					return
+				}
+				atomic.AddInt64(&saves, 1)
+			}
+		}()
+	}
+
+	latencies := make([][]time.Duration, snapshotWorkers)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var wg sync.WaitGroup
+		for w := 0; w < snapshotWorkers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < snapshotOpsPerRound; i++ {
+					size := uint64(4096)
+					if i%2 == 1 {
+						size = 1024 * 1024
+					}
+					begin := time.Now()
+					addr, err := da.Allocate(size)
+					latencies[w] = append(latencies[w], time.Since(begin))
+					if err != nil {
+						b.Errorf("Allocate: %v", err)
+						return
+					}
+					da.Free(addr, size)
+				}
+			}(w)
+		}
+		wg.Wait()
+	}
+	b.StopTimer()
+	close(stop)
+	saver.Wait()
+
+	var all []time.Duration
+	for _, l := range latencies {
+		all = append(all, l...)
+	}
+	slices.Sort(all)
+	percentile := func(p float64) float64 {
+		return float64(all[int(p*float64(len(all)-1))].Nanoseconds())
+	}
+	b.ReportMetric(percentile(0.50), "p50_ns")
+	b.ReportMetric(percentile(0.99), "p99_ns")
+	b.ReportMetric(percentile(0.999), "p999_ns")
+	b.ReportMetric(float64(all[len(all)-1].Nanoseconds()), "max_ns")
+	b.ReportMetric(float64(atomic.LoadInt64(&saves)), "saves")
+}
+
+// BenchmarkAllocateDuringSaveState 比较后台持续保存快照时与无备份时的分配延迟
+func BenchmarkAllocateDuringSaveState(b *testing.B) {
+	b.Run("idle", func(b *testing.B) { runAllocateLatency(b, false) })
+	b.Run("backup", func(b *testing.B) { runAllocateLatency(b, true) })
+}